@@ -0,0 +1,245 @@
+package elmobd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// AlertCondition represents how the value of a command is compared against
+// the threshold of an AlertRule.
+type AlertCondition int
+
+const (
+	// Above raises the alert when the value is above the threshold.
+	Above AlertCondition = iota
+	// Below raises the alert when the value is below the threshold.
+	Below
+)
+
+// String returns the condition as a comparison operator.
+func (cond AlertCondition) String() string {
+	if cond == Below {
+		return "<"
+	}
+
+	return ">"
+}
+
+// AlertRule represents a condition on the value of a command, such as the
+// coolant temperature being above 105 °C:
+//
+//	AlertRule{
+//		Name:       "engine overheating",
+//		Key:        "coolant_temperature",
+//		Condition:  Above,
+//		Threshold:  105,
+//		Hysteresis: 3,
+//	}
+//
+// The alert is raised when the value passes the threshold and is cleared when
+// the value has gone back past the threshold by more than Hysteresis. This
+// avoids a flood of alerts from a value hovering around the threshold.
+//
+// When MinInterval is set, the alert is not raised again until MinInterval
+// has passed since it was raised the last time.
+type AlertRule struct {
+	Name        string
+	Key         string
+	Condition   AlertCondition
+	Threshold   float64
+	Hysteresis  float64
+	MinInterval time.Duration
+}
+
+// String describes the rule, such as "engine overheating: coolant_temperature > 105".
+func (rule AlertRule) String() string {
+	return fmt.Sprintf("%s: %s %s %g", rule.Name, rule.Key, rule.Condition, rule.Threshold)
+}
+
+// AlertEvent represents an alert being raised or cleared.
+type AlertEvent struct {
+	Rule    AlertRule
+	Reading Reading
+	Raised  bool
+}
+
+// alertEventsBuffer is the size of the buffer of the channel returned by
+// Alerter.Events.
+const alertEventsBuffer = 16
+
+// Alerter evaluates alert rules against processed commands and notifies the
+// registered callbacks and the events channel when alerts are raised or
+// cleared.
+//
+// The Alerter does not poll the device itself, instead it is given the
+// processed commands using Observe from the loop or callback that polls the
+// device.
+//
+// An Alerter is safe to use from multiple goroutines.
+type Alerter struct {
+	mutex     sync.Mutex
+	rules     []*alertState
+	callbacks []func(AlertEvent)
+	events    chan AlertEvent
+	now       func() time.Time
+}
+
+// NewAlerter creates a new Alerter with the given rules.
+func NewAlerter(rules ...AlertRule) *Alerter {
+	alerter := &Alerter{now: time.Now}
+
+	for _, rule := range rules {
+		alerter.AddRule(rule)
+	}
+
+	return alerter
+}
+
+// AddRule adds the given rule to the rules evaluated.
+func (alerter *Alerter) AddRule(rule AlertRule) {
+	alerter.mutex.Lock()
+	defer alerter.mutex.Unlock()
+
+	alerter.rules = append(alerter.rules, &alertState{rule: rule})
+}
+
+// OnAlert registers a callback that is called with every event. The callbacks
+// are called from the goroutine calling Observe.
+func (alerter *Alerter) OnAlert(callback func(AlertEvent)) {
+	alerter.mutex.Lock()
+	defer alerter.mutex.Unlock()
+
+	alerter.callbacks = append(alerter.callbacks, callback)
+}
+
+// Events returns a channel that receives every event. The channel is
+// buffered, when the buffer is full new events are dropped rather than
+// blocking Observe.
+func (alerter *Alerter) Events() <-chan AlertEvent {
+	alerter.mutex.Lock()
+	defer alerter.mutex.Unlock()
+
+	if alerter.events == nil {
+		alerter.events = make(chan AlertEvent, alertEventsBuffer)
+	}
+
+	return alerter.events
+}
+
+// Active returns the rules of the alerts currently raised.
+func (alerter *Alerter) Active() []AlertRule {
+	alerter.mutex.Lock()
+	defer alerter.mutex.Unlock()
+
+	var active []AlertRule
+
+	for _, state := range alerter.rules {
+		if state.raised {
+			active = append(active, state.rule)
+		}
+	}
+
+	return active
+}
+
+// Observe evaluates the rules of the key of the given processed command
+// against its value. Commands without a numeric value are ignored.
+func (alerter *Alerter) Observe(cmd OBDCommand) {
+	reading := NewReading(cmd, alerter.now())
+	val, ok := reading.Float64()
+
+	if !ok {
+		return
+	}
+
+	alerter.mutex.Lock()
+
+	var events []AlertEvent
+
+	for _, state := range alerter.rules {
+		if state.rule.Key != reading.Key {
+			continue
+		}
+
+		if raised, changed := state.evaluate(val, reading.Time); changed {
+			events = append(events, AlertEvent{state.rule, reading, raised})
+		}
+	}
+
+	callbacks := alerter.callbacks
+	channel := alerter.events
+
+	alerter.mutex.Unlock()
+
+	for _, event := range events {
+		for _, callback := range callbacks {
+			callback(event)
+		}
+
+		if channel != nil {
+			select {
+			case channel <- event:
+			default:
+			}
+		}
+	}
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// alertState keeps track of whether the alert of a rule is raised.
+type alertState struct {
+	rule       AlertRule
+	raised     bool
+	lastRaised time.Time
+}
+
+// evaluate updates the state with the given value, it returns the new state
+// and whether the state changed.
+func (state *alertState) evaluate(val float64, at time.Time) (bool, bool) {
+	rule := state.rule
+
+	if state.raised {
+		var cleared bool
+
+		if rule.Condition == Above {
+			cleared = val < rule.Threshold-rule.Hysteresis
+		} else {
+			cleared = val > rule.Threshold+rule.Hysteresis
+		}
+
+		if cleared {
+			state.raised = false
+		}
+
+		return state.raised, cleared
+	}
+
+	var passed bool
+
+	if rule.Condition == Above {
+		passed = val > rule.Threshold
+	} else {
+		passed = val < rule.Threshold
+	}
+
+	if !passed {
+		return false, false
+	}
+
+	if rule.MinInterval > 0 && !state.lastRaised.IsZero() && at.Sub(state.lastRaised) < rule.MinInterval {
+		return false, false
+	}
+
+	state.raised = true
+	state.lastRaised = at
+
+	return true, true
+}
@@ -0,0 +1,83 @@
+package elmobd
+
+import (
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestAlerterHysteresis(t *testing.T) {
+	alerter := NewAlerter(AlertRule{
+		Name:       "overheating",
+		Key:        "coolant_temperature",
+		Condition:  Above,
+		Threshold:  105,
+		Hysteresis: 3,
+	})
+
+	var events []AlertEvent
+
+	alerter.OnAlert(func(event AlertEvent) {
+		events = append(events, event)
+	})
+
+	coolant := NewCoolantTemperature()
+
+	for _, temp := range []int{100, 106, 104, 107, 103, 101, 106} {
+		coolant.Value = temp
+		alerter.Observe(coolant)
+	}
+
+	assertEqual(t, len(events), 3)
+	assertEqual(t, events[0].Raised, true)
+	assertEqual(t, events[0].Reading.Value, 106)
+	assertEqual(t, events[1].Raised, false)
+	assertEqual(t, events[1].Reading.Value, 101)
+	assertEqual(t, events[2].Raised, true)
+	assertEqual(t, len(alerter.Active()), 1)
+
+	// Commands of other keys are ignored
+	alerter.Observe(NewVehicleSpeed())
+
+	assertEqual(t, len(events), 3)
+}
+
+func TestAlerterRateLimit(t *testing.T) {
+	alerter := NewAlerter(AlertRule{
+		Name:        "low voltage",
+		Key:         "control_module_voltage",
+		Condition:   Below,
+		Threshold:   11.8,
+		MinInterval: time.Minute,
+	})
+
+	at := time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC)
+
+	alerter.now = func() time.Time {
+		at = at.Add(10 * time.Second)
+
+		return at
+	}
+
+	events := alerter.Events()
+	voltage := NewControlModuleVoltage()
+
+	for _, val := range []float32{11.5, 12.5, 11.5, 12.5, 11.5, 11.5, 11.5, 11.5} {
+		voltage.Value = val
+		alerter.Observe(voltage)
+	}
+
+	raised := 0
+
+	for len(events) > 0 {
+		if event := <-events; event.Raised {
+			raised++
+		}
+	}
+
+	// Raised at 10s, suppressed at 30s, 50s and 60s, raised again at 70s
+	assertEqual(t, raised, 2)
+}
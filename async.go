@@ -0,0 +1,91 @@
+package elmobd
+
+import (
+	"context"
+	"sync"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// RunOBDCommandAsync queues the given command to be run on the device and
+// returns right away, such as for UI code that should not block while the
+// command is in flight. The result is sent on the returned channel once the
+// command has been run, the channel is buffered so the result may be read
+// whenever it suits, or not at all.
+//
+// The queued commands are run one after the other in the order they were
+// queued, by a single goroutine that lives as long as there are commands in
+// the queue.
+func (dev *Device) RunOBDCommandAsync(cmd OBDCommand) <-chan CommandResult {
+	result := make(chan CommandResult, 1)
+
+	dev.async.push(asyncJob{cmd, result}, dev.runAsync)
+
+	return result
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// asyncJob is a command queued with RunOBDCommandAsync.
+type asyncJob struct {
+	cmd    OBDCommand
+	result chan<- CommandResult
+}
+
+// asyncQueue keeps the commands queued with RunOBDCommandAsync, it is safe to
+// use from multiple goroutines.
+type asyncQueue struct {
+	mutex   sync.Mutex
+	jobs    []asyncJob
+	running bool
+}
+
+// push adds the given job to the queue and starts the given worker in a new
+// goroutine if it is not already running.
+func (queue *asyncQueue) push(job asyncJob, worker func()) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.jobs = append(queue.jobs, job)
+
+	if !queue.running {
+		queue.running = true
+
+		go worker()
+	}
+}
+
+// pop takes the next job from the queue, the second return value is false
+// when the queue is empty, which stops the worker.
+func (queue *asyncQueue) pop() (asyncJob, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if len(queue.jobs) == 0 {
+		queue.running = false
+
+		return asyncJob{}, false
+	}
+
+	job := queue.jobs[0]
+	queue.jobs = queue.jobs[1:]
+
+	return job, true
+}
+
+// runAsync runs the queued commands until the queue is empty.
+func (dev *Device) runAsync() {
+	for {
+		job, ok := dev.async.pop()
+
+		if !ok {
+			return
+		}
+
+		job.result <- dev.RunOBDCommandResult(context.Background(), job.cmd)
+	}
+}
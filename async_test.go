@@ -0,0 +1,75 @@
+package elmobd
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestRunOBDCommandAsync(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	speed := dev.RunOBDCommandAsync(NewVehicleSpeed())
+	unsupported := dev.RunOBDCommandAsync(NewTimeWithMILOn())
+	rpm := dev.RunOBDCommandAsync(NewEngineRPM())
+
+	res := <-rpm
+
+	assertSuccess(t, res.Err)
+	assertEqual(t, res.Command.(*EngineRPM).Value, float32(192))
+
+	res = <-unsupported
+
+	assert(t, res.Err != nil, "Expected unsupported command to fail")
+
+	res = <-speed
+
+	assertSuccess(t, res.Err)
+	assertEqual(t, res.Command.(*VehicleSpeed).Value, uint32(75))
+	assertEqual(t, dev.Stats().CommandsRun, uint64(3))
+}
+
+// recordingDevice records the commands run on it, it is safe to use from
+// multiple goroutines.
+type recordingDevice struct {
+	MockDevice
+	mutex    sync.Mutex
+	commands []string
+}
+
+func (dev *recordingDevice) RunCommand(command string) RawResult {
+	dev.mutex.Lock()
+	dev.commands = append(dev.commands, command)
+	dev.mutex.Unlock()
+
+	return dev.MockDevice.RunCommand(command)
+}
+
+func TestRunOBDCommandAsyncOrder(t *testing.T) {
+	raw := &recordingDevice{}
+	dev := &Device{rawDevice: raw}
+
+	var results []<-chan CommandResult
+
+	for i := 0; i < 3; i++ {
+		results = append(results, dev.RunOBDCommandAsync(NewVehicleSpeed()))
+		results = append(results, dev.RunOBDCommandAsync(NewEngineRPM()))
+	}
+
+	for _, result := range results {
+		assertSuccess(t, (<-result).Err)
+	}
+
+	raw.mutex.Lock()
+	defer raw.mutex.Unlock()
+
+	assertEqual(
+		t,
+		fmt.Sprint(raw.commands),
+		"[010D1 010C1 010D1 010C1 010D1 010C1]",
+	)
+}
@@ -0,0 +1,300 @@
+package elmobd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// BatteryState represents what the voltage of the battery tells about the
+// electrical system of the vehicle, see BatteryMonitor.
+type BatteryState int
+
+const (
+	// BatteryUnknown means no voltage has been observed yet.
+	BatteryUnknown BatteryState = iota
+	// BatteryResting means the battery is not charged, usually because the
+	// engine is off.
+	BatteryResting
+	// BatteryCharging means the alternator is charging the battery, which
+	// means the engine is running.
+	BatteryCharging
+	// BatteryCranking means the starter motor is drawing current from the
+	// battery.
+	BatteryCranking
+)
+
+// String returns the literal representation of the state.
+func (state BatteryState) String() string {
+	switch state {
+	case BatteryUnknown:
+		return "unknown"
+	case BatteryResting:
+		return "resting"
+	case BatteryCharging:
+		return "charging"
+	case BatteryCranking:
+		return "cranking"
+	}
+
+	return fmt.Sprintf("BatteryState(%d)", int(state))
+}
+
+// BatteryHealth represents the assessment of the health of the battery, see
+// BatteryMonitor.Report.
+type BatteryHealth int
+
+const (
+	// BatteryHealthUnknown means there are not enough observations to assess
+	// the health of the battery.
+	BatteryHealthUnknown BatteryHealth = iota
+	// BatteryHealthGood means the battery is healthy.
+	BatteryHealthGood
+	// BatteryHealthWeak means the battery is discharged or getting old.
+	BatteryHealthWeak
+	// BatteryHealthBad means the battery, or the charging system, needs
+	// attention.
+	BatteryHealthBad
+)
+
+// String returns the literal representation of the health.
+func (health BatteryHealth) String() string {
+	switch health {
+	case BatteryHealthUnknown:
+		return "unknown"
+	case BatteryHealthGood:
+		return "good"
+	case BatteryHealthWeak:
+		return "weak"
+	case BatteryHealthBad:
+		return "bad"
+	}
+
+	return fmt.Sprintf("BatteryHealth(%d)", int(health))
+}
+
+// BatterySample represents a voltage observed by a BatteryMonitor.
+type BatterySample struct {
+	Time    time.Time
+	Voltage float64
+}
+
+// CrankingDip represents the voltage dip while the engine was cranked.
+type CrankingDip struct {
+	Start    time.Time
+	Duration time.Duration
+	Minimum  float64
+}
+
+// BatteryReport represents the assessment of the battery made by a
+// BatteryMonitor. The voltages are zero when the state has not been observed.
+type BatteryReport struct {
+	State           BatteryState
+	Voltage         float64
+	RestingVoltage  float64
+	ChargingVoltage float64
+	LastCrank       *CrankingDip
+	Health          BatteryHealth
+	Reasons         []string
+}
+
+// BatteryMonitor keeps track of the voltage of the battery of a 12 V vehicle
+// over time, to detect when the engine is cranked, whether the alternator is
+// charging and to assess the health of the battery. It is meant for adapters
+// that are always connected, feeding it with the voltage at a regular interval
+// (see Device.SampleBattery). Cranking dips are only caught when sampling
+// several times per second.
+//
+// The assessment uses the usual rules of thumb for lead-acid batteries:
+//
+//   - a resting voltage below 12.4 V means the battery is discharged, below
+//     12.0 V it is deeply discharged
+//   - a cranking voltage below 10.0 V means the battery is weak, below 9.6 V
+//     it fails a load test
+//   - a charging voltage above 14.8 V means the alternator overcharges
+//
+// A BatteryMonitor is safe to use from multiple goroutines.
+type BatteryMonitor struct {
+	mutex    sync.Mutex
+	size     int
+	samples  []BatterySample
+	state    BatteryState
+	resting  float64
+	charging float64
+	crank    *CrankingDip
+	lastDip  *CrankingDip
+	onCrank  func(CrankingDip)
+}
+
+// NewBatteryMonitor creates a new BatteryMonitor keeping the given amount of
+// latest samples. The given callback is called each time a cranking dip has
+// ended, it can be nil.
+func NewBatteryMonitor(size int, onCrank func(CrankingDip)) *BatteryMonitor {
+	if size < 1 {
+		size = 1
+	}
+
+	return &BatteryMonitor{size: size, onCrank: onCrank}
+}
+
+// Observe updates the monitor with the given voltage observed at the given
+// time and returns the current state.
+func (mon *BatteryMonitor) Observe(voltage float64, at time.Time) BatteryState {
+	mon.mutex.Lock()
+
+	mon.samples = append(mon.samples, BatterySample{at, voltage})
+
+	if len(mon.samples) > mon.size {
+		mon.samples = mon.samples[len(mon.samples)-mon.size:]
+	}
+
+	var ended *CrankingDip
+
+	switch {
+	case mon.crank != nil && voltage < mon.resting-batteryCrankingDrop:
+		if voltage < mon.crank.Minimum {
+			mon.crank.Minimum = voltage
+		}
+
+		mon.crank.Duration = at.Sub(mon.crank.Start)
+	case mon.crank == nil && mon.state == BatteryResting && voltage < mon.resting-batteryCrankingDrop:
+		mon.crank = &CrankingDip{Start: at, Minimum: voltage}
+		mon.state = BatteryCranking
+	default:
+		if mon.crank != nil {
+			mon.crank.Duration = at.Sub(mon.crank.Start)
+			ended = mon.crank
+			mon.lastDip = mon.crank
+			mon.crank = nil
+		}
+
+		if voltage >= batteryChargingVoltage {
+			mon.state = BatteryCharging
+			mon.charging = voltage
+		} else {
+			mon.state = BatteryResting
+			mon.resting = voltage
+		}
+	}
+
+	state := mon.state
+
+	mon.mutex.Unlock()
+
+	if ended != nil && mon.onCrank != nil {
+		mon.onCrank(*ended)
+	}
+
+	return state
+}
+
+// State retrieves the current state.
+func (mon *BatteryMonitor) State() BatteryState {
+	mon.mutex.Lock()
+	defer mon.mutex.Unlock()
+
+	return mon.state
+}
+
+// Samples retrieves a copy of the latest samples, oldest first.
+func (mon *BatteryMonitor) Samples() []BatterySample {
+	mon.mutex.Lock()
+	defer mon.mutex.Unlock()
+
+	return append([]BatterySample(nil), mon.samples...)
+}
+
+// Report assesses the health of the battery from what has been observed, the
+// reasons tell why the battery is not considered good.
+func (mon *BatteryMonitor) Report() BatteryReport {
+	mon.mutex.Lock()
+	defer mon.mutex.Unlock()
+
+	report := BatteryReport{
+		State:           mon.state,
+		RestingVoltage:  mon.resting,
+		ChargingVoltage: mon.charging,
+		Health:          BatteryHealthUnknown,
+	}
+
+	if len(mon.samples) > 0 {
+		report.Voltage = mon.samples[len(mon.samples)-1].Voltage
+	}
+
+	if mon.lastDip != nil {
+		dip := *mon.lastDip
+		report.LastCrank = &dip
+	}
+
+	assess := func(health BatteryHealth, reason string) {
+		if health > report.Health {
+			report.Health = health
+		}
+
+		if reason != "" {
+			report.Reasons = append(report.Reasons, reason)
+		}
+	}
+
+	if mon.resting > 0 {
+		switch {
+		case mon.resting < 12.0:
+			assess(BatteryHealthBad, fmt.Sprintf("resting voltage %.2f V is deeply discharged", mon.resting))
+		case mon.resting < 12.4:
+			assess(BatteryHealthWeak, fmt.Sprintf("resting voltage %.2f V is discharged", mon.resting))
+		default:
+			assess(BatteryHealthGood, "")
+		}
+	}
+
+	if report.LastCrank != nil {
+		minimum := report.LastCrank.Minimum
+
+		switch {
+		case minimum < 9.6:
+			assess(BatteryHealthBad, fmt.Sprintf("cranking voltage %.2f V fails a load test", minimum))
+		case minimum < 10.0:
+			assess(BatteryHealthWeak, fmt.Sprintf("cranking voltage %.2f V is low", minimum))
+		default:
+			assess(BatteryHealthGood, "")
+		}
+	}
+
+	if mon.charging > 0 {
+		if mon.charging > 14.8 {
+			assess(BatteryHealthBad, fmt.Sprintf("charging voltage %.2f V is too high", mon.charging))
+		} else {
+			assess(BatteryHealthGood, "")
+		}
+	}
+
+	return report
+}
+
+// SampleBattery reads the voltage from the device (see GetVoltage) and
+// observes it with the given monitor, the current state is returned.
+func (dev *Device) SampleBattery(mon *BatteryMonitor) (BatteryState, error) {
+	voltage, err := dev.GetVoltage()
+
+	if err != nil {
+		return mon.State(), err
+	}
+
+	return mon.Observe(float64(voltage), time.Now()), nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// batteryChargingVoltage is the voltage above which the alternator is
+// considered to be charging.
+const batteryChargingVoltage = 13.2
+
+// batteryCrankingDrop is how far the voltage has to drop below the resting
+// voltage to be considered a cranking dip.
+const batteryCrankingDrop = 1.0
@@ -0,0 +1,89 @@
+package elmobd
+
+import (
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestBatteryMonitorCranking(t *testing.T) {
+	var dips []CrankingDip
+
+	mon := NewBatteryMonitor(4, func(dip CrankingDip) {
+		dips = append(dips, dip)
+	})
+
+	start := time.Unix(0, 0)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	assertEqual(t, mon.State(), BatteryUnknown)
+	assertEqual(t, mon.Observe(12.6, at(0)), BatteryResting)
+	assertEqual(t, mon.Observe(12.5, at(100)), BatteryResting)
+	assertEqual(t, mon.Observe(10.4, at(200)), BatteryCranking)
+	assertEqual(t, mon.Observe(10.2, at(300)), BatteryCranking)
+	assertEqual(t, len(dips), 0)
+	assertEqual(t, mon.Observe(14.2, at(600)), BatteryCharging)
+	assertEqual(t, mon.Observe(14.1, at(700)), BatteryCharging)
+
+	// Dropping below the resting voltage while charging is not cranking
+	assertEqual(t, mon.Observe(11.0, at(800)), BatteryResting)
+
+	assertEqual(t, len(dips), 1)
+	assertEqual(t, dips[0].Start, at(200))
+	assertEqual(t, dips[0].Duration, 400*time.Millisecond)
+	assertEqual(t, dips[0].Minimum, 10.2)
+
+	samples := mon.Samples()
+
+	assertEqual(t, len(samples), 4)
+	assertEqual(t, samples[0].Voltage, 10.2)
+	assertEqual(t, samples[3].Voltage, 11.0)
+}
+
+func TestBatteryMonitorReport(t *testing.T) {
+	type scenario struct {
+		voltages []float64
+		health   BatteryHealth
+		reasons  int
+	}
+
+	scenarios := []scenario{
+		{nil, BatteryHealthUnknown, 0},
+		{[]float64{12.6}, BatteryHealthGood, 0},
+		{[]float64{12.2}, BatteryHealthWeak, 1},
+		{[]float64{11.8}, BatteryHealthBad, 1},
+		{[]float64{12.6, 10.4, 14.2}, BatteryHealthGood, 0},
+		{[]float64{12.6, 9.8, 14.2}, BatteryHealthWeak, 1},
+		{[]float64{12.2, 9.2, 14.2}, BatteryHealthBad, 2},
+		{[]float64{12.6, 10.4, 15.1}, BatteryHealthBad, 1},
+	}
+
+	for _, scen := range scenarios {
+		mon := NewBatteryMonitor(10, nil)
+
+		for i, voltage := range scen.voltages {
+			mon.Observe(voltage, time.Unix(int64(i), 0))
+		}
+
+		report := mon.Report()
+
+		assertEqual(t, report.Health, scen.health)
+		assertEqual(t, len(report.Reasons), scen.reasons)
+	}
+}
+
+func TestDeviceSampleBattery(t *testing.T) {
+	dev := Device{rawDevice: &MockDevice{}}
+	mon := NewBatteryMonitor(1, nil)
+
+	state, err := dev.SampleBattery(mon)
+
+	assertSuccess(t, err)
+	assertEqual(t, state, BatteryResting)
+	assertAlmostEqual(t, mon.Report().RestingVoltage, 12.123)
+}
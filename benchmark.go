@@ -0,0 +1,170 @@
+package elmobd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// CommandBenchmark represents the latency measured for one of the commands
+// run by Device.Benchmark.
+type CommandBenchmark struct {
+	Key            string
+	Runs           int
+	Failed         int
+	AverageLatency time.Duration
+	LatencyP95     time.Duration
+}
+
+// BenchmarkResult represents the throughput and the latency distribution
+// measured by Device.Benchmark.
+type BenchmarkResult struct {
+	Rounds            int
+	CommandsRun       int
+	CommandsFailed    int
+	Duration          time.Duration
+	CommandsPerSecond float64
+	LatencyMin        time.Duration
+	AverageLatency    time.Duration
+	LatencyP50        time.Duration
+	LatencyP95        time.Duration
+	LatencyP99        time.Duration
+	LatencyMax        time.Duration
+	Commands          []CommandBenchmark
+}
+
+// String renders the result as a human-readable summary, followed by the
+// latency of each command.
+func (res BenchmarkResult) String() string {
+	var out strings.Builder
+
+	fmt.Fprintf(
+		&out,
+		"%d commands (%d failed) in %s: %.1f commands/s\n",
+		res.CommandsRun,
+		res.CommandsFailed,
+		res.Duration.Round(time.Millisecond),
+		res.CommandsPerSecond,
+	)
+	fmt.Fprintf(
+		&out,
+		"latency min %s avg %s p50 %s p95 %s p99 %s max %s\n",
+		res.LatencyMin,
+		res.AverageLatency,
+		res.LatencyP50,
+		res.LatencyP95,
+		res.LatencyP99,
+		res.LatencyMax,
+	)
+
+	for _, cmd := range res.Commands {
+		fmt.Fprintf(
+			&out,
+			"  %s: avg %s p95 %s (%d/%d failed)\n",
+			cmd.Key,
+			cmd.AverageLatency,
+			cmd.LatencyP95,
+			cmd.Failed,
+			cmd.Runs,
+		)
+	}
+
+	return out.String()
+}
+
+// Benchmark measures how many commands per second the connected adapter and
+// protocol achieve, and the distribution of the latency, by running the given
+// commands in series for the given amount of rounds. The result helps to
+// choose polling intervals and rate limits (see SetRateLimit) from data
+// instead of guesswork.
+//
+// Failed commands are counted and the benchmark goes on, since unsupported
+// PIDs and NO DATA answers take time as well. The latency includes waiting
+// for the rate limit of the device, if any.
+func (dev *Device) Benchmark(commands []OBDCommand, rounds int) (BenchmarkResult, error) {
+	if len(commands) == 0 {
+		return BenchmarkResult{}, fmt.Errorf("no commands to benchmark")
+	}
+
+	if rounds < 1 {
+		return BenchmarkResult{}, fmt.Errorf("rounds must be positive, got %d", rounds)
+	}
+
+	result := BenchmarkResult{Rounds: rounds}
+	perCommand := make([][]time.Duration, len(commands))
+	failed := make([]int, len(commands))
+
+	var all []time.Duration
+
+	start := time.Now()
+
+	for round := 0; round < rounds; round++ {
+		for i, cmd := range commands {
+			cmdStart := time.Now()
+			_, err := dev.RunOBDCommand(cmd)
+			latency := time.Since(cmdStart)
+
+			if err != nil {
+				failed[i]++
+				result.CommandsFailed++
+			}
+
+			perCommand[i] = append(perCommand[i], latency)
+			all = append(all, latency)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	result.CommandsRun = len(all)
+	result.CommandsPerSecond = float64(len(all)) / result.Duration.Seconds()
+
+	sortLatencies(all)
+
+	result.LatencyMin = all[0]
+	result.AverageLatency = averageLatency(all)
+	result.LatencyP50 = latencyPercentile(all, 50)
+	result.LatencyP95 = latencyPercentile(all, 95)
+	result.LatencyP99 = latencyPercentile(all, 99)
+	result.LatencyMax = all[len(all)-1]
+
+	for i, cmd := range commands {
+		latencies := perCommand[i]
+
+		sortLatencies(latencies)
+
+		result.Commands = append(result.Commands, CommandBenchmark{
+			Key:            cmd.Key(),
+			Runs:           len(latencies),
+			Failed:         failed[i],
+			AverageLatency: averageLatency(latencies),
+			LatencyP95:     latencyPercentile(latencies, 95),
+		})
+	}
+
+	return result, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+func sortLatencies(latencies []time.Duration) {
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+}
+
+func averageLatency(latencies []time.Duration) time.Duration {
+	var total time.Duration
+
+	for _, latency := range latencies {
+		total += latency
+	}
+
+	return total / time.Duration(len(latencies))
+}
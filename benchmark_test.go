@@ -0,0 +1,48 @@
+package elmobd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestBenchmark(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	commands := []OBDCommand{
+		NewVehicleSpeed(),
+		NewEngineRPM(),
+		NewTimeWithMILOn(),
+	}
+
+	dev.SetRateLimit(0, 200)
+
+	res, err := dev.Benchmark(commands, 4)
+
+	assertSuccess(t, err)
+	assertEqual(t, res.Rounds, 4)
+	assertEqual(t, res.CommandsRun, 12)
+	assertEqual(t, res.CommandsFailed, 4)
+	// The first command is not delayed, so 12 commands take 11 intervals
+	assert(t, res.CommandsPerSecond < 220, "Expected rate limit to be respected")
+	assert(t, res.LatencyMin <= res.LatencyP50, "Expected min <= p50")
+	assert(t, res.LatencyP50 <= res.LatencyP99, "Expected p50 <= p99")
+	assert(t, res.LatencyP99 <= res.LatencyMax, "Expected p99 <= max")
+	assert(t, res.Duration >= 55*time.Millisecond, "Expected rate limited duration")
+	assertEqual(t, len(res.Commands), 3)
+	assertEqual(t, res.Commands[1].Key, "engine_rpm")
+	assertEqual(t, res.Commands[1].Runs, 4)
+	assertEqual(t, res.Commands[2].Failed, 4)
+	assert(t, strings.Contains(res.String(), "12 commands (4 failed)"), res.String())
+
+	_, err = dev.Benchmark(nil, 1)
+
+	assert(t, err != nil, "Expected no commands to fail")
+
+	_, err = dev.Benchmark(commands, 0)
+
+	assert(t, err != nil, "Expected no rounds to fail")
+}
@@ -0,0 +1,243 @@
+package elmobd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// ValueCache returns the last value of a command when it was read within the
+// TTL, instead of asking the car again, so that UIs can refresh as often as
+// they like without waiting for the bus.
+//
+// When Run is running, the commands read recently (within WarmFor) are
+// refreshed in the background before their value expires, so that reading
+// them returns right away. Commands that are no longer read are not
+// refreshed, and are read from the car again once their value has expired.
+//
+// Use it like this:
+//
+//	cache := elmobd.NewValueCache(dev, 500*time.Millisecond)
+//
+//	go cache.Run(ctx)
+//
+//	speed, err := cache.RunOBDCommand(elmobd.NewVehicleSpeed())
+//
+// It is safe to use from multiple goroutines.
+type ValueCache struct {
+	// TTL is how long a value is returned from the cache.
+	TTL time.Duration
+	// RefreshInterval is how often Run checks for values to refresh, it is
+	// at least 1 ms.
+	RefreshInterval time.Duration
+	// WarmFor is how long after a command was last read that Run keeps
+	// refreshing it.
+	WarmFor time.Duration
+	dev     *Device
+	mutex   sync.Mutex
+	entries map[string]*cacheEntry
+	now     func() time.Time
+}
+
+// NewValueCache creates a new ValueCache for the given device, returning the
+// values read within the given TTL from the cache.
+func NewValueCache(dev *Device, ttl time.Duration) *ValueCache {
+	return &ValueCache{
+		TTL:             ttl,
+		RefreshInterval: ttl / 2,
+		WarmFor:         10 * ttl,
+		dev:             dev,
+		entries:         map[string]*cacheEntry{},
+		now:             time.Now,
+	}
+}
+
+// RunOBDCommand populates the given command with the cached value if it was
+// read within the TTL, otherwise the command is run on the device like
+// Device.RunOBDCommand and its value is cached.
+func (cache *ValueCache) RunOBDCommand(cmd OBDCommand) (OBDCommand, error) {
+	key := cmd.Key()
+	now := cache.now()
+
+	cache.mutex.Lock()
+
+	entry, ok := cache.entries[key]
+
+	if ok {
+		entry.lastRead = now
+	}
+
+	var payload []byte
+
+	if ok && now.Sub(entry.updated) < cache.TTL {
+		payload = entry.payload
+	}
+
+	cache.mutex.Unlock()
+
+	if payload == nil {
+		var err error
+
+		payload, err = cache.refresh(cmd, now)
+
+		if err != nil {
+			return cmd, err
+		}
+	}
+
+	result := &Result{payload}
+
+	if err := cmd.SetValue(result); err != nil {
+		return cmd, err
+	}
+
+	return cmd, cache.dev.validateRanges(result, cmd)
+}
+
+// Invalidate drops the cached values, so that the next read of each command
+// goes to the device.
+func (cache *ValueCache) Invalidate() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries = map[string]*cacheEntry{}
+}
+
+// Run refreshes the values of the commands read recently in the background,
+// see ValueCache. It runs until the given context is done, which is not
+// treated as an error.
+//
+// Failing to refresh a value is not an error either, the value is read from
+// the device again when it has expired.
+func (cache *ValueCache) Run(ctx context.Context) error {
+	ticker := time.NewTicker(cache.refreshInterval())
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		for _, cmd := range cache.due() {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			cache.refresh(cmd, cache.now())
+		}
+	}
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// minCacheRefreshInterval is the shortest RefreshInterval of a ValueCache, so
+// that a RefreshInterval of 0 (such as for a TTL of 1 ns) does not make Run
+// fail or spin.
+const minCacheRefreshInterval = time.Millisecond
+
+// refreshInterval returns the RefreshInterval, raised to the shortest one
+// allowed.
+func (cache *ValueCache) refreshInterval() time.Duration {
+	if cache.RefreshInterval < minCacheRefreshInterval {
+		return minCacheRefreshInterval
+	}
+
+	return cache.RefreshInterval
+}
+
+// cacheEntry is the cached value of a command. The command is the first one
+// given for the key, and is only used for building and validating the
+// request, which does not modify it.
+type cacheEntry struct {
+	cmd      OBDCommand
+	payload  []byte
+	updated  time.Time
+	lastRead time.Time
+}
+
+// due returns the commands that have been read within WarmFor, and whose
+// value expires before the next check.
+func (cache *ValueCache) due() []OBDCommand {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := cache.now()
+	commands := []OBDCommand{}
+
+	for _, entry := range cache.entries {
+		if now.Sub(entry.lastRead) > cache.WarmFor {
+			continue
+		}
+
+		if now.Sub(entry.updated)+cache.refreshInterval() >= cache.TTL {
+			commands = append(commands, entry.cmd)
+		}
+	}
+
+	return commands
+}
+
+// refresh reads the payload of the given command from the device and caches
+// it.
+func (cache *ValueCache) refresh(cmd OBDCommand, now time.Time) ([]byte, error) {
+	payload, err := cache.dev.readPayload(cmd)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[cmd.Key()]
+
+	if !ok {
+		entry = &cacheEntry{cmd: cmd, lastRead: now}
+		cache.entries[cmd.Key()] = entry
+	}
+
+	entry.payload = payload
+	entry.updated = now
+
+	return payload, nil
+}
+
+// readPayload runs the given command on the device and returns the validated
+// payload of the response, without setting the value of the command.
+func (dev *Device) readPayload(cmd OBDCommand) ([]byte, error) {
+	rawRes := dev.runCommand(cmd.ToCommand())
+
+	if rawRes.Failed() {
+		return nil, rawRes.GetError()
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	result, err := parseOBDResponse(cmd, rawRes.GetOutputs())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("no response to %s", cmd.ToCommand())
+	}
+
+	if err := result.Validate(cmd); err != nil {
+		return nil, err
+	}
+
+	return result.value, nil
+}
@@ -0,0 +1,131 @@
+package elmobd
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func (dev *recordingDevice) count() int {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	return len(dev.commands)
+}
+
+func TestValueCacheTTL(t *testing.T) {
+	raw := &recordingDevice{}
+	cache := NewValueCache(&Device{rawDevice: raw}, time.Second)
+	now := time.Unix(0, 0)
+
+	cache.now = func() time.Time {
+		return now
+	}
+
+	for i := 0; i < 3; i++ {
+		speed := NewVehicleSpeed()
+
+		_, err := cache.RunOBDCommand(speed)
+
+		assertSuccess(t, err)
+		assertEqual(t, speed.Value, uint32(75))
+
+		now = now.Add(400 * time.Millisecond)
+	}
+
+	assertEqual(t, raw.count(), 1)
+
+	now = now.Add(time.Second)
+
+	_, err := cache.RunOBDCommand(NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, raw.count(), 2)
+
+	cache.Invalidate()
+
+	_, err = cache.RunOBDCommand(NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, raw.count(), 3)
+
+	_, err = cache.RunOBDCommand(NewTimeWithMILOn())
+
+	assert(t, err != nil, "Expected unsupported command to fail")
+	assertEqual(t, len(cache.entries), 1)
+}
+
+func TestValueCacheDue(t *testing.T) {
+	cache := NewValueCache(&Device{rawDevice: &MockDevice{}}, time.Second)
+	now := time.Unix(0, 0)
+
+	cache.now = func() time.Time {
+		return now
+	}
+
+	_, err := cache.RunOBDCommand(NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, len(cache.due()), 0)
+
+	now = now.Add(600 * time.Millisecond)
+
+	assertEqual(t, len(cache.due()), 1)
+
+	// Not read for longer than WarmFor
+	now = now.Add(cache.WarmFor)
+
+	assertEqual(t, len(cache.due()), 0)
+}
+
+func TestValueCacheRun(t *testing.T) {
+	raw := &recordingDevice{}
+	cache := NewValueCache(&Device{rawDevice: raw}, 40*time.Millisecond)
+
+	cache.RefreshInterval = 10 * time.Millisecond
+
+	_, err := cache.RunOBDCommand(NewEngineRPM())
+
+	assertSuccess(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+	defer cancel()
+
+	assertSuccess(t, cache.Run(ctx))
+
+	refreshed := raw.count() - 1
+
+	assert(t, refreshed >= 2, "Expected value to be refreshed in the background")
+
+	rpm, err := cache.RunOBDCommand(NewEngineRPM())
+
+	assertSuccess(t, err)
+	assertEqual(t, rpm.(*EngineRPM).Value, float32(192))
+}
+
+func TestValueCacheRunShortInterval(t *testing.T) {
+	raw := &recordingDevice{}
+	cache := NewValueCache(&Device{rawDevice: raw}, time.Nanosecond)
+
+	assertEqual(t, cache.RefreshInterval, time.Duration(0))
+	assertEqual(t, cache.refreshInterval(), minCacheRefreshInterval)
+
+	_, err := cache.RunOBDCommand(NewEngineRPM())
+
+	assertSuccess(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+
+	defer cancel()
+
+	assertSuccess(t, cache.Run(ctx))
+
+	cache.RefreshInterval = -time.Second
+
+	assertEqual(t, cache.refreshInterval(), minCacheRefreshInterval)
+}
@@ -0,0 +1,136 @@
+package elmobd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// ChipVersion represents the identification of the chip of the ELM327
+// device, as answered to the ATI command, such as "ELM327 v1.4b".
+//
+// Most cheap adapters are clones running copied firmware, which often lack
+// AT commands or behave differently than the genuine chip. LikelyClone is a
+// heuristic based on the version the chip reports:
+//
+//   - v1.5 was never released by ELM Electronics, but is what most clones
+//     report
+//   - v2.1 is the most commonly cloned version
+//   - any other version that was never released
+type ChipVersion struct {
+	Raw         string
+	Family      string
+	Major       int
+	Minor       int
+	Revision    string
+	LikelyClone bool
+}
+
+// String returns the version as reported by the chip, such as "v1.4b".
+func (ver ChipVersion) String() string {
+	return fmt.Sprintf("v%d.%d%s", ver.Major, ver.Minor, ver.Revision)
+}
+
+// AtLeast checks if the version is the given version or later, useful for
+// checking whether an AT command is available, such as ATAT which was added
+// in v1.2.
+func (ver ChipVersion) AtLeast(major int, minor int) bool {
+	if ver.Major != major {
+		return ver.Major > major
+	}
+
+	return ver.Minor >= minor
+}
+
+// ParseChipVersion parses the answer of the ATI command into a ChipVersion.
+func ParseChipVersion(raw string) (ChipVersion, error) {
+	raw = strings.TrimSpace(raw)
+	match := chipVersionPattern.FindStringSubmatch(raw)
+
+	if match == nil {
+		return ChipVersion{}, fmt.Errorf("failed to parse chip version: %q", raw)
+	}
+
+	major, _ := strconv.Atoi(match[2])
+	minor, _ := strconv.Atoi(match[3])
+
+	ver := ChipVersion{
+		Raw:      raw,
+		Family:   match[1],
+		Major:    major,
+		Minor:    minor,
+		Revision: strings.ToLower(match[4]),
+	}
+
+	ver.LikelyClone = isLikelyClone(ver)
+
+	return ver, nil
+}
+
+// GetChipVersion gets the identification of the chip of the connected ELM327
+// device by running ATI. Unlike GetVersion, which gets the description of the
+// device, this tells the version of the firmware.
+func (dev *Device) GetChipVersion() (ChipVersion, error) {
+	rawRes := dev.runCommand("ATI")
+
+	if rawRes.Failed() {
+		return ChipVersion{}, rawRes.GetError()
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	for _, out := range rawRes.GetOutputs() {
+		if ver, err := ParseChipVersion(out); err == nil {
+			return ver, nil
+		}
+	}
+
+	return ChipVersion{}, fmt.Errorf(
+		"failed to parse chip version: %q", rawRes.GetOutputs(),
+	)
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+var chipVersionPattern = regexp.MustCompile(`^(\S+)\s+v(\d+)\.(\d+)([a-zA-Z]*)`)
+
+// releasedELM327Versions are the versions of the ELM327 released by ELM
+// Electronics.
+var releasedELM327Versions = map[string]bool{
+	"v1.0":  true,
+	"v1.0a": true,
+	"v1.1":  true,
+	"v1.2":  true,
+	"v1.2a": true,
+	"v1.3":  true,
+	"v1.3a": true,
+	"v1.4":  true,
+	"v1.4b": true,
+	"v2.0":  true,
+	"v2.1":  true,
+	"v2.2":  true,
+	"v2.3":  true,
+}
+
+// isLikelyClone checks the version against the heuristics described on
+// ChipVersion.
+func isLikelyClone(ver ChipVersion) bool {
+	if ver.Family != "ELM327" {
+		return false
+	}
+
+	if ver.Major == 2 && ver.Minor == 1 {
+		return true
+	}
+
+	return !releasedELM327Versions[ver.String()]
+}
@@ -0,0 +1,68 @@
+package elmobd
+
+import (
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestParseChipVersion(t *testing.T) {
+	type scenario struct {
+		raw    string
+		family string
+		major  int
+		minor  int
+		ver    string
+		clone  bool
+	}
+
+	scenarios := []scenario{
+		{"ELM327 v1.4b", "ELM327", 1, 4, "v1.4b", false},
+		{"ELM327 v2.2", "ELM327", 2, 2, "v2.2", false},
+		{"ELM327 v1.5", "ELM327", 1, 5, "v1.5", true},
+		{"ELM327 v2.1", "ELM327", 2, 1, "v2.1", true},
+		{"ELM327 v3.0", "ELM327", 3, 0, "v3.0", true},
+		{"  ELM329 v2.0  ", "ELM329", 2, 0, "v2.0", false},
+	}
+
+	for _, scen := range scenarios {
+		ver, err := ParseChipVersion(scen.raw)
+
+		assertSuccess(t, err)
+		assertEqual(t, ver.Family, scen.family)
+		assertEqual(t, ver.Major, scen.major)
+		assertEqual(t, ver.Minor, scen.minor)
+		assertEqual(t, ver.String(), scen.ver)
+		assertEqual(t, ver.LikelyClone, scen.clone)
+	}
+
+	_, err := ParseChipVersion("OBDII by elm329@gmail.com")
+
+	assert(t, err != nil, "description without version fails")
+}
+
+func TestChipVersionAtLeast(t *testing.T) {
+	ver, err := ParseChipVersion("ELM327 v1.4b")
+
+	assertSuccess(t, err)
+	assertEqual(t, ver.AtLeast(1, 2), true)
+	assertEqual(t, ver.AtLeast(1, 4), true)
+	assertEqual(t, ver.AtLeast(1, 5), false)
+	assertEqual(t, ver.AtLeast(2, 0), false)
+}
+
+func TestGetChipVersion(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	ver, err := dev.GetChipVersion()
+
+	assertSuccess(t, err)
+	assertEqual(t, ver.Raw, "ELM327 v1.5")
+	assertEqual(t, ver.LikelyClone, true)
+
+	description, err := dev.GetVersion()
+
+	assertSuccess(t, err)
+	assertEqual(t, description, "OBDII by elm329@gmail.com")
+}
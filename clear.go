@@ -0,0 +1,148 @@
+package elmobd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// ErrClearNotConfirmed is returned by ClearDTCs when the clear was not
+// confirmed.
+var ErrClearNotConfirmed = errors.New("clearing trouble codes was not confirmed")
+
+// ErrClearNotVerified is returned by ClearDTCs when the car still reports
+// trouble codes or the MIL after the clear.
+var ErrClearNotVerified = errors.New("trouble codes are still reported after clearing them")
+
+// ErrClearRefused is returned by ClearDTCs when the car refuses to clear the
+// trouble codes, which most cars do while the engine is running.
+var ErrClearRefused = errors.New("car refused to clear trouble codes")
+
+// ClearReport represents the outcome of ClearDTCs.
+type ClearReport struct {
+	// Before are the trouble codes stored before the clear.
+	Before []TroubleCode `json:"before"`
+	// After are the trouble codes stored after the clear.
+	After []TroubleCode `json:"after"`
+	// Removed are the trouble codes that were cleared.
+	Removed []TroubleCode `json:"removed"`
+	// MilActive and DtcAmount are the monitor status after the clear.
+	MilActive bool `json:"mil_active"`
+	DtcAmount byte `json:"dtc_amount"`
+	// Verified tells whether the car reports no trouble codes and the MIL
+	// off after the clear.
+	Verified bool `json:"verified"`
+}
+
+// ClearDTCs clears the trouble codes of the car in a guided way, since a
+// clear can not be undone and also resets the monitors, the freeze frames
+// and other diagnostic data:
+//
+//  1. the stored trouble codes are read (service 03)
+//  2. the given callback is asked to confirm the clear, given the codes
+//     about to be cleared, ErrClearNotConfirmed is returned when it does not
+//  3. the trouble codes are cleared (service 04, see NewClearTroubleCodes)
+//  4. the stored trouble codes and the monitor status (service 01 PID 01)
+//     are read again, to verify that the clear succeeded
+//
+// The report of what was removed is returned along with ErrClearNotVerified
+// when the car still reports trouble codes or the MIL after the clear, such
+// as when the fault is still present.
+//
+// Most cars only clear the codes with the ignition on and the engine off,
+// ErrClearRefused is returned when the car refuses.
+func (dev *Device) ClearDTCs(confirm func(codes []TroubleCode) bool) (*ClearReport, error) {
+	if confirm == nil {
+		return nil, ErrClearNotConfirmed
+	}
+
+	before, err := dev.GetTroubleCodes()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trouble codes: %w", err)
+	}
+
+	if !confirm(before) {
+		return nil, ErrClearNotConfirmed
+	}
+
+	if err := dev.clearTroubleCodes(); err != nil {
+		return nil, err
+	}
+
+	after, err := dev.GetTroubleCodes()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trouble codes after clearing them: %w", err)
+	}
+
+	status, err := Run(dev, NewMonitorStatus())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read monitor status after clearing trouble codes: %w", err)
+	}
+
+	report := &ClearReport{
+		Before:    before,
+		After:     after,
+		Removed:   []TroubleCode{},
+		MilActive: status.MilActive,
+		DtcAmount: status.DtcAmount,
+		Verified:  len(after) == 0 && !status.MilActive && status.DtcAmount == 0,
+	}
+
+	remaining := map[TroubleCode]bool{}
+
+	for _, code := range after {
+		remaining[code] = true
+	}
+
+	for _, code := range before {
+		if !remaining[code] {
+			report.Removed = append(report.Removed, code)
+		}
+	}
+
+	if !report.Verified {
+		return report, ErrClearNotVerified
+	}
+
+	return report, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// clearTroubleCodes sends service 04 and checks that the car acknowledged
+// it, by answering with a positive response (44) instead of a negative one
+// (7F 04).
+func (dev *Device) clearTroubleCodes() error {
+	rawRes := dev.runCommand(fmt.Sprintf("%02X", NewClearTroubleCodes().ModeID()))
+
+	if rawRes.Failed() {
+		return fmt.Errorf("failed to clear trouble codes: %w", rawRes.GetError())
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	for _, out := range rawRes.GetOutputs() {
+		out = stripSpaces(out)
+
+		if strings.HasPrefix(out, "44") {
+			return nil
+		}
+
+		if strings.HasPrefix(out, "7F04") {
+			return fmt.Errorf("%w: %s", ErrClearRefused, out)
+		}
+	}
+
+	return fmt.Errorf("unexpected answer to clearing trouble codes: %q", rawRes.GetOutputs())
+}
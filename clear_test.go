@@ -0,0 +1,89 @@
+package elmobd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// stubbornDevice acknowledges clearing the trouble codes without clearing
+// them, like a car where the fault is still present.
+type stubbornDevice struct {
+	MockDevice
+}
+
+func (dev *stubbornDevice) RunCommand(command string) RawResult {
+	if command == "04" {
+		return &MockResult{input: command, outputs: []string{"44"}}
+	}
+
+	return dev.MockDevice.RunCommand(command)
+}
+
+func TestClearDTCs(t *testing.T) {
+	raw := &recordingDevice{}
+	dev := &Device{rawDevice: raw}
+
+	var confirmed []TroubleCode
+
+	report, err := dev.ClearDTCs(func(codes []TroubleCode) bool {
+		confirmed = codes
+
+		return true
+	})
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprint(confirmed), "[P0133]")
+	assertEqual(t, fmt.Sprint(report.Removed), "[P0133]")
+	assertEqual(t, len(report.After), 0)
+	assertEqual(t, report.MilActive, false)
+	assertEqual(t, report.Verified, true)
+	assertEqual(t, fmt.Sprint(raw.commands), "[03 04 03 01011]")
+}
+
+func TestClearDTCsNotConfirmed(t *testing.T) {
+	raw := &recordingDevice{}
+	dev := &Device{rawDevice: raw}
+
+	_, err := dev.ClearDTCs(nil)
+
+	assertEqual(t, err, ErrClearNotConfirmed)
+
+	_, err = dev.ClearDTCs(func(codes []TroubleCode) bool {
+		return false
+	})
+
+	assertEqual(t, err, ErrClearNotConfirmed)
+	assertEqual(t, fmt.Sprint(raw.commands), "[03]")
+}
+
+func TestClearDTCsNotVerified(t *testing.T) {
+	dev := &Device{rawDevice: &stubbornDevice{}}
+
+	report, err := dev.ClearDTCs(func(codes []TroubleCode) bool {
+		return true
+	})
+
+	assertEqual(t, err, ErrClearNotVerified)
+	assertEqual(t, report.Verified, false)
+	assertEqual(t, report.MilActive, true)
+	assertEqual(t, len(report.Removed), 0)
+	assertEqual(t, fmt.Sprint(report.After), "[P0133]")
+}
+
+func TestClearDTCsRefused(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	raw.setResponse("04", "7F 04 22")
+
+	_, err := dev.ClearDTCs(func(codes []TroubleCode) bool {
+		return true
+	})
+
+	assert(t, errors.Is(err, ErrClearRefused), "Expected refused clear")
+}
@@ -0,0 +1,72 @@
+package elmobd
+
+import (
+	"reflect"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Cloner is implemented by commands that know how to copy themselves, which
+// CloneCommand uses instead of copying the struct of the command. Commands
+// defined outside of this library holding slices or maps in their values
+// should implement it.
+type Cloner interface {
+	Clone() OBDCommand
+}
+
+// CloneCommand returns a copy of the given command, which can be run without
+// modifying the given command. Commands that are not pointers to structs are
+// returned as they are.
+func CloneCommand(cmd OBDCommand) OBDCommand {
+	if cloner, ok := cmd.(Cloner); ok {
+		return cloner.Clone()
+	}
+
+	val := reflect.ValueOf(cmd)
+
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return cmd
+	}
+
+	clone := reflect.New(val.Elem().Type())
+	clone.Elem().Set(val.Elem())
+
+	return clone.Interface().(OBDCommand)
+}
+
+// CloneCommands returns a copy of each of the given commands, see
+// CloneCommand.
+func CloneCommands(commands []OBDCommand) []OBDCommand {
+	clones := make([]OBDCommand, len(commands))
+
+	for i, cmd := range commands {
+		clones[i] = CloneCommand(cmd)
+	}
+
+	return clones
+}
+
+// RunOBDCommandClone works like RunOBDCommand, but runs a copy of the given
+// command (see CloneCommand) and returns the populated copy, leaving the
+// given command as it is.
+//
+// This makes it safe to share command instances between goroutines, such as
+// the commands of GetSensorCommands, as long as the shared instances are only
+// run this way.
+func (dev *Device) RunOBDCommandClone(cmd OBDCommand) (OBDCommand, error) {
+	return dev.RunOBDCommand(CloneCommand(cmd))
+}
+
+// RunClone works like Run, but runs a copy of the given command like
+// RunOBDCommandClone does.
+func RunClone[T OBDCommand](dev *Device, cmd T) (T, error) {
+	clone, ok := CloneCommand(cmd).(T)
+
+	if !ok {
+		clone = cmd
+	}
+
+	return Run(dev, clone)
+}
@@ -0,0 +1,56 @@
+package elmobd
+
+import (
+	"sync"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestRunOBDCommandClone(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	shared := NewVehicleSpeed()
+
+	processed, err := dev.RunOBDCommandClone(shared)
+
+	assertSuccess(t, err)
+	assertEqual(t, processed.(*VehicleSpeed).Value, uint32(75))
+	assertEqual(t, shared.Value, uint32(0))
+	assertEqual(t, processed.ToCommand(), shared.ToCommand())
+
+	speed, err := RunClone(dev, shared)
+
+	assertSuccess(t, err)
+	assertEqual(t, speed.Value, uint32(75))
+	assert(t, speed != shared, "Expected a new instance")
+	assertEqual(t, shared.Value, uint32(0))
+}
+
+func TestRunOBDCommandCloneConcurrent(t *testing.T) {
+	dev := &Device{rawDevice: &recordingDevice{}}
+	commands := GetSensorCommands()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for _, cmd := range commands {
+				dev.RunOBDCommandClone(cmd)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	clones := CloneCommands(commands)
+
+	assertEqual(t, len(clones), len(commands))
+	assertEqual(t, clones[0].Key(), commands[0].Key())
+	assert(t, clones[0] != commands[0], "Expected a new instance")
+}
@@ -0,0 +1,248 @@
+// Command obddash shows a live dashboard of sensor values in the terminal.
+//
+// The commands to show are given as a comma-separated list of command keys,
+// each command is polled in turn and the dashboard is redrawn in place after
+// every round:
+//
+//	obddash -addr serial:///dev/ttyUSB0 -commands engine_rpm,vehicle_speed
+//
+// Values with a known range are shown with a gauge, and all numeric values
+// are shown with a sparkline of the latest readings.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rzetterberg/elmobd"
+	_ "github.com/rzetterberg/elmobd/transport/net"
+	_ "github.com/rzetterberg/elmobd/transport/serial"
+)
+
+const (
+	gaugeWidth     = 20
+	sparklineWidth = 30
+	clearScreen    = "\033[H\033[2J"
+	hideCursor     = "\033[?25l"
+	showCursor     = "\033[?25h"
+)
+
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+func main() {
+	addr := flag.String(
+		"addr",
+		"test:///dev/ttyUSB0",
+		"Address of the ELM327 device to use (use either test://, tcp://ip:port or serial:///dev/ttyS0)",
+	)
+	keys := flag.String(
+		"commands",
+		"engine_rpm,vehicle_speed,coolant_temperature,engine_load,throttle_position",
+		"Comma-separated keys of the commands to show",
+	)
+	interval := flag.Duration(
+		"interval",
+		500*time.Millisecond,
+		"Minimum time between two rounds of polling",
+	)
+
+	flag.Parse()
+
+	commands, err := lookupCommands(*keys)
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	dev, err := elmobd.NewDevice(*addr, false)
+
+	if err != nil {
+		fmt.Println("Failed to create new device", err)
+		os.Exit(1)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	fmt.Print(hideCursor)
+	defer fmt.Print(showCursor)
+
+	history := elmobd.NewHistory(sparklineWidth)
+	failures := map[string]error{}
+	ticker := time.NewTicker(*interval)
+
+	defer ticker.Stop()
+
+	for {
+		for _, cmd := range commands {
+			_, err := dev.RunOBDCommand(cmd)
+
+			failures[cmd.Key()] = err
+
+			if err == nil {
+				history.Record(cmd)
+			}
+		}
+
+		render(*addr, commands, history, failures)
+
+		select {
+		case <-interrupt:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lookupCommands finds the commands with the given comma-separated keys.
+func lookupCommands(keys string) ([]elmobd.OBDCommand, error) {
+	known := map[string]elmobd.OBDCommand{}
+
+	for _, cmd := range elmobd.GetSensorCommands() {
+		known[cmd.Key()] = cmd
+	}
+
+	extra := []elmobd.OBDCommand{
+		elmobd.NewFuel(),
+		elmobd.NewDistSinceDTCClear(),
+		elmobd.NewOdometer(),
+		elmobd.NewTransmissionActualGear(),
+		elmobd.NewControlModuleVoltage(),
+		elmobd.NewAmbientTemperature(),
+		elmobd.NewEngineOilTemperature(),
+		elmobd.NewAbsoluteBarometricPressure(),
+	}
+
+	for _, cmd := range extra {
+		known[cmd.Key()] = cmd
+	}
+
+	var result []elmobd.OBDCommand
+
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+
+		if key == "" {
+			continue
+		}
+
+		cmd, ok := known[key]
+
+		if !ok {
+			names := make([]string, 0, len(known))
+
+			for name := range known {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			return nil, fmt.Errorf(
+				"Unknown command %q, available commands: %s",
+				key,
+				strings.Join(names, ", "),
+			)
+		}
+
+		result = append(result, cmd)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("No commands given")
+	}
+
+	return result, nil
+}
+
+// render redraws the whole dashboard.
+func render(addr string, commands []elmobd.OBDCommand, history *elmobd.History, failures map[string]error) {
+	var out strings.Builder
+
+	out.WriteString(clearScreen)
+	fmt.Fprintf(&out, "elmobd dashboard - %s - %s\n\n", addr, time.Now().Format("15:04:05"))
+
+	for _, cmd := range commands {
+		key := cmd.Key()
+
+		if err := failures[key]; err != nil {
+			fmt.Fprintf(&out, "%-30s error: %s\n", key, err)
+			continue
+		}
+
+		reading, _ := history.Latest(key)
+		val, numeric := reading.Float64()
+
+		if !numeric {
+			fmt.Fprintf(&out, "%-30s %s\n", key, cmd.ValueAsLit())
+			continue
+		}
+
+		fmt.Fprintf(
+			&out,
+			"%-30s %12.2f %-6s %s %s\n",
+			key,
+			val,
+			reading.Unit,
+			gauge(cmd, val),
+			sparkline(history.Values(key)),
+		)
+	}
+
+	out.WriteString("\nPress Ctrl+C to quit\n")
+
+	fmt.Print(out.String())
+}
+
+// gauge draws the value as a bar within the range of the command.
+func gauge(cmd elmobd.OBDCommand, val float64) string {
+	vr, ok := elmobd.GetCommandRange(cmd)
+
+	if !ok || vr.Max <= vr.Min {
+		return strings.Repeat(" ", gaugeWidth+2)
+	}
+
+	filled := int(math.Round((val - vr.Min) / (vr.Max - vr.Min) * gaugeWidth))
+
+	if filled < 0 {
+		filled = 0
+	} else if filled > gaugeWidth {
+		filled = gaugeWidth
+	}
+
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", gaugeWidth-filled) + "]"
+}
+
+// sparkline draws the values relative to the lowest and highest value.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+
+	for _, val := range values {
+		low = math.Min(low, val)
+		high = math.Max(high, val)
+	}
+
+	var line strings.Builder
+
+	for _, val := range values {
+		level := 0
+
+		if high > low {
+			level = int((val - low) / (high - low) * float64(len(sparklineLevels)-1))
+		}
+
+		line.WriteRune(sparklineLevels[level])
+	}
+
+	return line.String()
+}
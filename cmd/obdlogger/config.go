@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rzetterberg/elmobd"
+)
+
+// config is the configuration file of the logger, such as:
+//
+//	{
+//	  "device": "serial:///dev/ttyUSB0",
+//	  "commands": {
+//	    "engine_rpm": "1s",
+//	    "vehicle_speed": "1s",
+//	    "coolant_temperature": "30s"
+//	  },
+//	  "sinks": {
+//	    "csv": {"path": "/var/log/obdlogger/readings.csv", "max_size": 10485760, "max_backups": 5},
+//	    "mqtt": {"broker": "localhost:1883", "topic_prefix": "car"},
+//	    "http": {"url": "http://localhost:8080/readings", "batch_size": 50, "flush_interval": "5s"}
+//	  }
+//	}
+//
+// The commands are given by key with the interval to read them at. At least
+// one sink has to be configured.
+type config struct {
+	Device   string              `json:"device"`
+	Watchdog int                 `json:"watchdog"`
+	Commands map[string]duration `json:"commands"`
+	Sinks    struct {
+		CSV  *csvConfig  `json:"csv"`
+		MQTT *mqttConfig `json:"mqtt"`
+		HTTP *httpConfig `json:"http"`
+	} `json:"sinks"`
+}
+
+type csvConfig struct {
+	Path       string `json:"path"`
+	MaxSize    int64  `json:"max_size"`
+	MaxBackups int    `json:"max_backups"`
+}
+
+type mqttConfig struct {
+	Broker      string `json:"broker"`
+	ClientID    string `json:"client_id"`
+	TopicPrefix string `json:"topic_prefix"`
+	Retained    bool   `json:"retained"`
+}
+
+type httpConfig struct {
+	URL           string   `json:"url"`
+	BatchSize     int      `json:"batch_size"`
+	FlushInterval duration `json:"flush_interval"`
+	QueueSize     int      `json:"queue_size"`
+}
+
+// duration is a time.Duration given as a string in the configuration, such
+// as "500ms".
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var str string
+
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("duration is not a string: %s", data)
+	}
+
+	parsed, err := time.ParseDuration(str)
+
+	if err != nil {
+		return err
+	}
+
+	*d = duration(parsed)
+
+	return nil
+}
+
+// loadConfig reads and validates the configuration file at the given path.
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	conf := config{
+		Device:   "test://demo",
+		Watchdog: 3,
+	}
+
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if len(conf.Commands) == 0 {
+		return nil, fmt.Errorf("no commands configured in %s", path)
+	}
+
+	for key, interval := range conf.Commands {
+		if _, ok := elmobd.NewCommandByKey(key); !ok {
+			return nil, fmt.Errorf("unknown command %q in %s", key, path)
+		}
+
+		if interval <= 0 {
+			return nil, fmt.Errorf("interval of %s is not positive in %s", key, path)
+		}
+	}
+
+	if conf.Sinks.CSV == nil && conf.Sinks.MQTT == nil && conf.Sinks.HTTP == nil {
+		return nil, fmt.Errorf("no sinks configured in %s", path)
+	}
+
+	return &conf, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// writeConfig writes the given configuration to a temporary file, returning
+// its path.
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "obdlogger.json")
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	path := writeConfig(t, `{
+		"commands": {"engine_rpm": "500ms"},
+		"sinks": {"csv": {"path": "readings.csv"}}
+	}`)
+
+	conf, err := loadConfig(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Device != "test://demo" {
+		t.Errorf("Expected the demo device, got %q", conf.Device)
+	}
+
+	if conf.Watchdog != 3 {
+		t.Errorf("Expected a watchdog of 3 timeouts, got %d", conf.Watchdog)
+	}
+
+	if time.Duration(conf.Commands["engine_rpm"]) != 500*time.Millisecond {
+		t.Errorf("Expected an interval of 500ms, got %s", time.Duration(conf.Commands["engine_rpm"]))
+	}
+
+	if conf.Sinks.MQTT != nil || conf.Sinks.HTTP != nil {
+		t.Error("Expected only the CSV sink")
+	}
+}
+
+func TestLoadConfigExample(t *testing.T) {
+	conf, err := loadConfig("obdlogger.example.json")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Device != "serial:///dev/ttyUSB0?baudrate=38400" {
+		t.Errorf("Unexpected device %q", conf.Device)
+	}
+
+	if len(conf.Commands) != 5 {
+		t.Errorf("Expected 5 commands, got %d", len(conf.Commands))
+	}
+
+	if conf.Sinks.CSV.MaxBackups != 5 || conf.Sinks.MQTT.TopicPrefix != "car" {
+		t.Error("Unexpected sinks")
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	type scenario struct {
+		content string
+		err     string
+	}
+
+	scenarios := []scenario{
+		{`{"commands": {"engine_rpm": "1s"`, "failed to parse"},
+		{`{"commands": {"engine_rpm": 1}, "sinks": {"csv": {}}}`, "duration is not a string"},
+		{`{"commands": {"engine_rpm": "soon"}, "sinks": {"csv": {}}}`, "invalid duration"},
+		{`{"sinks": {"csv": {}}}`, "no commands configured"},
+		{`{"commands": {"warp_speed": "1s"}, "sinks": {"csv": {}}}`, `unknown command "warp_speed"`},
+		{`{"commands": {"engine_rpm": "0s"}, "sinks": {"csv": {}}}`, "interval of engine_rpm is not positive"},
+		{`{"commands": {"engine_rpm": "-1s"}, "sinks": {"csv": {}}}`, "interval of engine_rpm is not positive"},
+		{`{"commands": {"engine_rpm": "1s"}}`, "no sinks configured"},
+	}
+
+	for _, scen := range scenarios {
+		_, err := loadConfig(writeConfig(t, scen.content))
+
+		if err == nil || !strings.Contains(err.Error(), scen.err) {
+			t.Errorf("Expected %q to fail with %q, got %v", scen.content, scen.err, err)
+		}
+	}
+
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+}
@@ -0,0 +1,139 @@
+// Command obdlogger is a headless daemon logging sensor values, such as on a
+// Raspberry Pi in the car.
+//
+// It reads the device, the commands with the interval to read them at and the
+// sinks to write the readings to from a JSON configuration file (see config),
+// and runs until it is interrupted or terminated:
+//
+//	obdlogger -config /etc/obdlogger.json
+//
+// The readings can be appended to a CSV file, published to an MQTT broker
+// and posted to an HTTP endpoint, see obdlogger.example.json. Commands
+// failing to run are logged and read again at their next interval.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rzetterberg/elmobd"
+	_ "github.com/rzetterberg/elmobd/transport/net"
+	_ "github.com/rzetterberg/elmobd/transport/serial"
+)
+
+var logger = log.New(os.Stderr, "obdlogger: ", log.LstdFlags)
+
+func main() {
+	path := flag.String(
+		"config",
+		"/etc/obdlogger.json",
+		"Path of the configuration file",
+	)
+	debug := flag.Bool(
+		"debug",
+		false,
+		"Print the commands sent to the device",
+	)
+
+	flag.Parse()
+
+	conf, err := loadConfig(*path)
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	dev, err := elmobd.NewDevice(conf.Device, *debug)
+
+	if err != nil {
+		logger.Fatal("Failed to create new device: ", err)
+	}
+
+	defer dev.Close()
+
+	if conf.Watchdog > 0 {
+		err = dev.EnableWatchdog(conf.Watchdog, func(event elmobd.WatchdogEvent) {
+			logger.Printf("Watchdog: %s after %d timeouts (recovered: %t)", event.Step, event.Timeouts, event.Recovered)
+		})
+
+		if err != nil {
+			logger.Println("Running without watchdog:", err)
+		}
+	}
+
+	sinks, err := openSinks(conf)
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	defer closeSinks(sinks)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	keys := make([]string, 0, len(conf.Commands))
+
+	for key := range conf.Commands {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var wg sync.WaitGroup
+
+	for _, key := range keys {
+		cmd, _ := elmobd.NewCommandByKey(key)
+		wg.Add(1)
+
+		go func(interval time.Duration) {
+			defer wg.Done()
+
+			poll(ctx, dev, cmd, interval, sinks)
+		}(time.Duration(conf.Commands[key]))
+	}
+
+	logger.Printf("Logging %d commands from %s", len(keys), conf.Device)
+
+	wg.Wait()
+
+	logger.Println("Stopped")
+}
+
+// poll reads the given command at the given interval until the given context
+// is done, writing each reading to all the sinks.
+//
+// The commands of all the pollers are queued on the device with
+// RunOBDCommandAsync, so they are run one after the other.
+func poll(ctx context.Context, dev *elmobd.Device, cmd elmobd.OBDCommand, interval time.Duration, sinks []sink) {
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	for {
+		result := <-dev.RunOBDCommandAsync(cmd)
+
+		if result.Err != nil {
+			logger.Printf("Failed to read %s: %s", cmd.Key(), result.Err)
+		} else {
+			for _, s := range sinks {
+				if err := s.Write(result); err != nil {
+					logger.Printf("Failed to write %s: %s", cmd.Key(), err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
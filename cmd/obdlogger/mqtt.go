@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// mqttClient is a minimal MQTT 3.1.1 client only publishing with QoS 0,
+// which is all the logger needs, so that it does not depend on an MQTT
+// library. The connection is opened again when publishing fails.
+type mqttClient struct {
+	mutex    sync.Mutex
+	broker   string
+	clientID string
+	conn     net.Conn
+}
+
+// dialMQTT connects to the MQTT broker at the given address, such as
+// "localhost:1883".
+func dialMQTT(broker string, clientID string) (*mqttClient, error) {
+	client := &mqttClient{broker: broker, clientID: clientID}
+
+	if err := client.connect(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Publish publishes the given payload to the given topic, implementing
+// elmobd.MQTTClient.
+func (client *mqttClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	if qos != 0 {
+		return fmt.Errorf("unsupported MQTT QoS: %d", qos)
+	}
+
+	header := byte(0x30)
+
+	if retained {
+		header |= 0x01
+	}
+
+	packet := mqttPacket(header, mqttString(topic), payload)
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.conn != nil {
+		if _, err := client.conn.Write(packet); err == nil {
+			return nil
+		}
+
+		client.conn.Close()
+		client.conn = nil
+	}
+
+	if err := client.connect(); err != nil {
+		return err
+	}
+
+	_, err := client.conn.Write(packet)
+
+	return err
+}
+
+// Close disconnects from the broker.
+func (client *mqttClient) Close() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.conn == nil {
+		return nil
+	}
+
+	client.conn.Write([]byte{0xE0, 0x00})
+
+	err := client.conn.Close()
+	client.conn = nil
+
+	return err
+}
+
+// connect opens the connection to the broker and waits for it to accept the
+// connection. The keep alive is turned off, so that the connection does not
+// need to be pinged between readings.
+func (client *mqttClient) connect() error {
+	conn, err := net.DialTimeout("tcp", client.broker, 10*time.Second)
+
+	if err != nil {
+		return err
+	}
+
+	variable := []byte{0x00, 0x04, 'M', 'Q', 'T', 'T', 0x04, 0x02, 0x00, 0x00}
+
+	if _, err := conn.Write(mqttPacket(0x10, variable, mqttString(client.clientID))); err != nil {
+		conn.Close()
+
+		return err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	ack := make([]byte, 4)
+
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		conn.Close()
+
+		return fmt.Errorf("no answer from MQTT broker: %w", err)
+	}
+
+	if ack[0] != 0x20 || ack[3] != 0x00 {
+		conn.Close()
+
+		return errors.New("MQTT broker refused the connection")
+	}
+
+	conn.SetReadDeadline(time.Time{})
+
+	client.conn = conn
+
+	return nil
+}
+
+// mqttPacket builds a packet with the given fixed header byte out of the
+// given parts.
+func mqttPacket(header byte, parts ...[]byte) []byte {
+	length := 0
+
+	for _, part := range parts {
+		length += len(part)
+	}
+
+	packet := []byte{header}
+
+	// The remaining length is encoded 7 bits at a time
+	for {
+		digit := byte(length % 128)
+		length /= 128
+
+		if length > 0 {
+			digit |= 0x80
+		}
+
+		packet = append(packet, digit)
+
+		if length == 0 {
+			break
+		}
+	}
+
+	for _, part := range parts {
+		packet = append(packet, part...)
+	}
+
+	return packet
+}
+
+// mqttString encodes the given string prefixed with its length.
+func mqttString(str string) []byte {
+	return append([]byte{byte(len(str) >> 8), byte(len(str))}, str...)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// serveMQTT accepts one connection on the given listener like an MQTT broker
+// would, answering the CONNECT packet with the given return code and sending
+// all the packets received to the given channel.
+func serveMQTT(listener net.Listener, returnCode byte, packets chan<- []byte) {
+	conn, err := listener.Accept()
+
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		packet, err := readMQTTPacket(reader)
+
+		if err != nil {
+			close(packets)
+			return
+		}
+
+		if packet[0] == 0x10 {
+			conn.Write([]byte{0x20, 0x02, 0x00, returnCode})
+		}
+
+		packets <- packet
+	}
+}
+
+// readMQTTPacket reads a whole packet, including its fixed header.
+func readMQTTPacket(reader *bufio.Reader) ([]byte, error) {
+	header, err := reader.ReadByte()
+
+	if err != nil {
+		return nil, err
+	}
+
+	packet := []byte{header}
+	length := 0
+
+	for multiplier := 1; ; multiplier *= 128 {
+		digit, err := reader.ReadByte()
+
+		if err != nil {
+			return nil, err
+		}
+
+		packet = append(packet, digit)
+		length += int(digit&0x7F) * multiplier
+
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+
+	for read := 0; read < length; {
+		n, err := reader.Read(body[read:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		read += n
+	}
+
+	return append(packet, body...), nil
+}
+
+func TestMQTTPacketRemainingLength(t *testing.T) {
+	type scenario struct {
+		length int
+		header []byte
+	}
+
+	scenarios := []scenario{
+		{0, []byte{0x30, 0x00}},
+		{127, []byte{0x30, 0x7F}},
+		{128, []byte{0x30, 0x80, 0x01}},
+		{16383, []byte{0x30, 0xFF, 0x7F}},
+		{16384, []byte{0x30, 0x80, 0x80, 0x01}},
+	}
+
+	for _, scen := range scenarios {
+		payload := bytes.Repeat([]byte{'x'}, scen.length)
+		packet := mqttPacket(0x30, payload[:scen.length/2], payload[scen.length/2:])
+
+		if !bytes.Equal(packet[:len(scen.header)], scen.header) {
+			t.Errorf("Expected header % X for length %d, got % X", scen.header, scen.length, packet[:len(scen.header)])
+		}
+
+		if len(packet) != len(scen.header)+scen.length {
+			t.Errorf("Expected %d bytes for length %d, got %d", len(scen.header)+scen.length, scen.length, len(packet))
+		}
+	}
+}
+
+func TestMQTTString(t *testing.T) {
+	if got := mqttString("car/rpm"); !bytes.Equal(got, []byte("\x00\x07car/rpm")) {
+		t.Errorf("Unexpected string % X", got)
+	}
+
+	long := string(bytes.Repeat([]byte{'a'}, 300))
+
+	if got := mqttString(long); got[0] != 0x01 || got[1] != 0x2C || len(got) != 302 {
+		t.Errorf("Unexpected length prefix % X", got[:2])
+	}
+}
+
+func TestMQTTClientPublish(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	packets := make(chan []byte, 10)
+
+	go serveMQTT(listener, 0x00, packets)
+
+	client, err := dialMQTT(listener.Addr().String(), "car1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connect := <-packets
+	expected := []byte{
+		0x10, 0x10,
+		0x00, 0x04, 'M', 'Q', 'T', 'T', 0x04, 0x02, 0x00, 0x00,
+		0x00, 0x04, 'c', 'a', 'r', '1',
+	}
+
+	if !bytes.Equal(connect, expected) {
+		t.Fatalf("Expected CONNECT % X, got % X", expected, connect)
+	}
+
+	if err := client.Publish("car/rpm", 0, true, []byte("850")); err != nil {
+		t.Fatal(err)
+	}
+
+	publish := <-packets
+	expected = []byte{0x31, 0x0C, 0x00, 0x07, 'c', 'a', 'r', '/', 'r', 'p', 'm', '8', '5', '0'}
+
+	if !bytes.Equal(publish, expected) {
+		t.Fatalf("Expected PUBLISH % X, got % X", expected, publish)
+	}
+
+	if err := client.Publish("car/rpm", 1, false, nil); err == nil {
+		t.Error("Expected QoS 1 to fail")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if disconnect := <-packets; !bytes.Equal(disconnect, []byte{0xE0, 0x00}) {
+		t.Errorf("Expected DISCONNECT, got % X", disconnect)
+	}
+}
+
+func TestMQTTClientRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	go serveMQTT(listener, 0x05, make(chan []byte, 10))
+
+	if _, err := dialMQTT(listener.Addr().String(), "car1"); err == nil {
+		t.Fatal("Expected the refused connection to fail")
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rzetterberg/elmobd"
+)
+
+// sink is where the logger writes the results of the commands.
+type sink interface {
+	Write(result elmobd.CommandResult) error
+	Close() error
+}
+
+// openSinks opens the sinks of the given configuration.
+func openSinks(conf *config) ([]sink, error) {
+	sinks := []sink{}
+
+	if conf.Sinks.CSV != nil {
+		logger, err := elmobd.NewCSVLogger(conf.Sinks.CSV.Path)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to open CSV sink: %w", err)
+		}
+
+		logger.MaxSize = conf.Sinks.CSV.MaxSize
+		logger.MaxBackups = conf.Sinks.CSV.MaxBackups
+
+		sinks = append(sinks, &csvSink{logger})
+	}
+
+	if conf.Sinks.MQTT != nil {
+		clientID := conf.Sinks.MQTT.ClientID
+
+		if clientID == "" {
+			clientID = "obdlogger"
+		}
+
+		client, err := dialMQTT(conf.Sinks.MQTT.Broker, clientID)
+
+		if err != nil {
+			closeSinks(sinks)
+
+			return nil, fmt.Errorf("failed to open MQTT sink: %w", err)
+		}
+
+		pub := elmobd.NewMQTTPublisher(client, conf.Sinks.MQTT.TopicPrefix)
+		pub.Retained = conf.Sinks.MQTT.Retained
+
+		sinks = append(sinks, &mqttSink{client, pub})
+	}
+
+	if conf.Sinks.HTTP != nil {
+		queueSize := conf.Sinks.HTTP.QueueSize
+
+		if queueSize <= 0 {
+			queueSize = 1000
+		}
+
+		webhook := elmobd.NewWebhookSink(conf.Sinks.HTTP.URL, queueSize)
+
+		if conf.Sinks.HTTP.BatchSize > 0 {
+			webhook.BatchSize = conf.Sinks.HTTP.BatchSize
+		}
+
+		if conf.Sinks.HTTP.FlushInterval > 0 {
+			webhook.FlushInterval = time.Duration(conf.Sinks.HTTP.FlushInterval)
+		}
+
+		webhook.OnDrop = func(readings []elmobd.Reading, err error) {
+			logger.Printf("Dropped %d readings: %s", len(readings), err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+
+		go func() {
+			webhook.Run(ctx)
+			close(stopped)
+		}()
+
+		sinks = append(sinks, &httpSink{webhook, cancel, stopped})
+	}
+
+	return sinks, nil
+}
+
+// closeSinks closes the given sinks, logging the failures.
+func closeSinks(sinks []sink) {
+	for _, s := range sinks {
+		if err := s.Close(); err != nil {
+			logger.Println("Failed to close sink:", err)
+		}
+	}
+}
+
+type csvSink struct {
+	logger *elmobd.CSVLogger
+}
+
+func (s *csvSink) Write(result elmobd.CommandResult) error {
+	return s.logger.Log(result.Command)
+}
+
+func (s *csvSink) Close() error {
+	return s.logger.Close()
+}
+
+type mqttSink struct {
+	client *mqttClient
+	pub    *elmobd.MQTTPublisher
+}
+
+func (s *mqttSink) Write(result elmobd.CommandResult) error {
+	return s.pub.Publish(result.Command)
+}
+
+func (s *mqttSink) Close() error {
+	return s.client.Close()
+}
+
+// httpSink posts the readings in batches to the configured URL, delivering
+// the queued readings when closed.
+type httpSink struct {
+	webhook *elmobd.WebhookSink
+	stop    context.CancelFunc
+	stopped chan struct{}
+}
+
+func (s *httpSink) Write(result elmobd.CommandResult) error {
+	return s.webhook.PublishReading(result.Reading())
+}
+
+func (s *httpSink) Close() error {
+	s.stop()
+	<-s.stopped
+
+	return nil
+}
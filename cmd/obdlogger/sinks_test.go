@@ -0,0 +1,136 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rzetterberg/elmobd"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestOpenSinks(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	packets := make(chan []byte, 10)
+
+	go serveMQTT(listener, 0x00, packets)
+
+	posted := make(chan string, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posted <- string(body)
+	}))
+	defer server.Close()
+
+	conf := &config{}
+	conf.Sinks.CSV = &csvConfig{
+		Path:       filepath.Join(t.TempDir(), "readings.csv"),
+		MaxSize:    1024,
+		MaxBackups: 2,
+	}
+	conf.Sinks.MQTT = &mqttConfig{
+		Broker:      listener.Addr().String(),
+		TopicPrefix: "car",
+		Retained:    true,
+	}
+	conf.Sinks.HTTP = &httpConfig{
+		URL:           server.URL,
+		BatchSize:     10,
+		FlushInterval: duration(time.Minute),
+	}
+
+	sinks, err := openSinks(conf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sinks) != 3 {
+		t.Fatalf("Expected 3 sinks, got %d", len(sinks))
+	}
+
+	csv, ok := sinks[0].(*csvSink)
+
+	if !ok || csv.logger.MaxSize != 1024 || csv.logger.MaxBackups != 2 {
+		t.Errorf("Unexpected CSV sink %#v", sinks[0])
+	}
+
+	mqtt, ok := sinks[1].(*mqttSink)
+
+	if !ok || mqtt.client.clientID != "obdlogger" || !mqtt.pub.Retained {
+		t.Errorf("Unexpected MQTT sink %#v", sinks[1])
+	}
+
+	webhook, ok := sinks[2].(*httpSink)
+
+	if !ok || webhook.webhook.BatchSize != 10 || webhook.webhook.FlushInterval != time.Minute {
+		t.Errorf("Unexpected HTTP sink %#v", sinks[2])
+	}
+
+	speed := elmobd.NewVehicleSpeed()
+	speed.Value = 75
+
+	for _, s := range sinks {
+		if err := s.Write(elmobd.CommandResult{Command: speed, Time: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closeSinks(sinks)
+
+	content, err := os.ReadFile(conf.Sinks.CSV.Path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(content), ",vehicle_speed,75,km/h\n") {
+		t.Errorf("Expected the reading in the CSV file, got %q", content)
+	}
+
+	<-packets
+
+	if publish := <-packets; !strings.Contains(string(publish), "car/vehicle_speed") {
+		t.Errorf("Expected the reading to be published, got %q", publish)
+	}
+
+	if body := <-posted; !strings.Contains(body, `"key":"vehicle_speed"`) {
+		t.Errorf("Expected the reading to be posted, got %q", body)
+	}
+}
+
+func TestOpenSinksFailing(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing listens on the address of the broker anymore
+	listener.Close()
+
+	conf := &config{}
+	conf.Sinks.CSV = &csvConfig{Path: filepath.Join(t.TempDir(), "readings.csv")}
+	conf.Sinks.MQTT = &mqttConfig{Broker: listener.Addr().String()}
+
+	if _, err := openSinks(conf); err == nil || !strings.Contains(err.Error(), "MQTT sink") {
+		t.Fatalf("Expected the MQTT sink to fail, got %v", err)
+	}
+}
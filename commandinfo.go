@@ -0,0 +1,203 @@
+package elmobd
+
+/*==============================================================================
+ * External
+ */
+
+// CommandCategory represents the group a command belongs to, such as the
+// commands reading values of the engine.
+type CommandCategory string
+
+// The categories of the defined commands, see GetCommandInfo.
+const (
+	CategoryStatus        CommandCategory = "status"
+	CategoryEngine        CommandCategory = "engine"
+	CategoryFuel          CommandCategory = "fuel"
+	CategoryAir           CommandCategory = "air"
+	CategoryOxygenSensors CommandCategory = "oxygen_sensors"
+	CategoryEmissions     CommandCategory = "emissions"
+	CategoryTurbo         CommandCategory = "turbo"
+	CategoryVehicle       CommandCategory = "vehicle"
+)
+
+// CommandInfo represents the display information of a command, so that user
+// interfaces can list the commands without knowing each command type.
+type CommandInfo struct {
+	Key         string          `json:"key"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Category    CommandCategory `json:"category"`
+	Unit        string          `json:"unit,omitempty"`
+}
+
+// GetCommandInfo returns the display information of the given command, the
+// second return value is false if the command is unknown.
+func GetCommandInfo(cmd OBDCommand) (CommandInfo, bool) {
+	return GetCommandInfoByKey(cmd.Key())
+}
+
+// GetCommandInfoByKey returns the display information of the command with the
+// given key (see GetCommandKeys), the second return value is false if there
+// is no such command.
+func GetCommandInfoByKey(key string) (CommandInfo, bool) {
+	meta, ok := commandInfos[key]
+
+	if !ok {
+		return CommandInfo{}, false
+	}
+
+	return CommandInfo{
+		Key:         key,
+		Name:        meta.name,
+		Description: meta.description,
+		Category:    meta.category,
+		Unit:        commandUnits[key],
+	}, true
+}
+
+// GetCommandKeysByCategory returns the keys of the defined commands in the
+// given category, sorted alphabetically.
+func GetCommandKeysByCategory(category CommandCategory) []string {
+	var keys []string
+
+	for _, key := range GetCommandKeys() {
+		if commandInfos[key].category == category {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// commandMeta is the display information kept for each command.
+type commandMeta struct {
+	name        string
+	description string
+	category    CommandCategory
+}
+
+// commandInfos maps the keys of the defined commands to their display
+// information.
+var commandInfos = map[string]commandMeta{
+	"monitor_status":                         {"Monitor status", "MIL status, amount of trouble codes and readiness of the on-board monitors", CategoryStatus},
+	"engine_load":                            {"Engine load", "Calculated engine load", CategoryEngine},
+	"fuel":                                   {"Fuel level", "Fuel tank level", CategoryFuel},
+	"dist_since_dtc_clean":                   {"Distance since codes cleared", "Distance traveled since the trouble codes were cleared", CategoryStatus},
+	"odometer":                               {"Odometer", "Total distance traveled by the vehicle", CategoryVehicle},
+	"transmission_actual_gear":               {"Transmission gear ratio", "Actual gear ratio of the transmission", CategoryVehicle},
+	"coolant_temperature":                    {"Coolant temperature", "Engine coolant temperature", CategoryEngine},
+	"short_term_fuel_trim_bank1":             {"Short term fuel trim bank 1", "Short term fuel trim of bank 1", CategoryFuel},
+	"long_term_fuel_trim_bank1":              {"Long term fuel trim bank 1", "Long term fuel trim of bank 1", CategoryFuel},
+	"short_term_fuel_trim_bank2":             {"Short term fuel trim bank 2", "Short term fuel trim of bank 2", CategoryFuel},
+	"long_term_fuel_trim_bank2":              {"Long term fuel trim bank 2", "Long term fuel trim of bank 2", CategoryFuel},
+	"fuel_pressure":                          {"Fuel pressure", "Fuel pressure (gauge)", CategoryFuel},
+	"intake_manifold_pressure":               {"Intake manifold pressure", "Absolute pressure in the intake manifold", CategoryAir},
+	"engine_rpm":                             {"Engine RPM", "Engine speed", CategoryEngine},
+	"vehicle_speed":                          {"Vehicle speed", "Speed of the vehicle", CategoryVehicle},
+	"timing_advance":                         {"Timing advance", "Ignition timing advance before top dead center", CategoryEngine},
+	"intake_air_temperature":                 {"Intake air temperature", "Temperature of the intake air", CategoryAir},
+	"maf_air_flow_rate":                      {"MAF air flow rate", "Air flow rate measured by the mass air flow sensor", CategoryAir},
+	"throttle_position":                      {"Throttle position", "Absolute throttle position", CategoryAir},
+	"obd_standards":                          {"OBD standards", "OBD standards the vehicle conforms to", CategoryStatus},
+	"runtime_since_engine_start":             {"Run time since engine start", "Time since the engine was started", CategoryEngine},
+	"control_module_voltage":                 {"Control module voltage", "Supply voltage of the control module", CategoryVehicle},
+	"ambient_temperature":                    {"Ambient air temperature", "Temperature of the outside air", CategoryVehicle},
+	"engine_oil_temperature":                 {"Engine oil temperature", "Temperature of the engine oil", CategoryEngine},
+	"absolute_barometric_pressure":           {"Barometric pressure", "Absolute barometric pressure", CategoryAir},
+	"commanded_secondary_air_status":         {"Secondary air status", "Commanded state of the secondary air system", CategoryEmissions},
+	"o2_sensors_present":                     {"Oxygen sensors present", "Which oxygen sensors are present, in 2 banks", CategoryOxygenSensors},
+	"o2_sensors_present_4_banks":             {"Oxygen sensors present (4 banks)", "Which oxygen sensors are present, in 4 banks", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank1_sensor1":        {"Oxygen sensor voltage B1S1", "Voltage and short term fuel trim of the oxygen sensor in bank 1, sensor 1", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank1_sensor2":        {"Oxygen sensor voltage B1S2", "Voltage and short term fuel trim of the oxygen sensor in bank 1, sensor 2", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank1_sensor3":        {"Oxygen sensor voltage B1S3", "Voltage and short term fuel trim of the oxygen sensor in bank 1, sensor 3", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank1_sensor4":        {"Oxygen sensor voltage B1S4", "Voltage and short term fuel trim of the oxygen sensor in bank 1, sensor 4", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank2_sensor1":        {"Oxygen sensor voltage B2S1", "Voltage and short term fuel trim of the oxygen sensor in bank 2, sensor 1", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank2_sensor2":        {"Oxygen sensor voltage B2S2", "Voltage and short term fuel trim of the oxygen sensor in bank 2, sensor 2", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank2_sensor3":        {"Oxygen sensor voltage B2S3", "Voltage and short term fuel trim of the oxygen sensor in bank 2, sensor 3", CategoryOxygenSensors},
+	"o2_sensor_voltage_bank2_sensor4":        {"Oxygen sensor voltage B2S4", "Voltage and short term fuel trim of the oxygen sensor in bank 2, sensor 4", CategoryOxygenSensors},
+	"dist_with_mil_on":                       {"Distance with MIL on", "Distance traveled with the malfunction indicator lamp on", CategoryStatus},
+	"fuel_rail_pressure":                     {"Fuel rail pressure", "Fuel rail pressure relative to the manifold vacuum", CategoryFuel},
+	"fuel_rail_gauge_pressure":               {"Fuel rail gauge pressure", "Fuel rail pressure of diesel or direct injection engines", CategoryFuel},
+	"o2_sensor_lambda_voltage_bank1_sensor1": {"Wideband oxygen sensor voltage B1S1", "Lambda and voltage of the wideband oxygen sensor in bank 1, sensor 1", CategoryOxygenSensors},
+	"o2_sensor_lambda_voltage_bank1_sensor2": {"Wideband oxygen sensor voltage B1S2", "Lambda and voltage of the wideband oxygen sensor in bank 1, sensor 2", CategoryOxygenSensors},
+	"o2_sensor_lambda_voltage_bank1_sensor3": {"Wideband oxygen sensor voltage B1S3", "Lambda and voltage of the wideband oxygen sensor in bank 1, sensor 3", CategoryOxygenSensors},
+	"o2_sensor_lambda_voltage_bank1_sensor4": {"Wideband oxygen sensor voltage B1S4", "Lambda and voltage of the wideband oxygen sensor in bank 1, sensor 4", CategoryOxygenSensors},
+	"o2_sensor_lambda_voltage_bank2_sensor1": {"Wideband oxygen sensor voltage B2S1", "Lambda and voltage of the wideband oxygen sensor in bank 2, sensor 1", CategoryOxygenSensors},
+	"o2_sensor_lambda_voltage_bank2_sensor2": {"Wideband oxygen sensor voltage B2S2", "Lambda and voltage of the wideband oxygen sensor in bank 2, sensor 2", CategoryOxygenSensors},
+	"o2_sensor_lambda_voltage_bank2_sensor3": {"Wideband oxygen sensor voltage B2S3", "Lambda and voltage of the wideband oxygen sensor in bank 2, sensor 3", CategoryOxygenSensors},
+	"o2_sensor_lambda_voltage_bank2_sensor4": {"Wideband oxygen sensor voltage B2S4", "Lambda and voltage of the wideband oxygen sensor in bank 2, sensor 4", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank1_sensor1": {"Wideband oxygen sensor current B1S1", "Lambda and current of the wideband oxygen sensor in bank 1, sensor 1", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank1_sensor2": {"Wideband oxygen sensor current B1S2", "Lambda and current of the wideband oxygen sensor in bank 1, sensor 2", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank1_sensor3": {"Wideband oxygen sensor current B1S3", "Lambda and current of the wideband oxygen sensor in bank 1, sensor 3", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank1_sensor4": {"Wideband oxygen sensor current B1S4", "Lambda and current of the wideband oxygen sensor in bank 1, sensor 4", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank2_sensor1": {"Wideband oxygen sensor current B2S1", "Lambda and current of the wideband oxygen sensor in bank 2, sensor 1", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank2_sensor2": {"Wideband oxygen sensor current B2S2", "Lambda and current of the wideband oxygen sensor in bank 2, sensor 2", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank2_sensor3": {"Wideband oxygen sensor current B2S3", "Lambda and current of the wideband oxygen sensor in bank 2, sensor 3", CategoryOxygenSensors},
+	"o2_sensor_lambda_current_bank2_sensor4": {"Wideband oxygen sensor current B2S4", "Lambda and current of the wideband oxygen sensor in bank 2, sensor 4", CategoryOxygenSensors},
+	"commanded_egr":                          {"Commanded EGR", "Commanded exhaust gas recirculation", CategoryEmissions},
+	"egr_error":                              {"EGR error", "Error of the exhaust gas recirculation against the commanded value", CategoryEmissions},
+	"commanded_evaporative_purge":            {"Commanded evaporative purge", "Commanded opening of the evaporative purge valve", CategoryEmissions},
+	"warm_ups_since_dtc_clear":               {"Warm-ups since codes cleared", "Warm-up cycles since the trouble codes were cleared", CategoryStatus},
+	"evap_vapor_pressure":                    {"Evap vapor pressure", "Vapor pressure of the evaporative system", CategoryEmissions},
+	"absolute_evap_vapor_pressure":           {"Absolute evap vapor pressure", "Absolute vapor pressure of the evaporative system", CategoryEmissions},
+	"evap_vapor_pressure_wide":               {"Evap vapor pressure (wide range)", "Vapor pressure of the evaporative system, wide range", CategoryEmissions},
+	"monitor_status_this_drive_cycle":        {"Monitor status this drive cycle", "State of the on-board monitors during the current drive cycle", CategoryStatus},
+	"absolute_load":                          {"Absolute load", "Absolute load value of the engine", CategoryEngine},
+	"commanded_equivalence_ratio":            {"Commanded air-fuel equivalence ratio", "Commanded lambda of the air-fuel mixture", CategoryFuel},
+	"relative_throttle_position":             {"Relative throttle position", "Throttle position relative to the learned closed position", CategoryAir},
+	"absolute_throttle_position_b":           {"Absolute throttle position B", "Absolute position of throttle sensor B", CategoryAir},
+	"absolute_throttle_position_c":           {"Absolute throttle position C", "Absolute position of throttle sensor C", CategoryAir},
+	"accelerator_pedal_position_d":           {"Accelerator pedal position D", "Position of accelerator pedal sensor D", CategoryAir},
+	"accelerator_pedal_position_e":           {"Accelerator pedal position E", "Position of accelerator pedal sensor E", CategoryAir},
+	"accelerator_pedal_position_f":           {"Accelerator pedal position F", "Position of accelerator pedal sensor F", CategoryAir},
+	"commanded_throttle_actuator":            {"Commanded throttle actuator", "Commanded position of the throttle actuator", CategoryAir},
+	"time_with_mil_on":                       {"Time with MIL on", "Time run with the malfunction indicator lamp on", CategoryStatus},
+	"time_since_dtc_clear":                   {"Time since codes cleared", "Time since the trouble codes were cleared", CategoryStatus},
+	"maximum_values":                         {"Maximum values", "Maximum values of lambda, oxygen sensor voltage and current and intake manifold pressure", CategoryEngine},
+	"maximum_maf_air_flow_rate":              {"Maximum MAF air flow rate", "Maximum value of the mass air flow sensor", CategoryAir},
+	"fuel_type":                              {"Fuel type", "Type of fuel the vehicle uses", CategoryFuel},
+	"ethanol_fuel":                           {"Ethanol fuel", "Percentage of ethanol in the fuel", CategoryFuel},
+	"relative_accelerator_pedal_position":    {"Relative accelerator pedal position", "Accelerator pedal position relative to the learned released position", CategoryAir},
+	"hybrid_battery_remaining_life":          {"Hybrid battery remaining life", "Remaining life of the hybrid battery pack", CategoryVehicle},
+	"fuel_injection_timing":                  {"Fuel injection timing", "Timing of the fuel injection", CategoryFuel},
+	"engine_fuel_rate":                       {"Engine fuel rate", "Fuel consumed by the engine per hour", CategoryFuel},
+	"driver_demand_torque":                   {"Driver demand torque", "Torque requested by the driver", CategoryEngine},
+	"actual_engine_torque":                   {"Actual engine torque", "Torque produced by the engine", CategoryEngine},
+	"engine_reference_torque":                {"Engine reference torque", "Reference torque the percent torques are relative to", CategoryEngine},
+	"engine_percent_torque_data":             {"Engine percent torque data", "Engine torque at idle and at the engine points", CategoryEngine},
+	"auxiliary_input_output":                 {"Auxiliary inputs and outputs", "Status of power take-off, neutral switches and glow plug lamp", CategoryVehicle},
+	"maf_air_flow_rate_sensors":              {"MAF air flow rate per sensor", "Air flow rate of each mass air flow sensor", CategoryAir},
+	"coolant_temperature_sensors":            {"Coolant temperature per sensor", "Temperature of each engine coolant temperature sensor", CategoryEngine},
+	"intake_air_temperature_sensors":         {"Intake air temperature per sensor", "Temperature of each intake air temperature sensor", CategoryAir},
+	"fuel_pressure_control":                  {"Fuel pressure control", "Commanded and actual fuel rail pressure and fuel temperature", CategoryFuel},
+	"boost_pressure_control":                 {"Boost pressure control", "Commanded and actual boost pressure of each turbocharger", CategoryTurbo},
+	"variable_geometry_turbo_control":        {"Variable geometry turbo control", "Commanded and actual vane position of each turbocharger", CategoryTurbo},
+	"wastegate_control":                      {"Wastegate control", "Commanded and actual wastegate position of each turbocharger", CategoryTurbo},
+	"exhaust_pressure":                       {"Exhaust pressure", "Exhaust pressure of each bank", CategoryEmissions},
+	"turbocharger_rpm":                       {"Turbocharger RPM", "Shaft speed of each turbocharger", CategoryTurbo},
+	"turbocharger_a_temperature":             {"Turbocharger A temperature", "Compressor and turbine temperatures of turbocharger A", CategoryTurbo},
+	"turbocharger_b_temperature":             {"Turbocharger B temperature", "Compressor and turbine temperatures of turbocharger B", CategoryTurbo},
+	"charge_air_cooler_temperature":          {"Charge air cooler temperature", "Temperature of each charge air cooler sensor", CategoryTurbo},
+	"exhaust_gas_temperature_bank1":          {"Exhaust gas temperature bank 1", "Temperature of each exhaust gas temperature sensor of bank 1", CategoryEmissions},
+	"exhaust_gas_temperature_bank2":          {"Exhaust gas temperature bank 2", "Temperature of each exhaust gas temperature sensor of bank 2", CategoryEmissions},
+	"particulate_filter_pressure_bank1":      {"Particulate filter pressure bank 1", "Differential, inlet and outlet pressure of the particulate filter of bank 1", CategoryEmissions},
+	"particulate_filter_pressure_bank2":      {"Particulate filter pressure bank 2", "Differential, inlet and outlet pressure of the particulate filter of bank 2", CategoryEmissions},
+	"particulate_filter_temperature":         {"Particulate filter temperature", "Inlet and outlet temperature of the particulate filter of each bank", CategoryEmissions},
+	"nox_sensor":                             {"NOx sensor", "NOx concentration measured by each NOx sensor", CategoryEmissions},
+	"particulate_matter_sensor":              {"Particulate matter sensor", "Particulate matter concentration measured by each sensor", CategoryEmissions},
+	"nox_reagent_system":                     {"NOx reagent system", "Reagent (DEF) consumption, tank level and NOx warning time", CategoryEmissions},
+	"scr_inducement_system":                  {"SCR inducement system", "Status of the SCR inducement system and the distances it was active", CategoryEmissions},
+	"diesel_exhaust_fluid_sensor":            {"Diesel exhaust fluid sensor", "Concentration, temperature and tank level of the diesel exhaust fluid", CategoryEmissions},
+	"engine_run_time":                        {"Engine run time", "Total run time of the engine, while idling and with power take-off active", CategoryEngine},
+	"engine_run_time_aecd_1_5":               {"Engine run time AECD #1-#5", "Run time of the auxiliary emission control devices #1 to #5", CategoryEmissions},
+	"engine_run_time_aecd_6_10":              {"Engine run time AECD #6-#10", "Run time of the auxiliary emission control devices #6 to #10", CategoryEmissions},
+	"engine_friction_torque":                 {"Engine friction torque", "Torque lost to the friction of the engine", CategoryEngine},
+	"cylinder_fuel_rate":                     {"Cylinder fuel rate", "Fuel injected into a cylinder per intake stroke", CategoryFuel},
+	"hybrid_system_data":                     {"Hybrid/EV system data", "Charging mode, voltage and current of the hybrid or EV battery system", CategoryVehicle},
+	"engine_fuel_mass_rate":                  {"Engine fuel mass rate", "Fuel mass burned by the engine and by the whole vehicle", CategoryFuel},
+	"exhaust_flow_rate":                      {"Exhaust flow rate", "Mass flow rate of the exhaust gas", CategoryEmissions},
+}
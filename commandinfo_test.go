@@ -0,0 +1,49 @@
+package elmobd
+
+import (
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestCommandInfoDefinedForAllCommands(t *testing.T) {
+	for _, key := range GetCommandKeys() {
+		info, ok := GetCommandInfoByKey(key)
+
+		assert(t, ok, "info is defined for "+key)
+		assert(t, info.Name != "" && info.Description != "", "info is filled in for "+key)
+		assert(t, info.Category != "", "category is set for "+key)
+	}
+
+	_, ok := GetCommandInfoByKey("unknown")
+
+	assertEqual(t, ok, false)
+}
+
+func TestGetCommandInfo(t *testing.T) {
+	info, ok := GetCommandInfo(NewEngineRPM())
+
+	assertEqual(t, ok, true)
+	assertEqual(t, info, CommandInfo{
+		Key:         "engine_rpm",
+		Name:        "Engine RPM",
+		Description: "Engine speed",
+		Category:    CategoryEngine,
+		Unit:        "rpm",
+	})
+}
+
+func TestGetCommandKeysByCategory(t *testing.T) {
+	keys := GetCommandKeysByCategory(CategoryTurbo)
+
+	assertEqual(t, len(keys), 7)
+	assertEqual(t, keys[0], "boost_pressure_control")
+
+	for _, key := range keys {
+		info, _ := GetCommandInfoByKey(key)
+
+		assertEqual(t, info.Category, CategoryTurbo)
+	}
+}
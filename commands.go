@@ -1,12 +1,19 @@
 package elmobd
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 )
 
 const SERVICE_01_ID = 0x01
+const SERVICE_02_ID = 0x02
+const SERVICE_03_ID = 0x03
 const SERVICE_04_ID = 0x04
+const SERVICE_06_ID = 0x06
+const SERVICE_09_ID = 0x09
+const SERVICE_22_ID = 0x22
 
 /*==============================================================================
  * Generic types
@@ -27,6 +34,18 @@ type OBDCommand interface {
 	ToCommand() string
 }
 
+// VariableWidthCommand is implemented by commands whose responses vary in
+// length, such as the oxygen sensors present or the strings of service 09.
+// The response is valid when its payload is between the minimum and maximum
+// amount of bytes, inclusive. DataWidth is still used by ToCommand for the
+// amount of responses to wait for, so it should return the maximum amount.
+//
+// Such commands get their payload with Result.Payload.
+type VariableWidthCommand interface {
+	OBDCommand
+	DataWidthRange() (int, int)
+}
+
 // baseCommand is a simple struct with the 3 members that all OBDCommands
 // will have in common.
 type baseCommand struct {
@@ -80,8 +99,30 @@ func (cmd *baseCommand) ToCommand() string {
 
 // FloatCommand is just a shortcut for commands that retrieve floating point
 // values from the ELM327 device.
+//
+// The value is kept both as the float32 Value and as a float64, which is
+// retrieved using Float64. Use Float64 when the precision matters, such as
+// when calculating other values from the value.
 type FloatCommand struct {
-	Value float32
+	Value   float32
+	value64 float64
+}
+
+// SetFloat64 sets the value of the command, keeping the full precision of the
+// given value for Float64.
+func (cmd *FloatCommand) SetFloat64(val float64) {
+	cmd.Value = float32(val)
+	cmd.value64 = val
+}
+
+// Float64 retrieves the value with full precision. If Value has been assigned
+// directly since the last call to SetFloat64, Value is returned as float64.
+func (cmd *FloatCommand) Float64() float64 {
+	if float32(cmd.value64) != cmd.Value {
+		return float64(cmd.Value)
+	}
+
+	return cmd.value64
 }
 
 // ValueAsLit retrieves the value as a literal representation.
@@ -89,6 +130,12 @@ func (cmd *FloatCommand) ValueAsLit() string {
 	return fmt.Sprintf("%f", cmd.Value)
 }
 
+// value retrieves the value with full precision, used when exporting
+// readings.
+func (cmd *FloatCommand) value() interface{} {
+	return cmd.Float64()
+}
+
 // IntCommand is just a shortcut for commands that retrieve integer
 // values from the ELM327 device.
 type IntCommand struct {
@@ -100,6 +147,11 @@ func (cmd *IntCommand) ValueAsLit() string {
 	return fmt.Sprintf("%d", cmd.Value)
 }
 
+// value retrieves the value as it is, used when exporting readings.
+func (cmd *IntCommand) value() interface{} {
+	return cmd.Value
+}
+
 // UIntCommand is just a shortcut for commands that retrieve unsigned
 // integer values from the ELM327 device.
 type UIntCommand struct {
@@ -111,6 +163,11 @@ func (cmd *UIntCommand) ValueAsLit() string {
 	return fmt.Sprintf("%d", cmd.Value)
 }
 
+// value retrieves the value as it is, used when exporting readings.
+func (cmd *UIntCommand) value() interface{} {
+	return cmd.Value
+}
+
 /*==============================================================================
  * Specific types
  */
@@ -118,7 +175,7 @@ func (cmd *UIntCommand) ValueAsLit() string {
 // PartSupported represents a command that checks which 31 PIDs are supported
 // of a part.
 //
-// All PIDs are divided into parts 7 parts with the following PIDs:
+// All PIDs are divided into 8 parts with the following PIDs:
 //
 // - Part 1 (0x00): 0x01 to 0x20
 // - Part 2 (0x20): 0x21 to 0x40
@@ -127,12 +184,14 @@ func (cmd *UIntCommand) ValueAsLit() string {
 // - Part 5 (0x80): 0x81 to 0xA0
 // - Part 6 (0xA0): 0xA1 to 0xC0
 // - Part 7 (0xC0): 0xC1 to 0xE0
+// - Part 8 (0xE0): 0xE1 to 0xFF
 //
 // PID 0x00 checks which PIDs that are supported of part 1, after that, the
 // last PID of each part checks the whether the next part is supported.
 //
 // So PID 0x20 of Part 1 checks which PIDs in part 2 are supported, PID 0x40 of
-// part 2 checks which PIDs in part 3 are supported, etc etc.
+// part 2 checks which PIDs in part 3 are supported, etc etc. There is no PID
+// checking a part after part 8.
 type PartSupported struct {
 	baseCommand
 	UIntCommand
@@ -142,12 +201,15 @@ type PartSupported struct {
 // PartRange represents how many PIDs there are in one part
 const PartRange = 0x20
 
+// PartsAmount represents how many parts there are, see PartSupported
+const PartsAmount = 8
+
 // NewPartSupported creates a new PartSupported.
 func NewPartSupported(index byte) *PartSupported {
 	if index < 1 {
 		index = 1
-	} else if index > 7 {
-		index = 7
+	} else if index > PartsAmount {
+		index = PartsAmount
 	}
 
 	pid := OBDParameterID((index - 1) * PartRange)
@@ -185,10 +247,10 @@ func (part *PartSupported) SetRawValue(val uint32) {
 //
 // If the given command has a PID of 0x31 then this function returns false.
 func (part *PartSupported) PIDInRange(comparePID OBDParameterID) bool {
-	endPID := OBDParameterID(part.index * PartRange)
-	startPID := OBDParameterID(endPID-PartRange) + 1
+	endPID := int(part.index) * PartRange
+	startPID := endPID - PartRange + 1
 
-	return startPID <= comparePID && comparePID <= endPID
+	return startPID <= int(comparePID) && int(comparePID) <= endPID
 }
 
 // CommandInRange checks if the PID of the given command is in range of the
@@ -246,7 +308,7 @@ func (part *PartSupported) CommandInRange(cmd OBDCommand) bool {
 // the information from the bit.
 //
 // This works well for checking if part 1 supported PIDs between 0x1 and 0x20,
-// but it fails for parts 2,3,4,5,6,7 and PIDs about 0x20.
+// but it fails for parts 2,3,4,5,6,7,8 and PIDs about 0x20.
 //
 // In order to make this work for other parts besides 1, we simply normalize the
 // PID number by removing 32 times the part index, instead of hard coding the value
@@ -256,7 +318,7 @@ func (part *PartSupported) SupportsPID(comparePID OBDParameterID) bool {
 		return false
 	}
 
-	offset := uint32(32 * part.index)
+	offset := uint32(part.index) * 32
 	bitsToShift := offset - uint32(comparePID)
 	result := (part.Value >> bitsToShift) & 1
 
@@ -265,8 +327,12 @@ func (part *PartSupported) SupportsPID(comparePID OBDParameterID) bool {
 
 // SupportsNextPart checks if the PID that is used to check the next part is
 // supported. This PID is always the last PID of the current part, which means
-// we can simply check if the D0 bit is set.
+// we can simply check if the D0 bit is set. The last part has no next part.
 func (part *PartSupported) SupportsNextPart() bool {
+	if part.index >= PartsAmount {
+		return false
+	}
+
 	result := part.Value & 1
 
 	return result == 1
@@ -284,22 +350,82 @@ func (part *PartSupported) Index() byte {
 	return part.index
 }
 
+// NewMonitorIDsSupported creates a new PartSupported that checks which
+// on-board monitor IDs (MIDs) of service 06 are supported, in the part with
+// the given index. The parts are divided like the parts of service 01, so
+// part 1 checks MIDs 0x01 to 0x20.
+func NewMonitorIDsSupported(index byte) *PartSupported {
+	if index < 1 {
+		index = 1
+	} else if index > PartsAmount {
+		index = PartsAmount
+	}
+
+	mid := OBDParameterID((index - 1) * PartRange)
+
+	return &PartSupported{
+		baseCommand{SERVICE_06_ID, mid, 4, fmt.Sprintf("supported_monitor_ids_part%d", index)},
+		UIntCommand{},
+		index,
+	}
+}
+
+// The PIDs of the vehicle information of service 09, see
+// NewVehicleInfoSupported.
+const (
+	VehicleInfoVIN                            OBDParameterID = 0x02
+	VehicleInfoCalibrationID                  OBDParameterID = 0x04
+	VehicleInfoCVN                            OBDParameterID = 0x06
+	VehicleInfoPerformanceTracking            OBDParameterID = 0x08
+	VehicleInfoECUName                        OBDParameterID = 0x0A
+	VehicleInfoPerformanceTrackingCompression OBDParameterID = 0x0B
+)
+
+// NewVehicleInfoSupported creates a new PartSupported that checks which PIDs
+// of service 09 (vehicle information) are supported, such as the VIN (see
+// VehicleInfoVIN). Service 09 only has one part, so SupportsPID checks PIDs
+// 0x01 to 0x20.
+func NewVehicleInfoSupported() *PartSupported {
+	return &PartSupported{
+		baseCommand{SERVICE_09_ID, 0, 4, "supported_vehicle_info"},
+		UIntCommand{},
+		1,
+	}
+}
+
 // MonitorStatus represents a command that checks the status since DTCs
-// were cleared last time. This includes the MIL status and the amount of
-// DTCs.
+// were cleared last time. This includes the MIL status, the amount of DTCs
+// and the readiness of the on-board monitors, which tells whether the
+// emission related systems have been tested since the DTCs were cleared.
 type MonitorStatus struct {
 	baseCommand
 	MilActive bool
 	DtcAmount byte
+	Monitors
 }
 
 // ValueAsLit retrieves the value as a literal representation.
 func (cmd *MonitorStatus) ValueAsLit() string {
-	return fmt.Sprintf(
-		"{\"mil_active\": %t, \"dts_amount\": %d",
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *MonitorStatus) value() interface{} {
+	return struct {
+		MilActive bool     `json:"mil_active"`
+		DtcAmount byte     `json:"dtc_amount"`
+		Monitors  Monitors `json:"monitors"`
+	}{
 		cmd.MilActive,
 		cmd.DtcAmount,
-	)
+		cmd.Monitors,
+	}
 }
 
 // NewMonitorStatus creates a new MonitorStatus.
@@ -308,26 +434,24 @@ func NewMonitorStatus() *MonitorStatus {
 		baseCommand{SERVICE_01_ID, 1, 4, "monitor_status"},
 		false,
 		0,
+		Monitors{},
 	}
 }
 
 // SetValue processes the byte array value into the right unsigned
 // integer value.
 func (cmd *MonitorStatus) SetValue(result *Result) error {
-	expAmount := 4
-	payload := result.value[2:]
-	amount := len(payload)
+	payload, err := monitorPayload(result)
 
-	if amount != expAmount {
-		return fmt.Errorf(
-			"Expected %d bytes of payload, got %d", expAmount, amount,
-		)
+	if err != nil {
+		return err
 	}
 
 	// 0x80 is the MSB: 0b10000000
 	cmd.MilActive = (payload[0] & 0x80) == 0x80
 	// 0x7F everything but the MSB: 0b01111111
 	cmd.DtcAmount = byte(payload[0] & 0x7F)
+	cmd.Monitors = decodeMonitors(payload[1], payload[2], payload[3])
 
 	return nil
 }
@@ -357,7 +481,7 @@ func (cmd *EngineLoad) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload) / 255
+	cmd.SetFloat64(float64(payload) / 255)
 
 	return nil
 }
@@ -387,7 +511,7 @@ func (cmd *Fuel) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload) / 255
+	cmd.SetFloat64(float64(payload) / 255)
 
 	return nil
 }
@@ -447,7 +571,7 @@ func (cmd *Odometer) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload) / 10
+	cmd.SetFloat64(float64(payload) / 10)
 
 	return nil
 }
@@ -477,7 +601,7 @@ func (cmd *TransmissionActualGear) SetValue(result *Result) error {
 		return err
 	}
 	// A & B are not used in the calculation
-	cmd.Value = float32(payload>>16) / 1000
+	cmd.SetFloat64(float64(payload>>16) / 1000)
 
 	return nil
 }
@@ -530,7 +654,7 @@ func (cmd *fuelTrim) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = (float32(payload) / 1.28) - 100
+	cmd.SetFloat64((float64(payload) / 1.28) - 100)
 
 	return nil
 }
@@ -686,7 +810,7 @@ func (cmd *EngineRPM) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload) / 4
+	cmd.SetFloat64(float64(payload) / 4)
 
 	return nil
 }
@@ -750,7 +874,7 @@ func (cmd *TimingAdvance) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload/2) - 64
+	cmd.SetFloat64(float64(payload)/2 - 64)
 
 	return nil
 }
@@ -815,7 +939,7 @@ func (cmd *MafAirFlowRate) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload) / 100
+	cmd.SetFloat64(float64(payload) / 100)
 
 	return nil
 }
@@ -824,7 +948,7 @@ func (cmd *MafAirFlowRate) SetValue(result *Result) error {
 // percentage.
 //
 // Min: 0.0
-// Max: 100.0
+// Max: 1.0
 type ThrottlePosition struct {
 	baseCommand
 	FloatCommand
@@ -846,49 +970,138 @@ func (cmd *ThrottlePosition) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload) / 255
+	cmd.SetFloat64(float64(payload) / 255)
 
 	return nil
 }
 
+// OBDStandard represents an OBD standard a vehicle conforms to, see
+// OBDStandards.
+type OBDStandard byte
+
+// The OBD standards, as defined by SAE J1979. The values missing are
+// reserved.
+const (
+	OBDStandardOBDIICARB        OBDStandard = 1
+	OBDStandardOBDEPA           OBDStandard = 2
+	OBDStandardOBDAndOBDII      OBDStandard = 3
+	OBDStandardOBDI             OBDStandard = 4
+	OBDStandardNotCompliant     OBDStandard = 5
+	OBDStandardEOBD             OBDStandard = 6
+	OBDStandardEOBDAndOBDII     OBDStandard = 7
+	OBDStandardEOBDAndOBD       OBDStandard = 8
+	OBDStandardEOBDOBDAndOBDII  OBDStandard = 9
+	OBDStandardJOBD             OBDStandard = 10
+	OBDStandardJOBDAndOBDII     OBDStandard = 11
+	OBDStandardJOBDAndEOBD      OBDStandard = 12
+	OBDStandardJOBDEOBDAndOBDII OBDStandard = 13
+	OBDStandardEMD              OBDStandard = 17
+	OBDStandardEMDPlus          OBDStandard = 18
+	OBDStandardHDOBDC           OBDStandard = 19
+	OBDStandardHDOBD            OBDStandard = 20
+	OBDStandardWWHOBD           OBDStandard = 21
+	OBDStandardHDEOBDI          OBDStandard = 23
+	OBDStandardHDEOBDIN         OBDStandard = 24
+	OBDStandardHDEOBDII         OBDStandard = 25
+	OBDStandardHDEOBDIIN        OBDStandard = 26
+	OBDStandardOBDBr1           OBDStandard = 28
+	OBDStandardOBDBr2           OBDStandard = 29
+	OBDStandardKOBD             OBDStandard = 30
+	OBDStandardIOBDI            OBDStandard = 31
+	OBDStandardIOBDII           OBDStandard = 32
+	OBDStandardHDEOBDIV         OBDStandard = 33
+)
+
+var obdStandardNames = []string{
+	"",
+	"OBD-II as defined by the CARB",
+	"OBD as defined by the EPA",
+	"OBD and OBD-II",
+	"OBD-I",
+	"Not OBD compliant",
+	"EOBD (Europe)",
+	"EOBD and OBD-II",
+	"EOBD and OBD",
+	"EOBD, OBD and OBD-II",
+	"JOBD (Japan)",
+	"JOBD and OBD-II",
+	"JOBD and EOBD",
+	"JOBD, EOBD and OBD-II",
+	"",
+	"",
+	"",
+	"Engine Manufacturer Diagnostics (EMD)",
+	"Engine Manufacturer Diagnostics Enhanced (EMD+)",
+	"Heavy Duty On-Board Diagnostics (Child/Partial) (HD OBD-C)",
+	"Heavy Duty On-Board Diagnostics (HD OBD)",
+	"World Wide Harmonized OBD (WWH OBD)",
+	"",
+	"Heavy Duty Euro OBD Stage I without NOx control (HD EOBD-I)",
+	"Heavy Duty Euro OBD Stage I with NOx control (HD EOBD-I N)",
+	"Heavy Duty Euro OBD Stage II without NOx control (HD EOBD-II)",
+	"Heavy Duty Euro OBD Stage II with NOx control (HD EOBD-II N)",
+	"",
+	"Brazil OBD Phase 1 (OBDBr-1)",
+	"Brazil OBD Phase 2 (OBDBr-2)",
+	"Korean OBD (KOBD)",
+	"India OBD I (IOBD I)",
+	"India OBD II (IOBD II)",
+	"Heavy Duty Euro OBD Stage VI (HD EOBD-IV)",
+}
+
+// String returns the description of the standard, such as "EOBD and
+// OBD-II".
+func (std OBDStandard) String() string {
+	if int(std) < len(obdStandardNames) && obdStandardNames[std] != "" {
+		return obdStandardNames[std]
+	}
+
+	if std >= 251 {
+		return fmt.Sprintf("not available (%d)", byte(std))
+	}
+
+	return fmt.Sprintf("reserved (%d)", byte(std))
+}
+
+// IsOBDII checks if the standard includes OBD-II.
+func (std OBDStandard) IsOBDII() bool {
+	switch std {
+	case OBDStandardOBDIICARB,
+		OBDStandardOBDAndOBDII,
+		OBDStandardEOBDAndOBDII,
+		OBDStandardEOBDOBDAndOBDII,
+		OBDStandardJOBDAndOBDII,
+		OBDStandardJOBDEOBDAndOBDII:
+		return true
+	}
+
+	return false
+}
+
+// IsEOBD checks if the standard includes EOBD, the European standard, which
+// includes the heavy duty variants of it.
+func (std OBDStandard) IsEOBD() bool {
+	switch std {
+	case OBDStandardEOBD,
+		OBDStandardEOBDAndOBDII,
+		OBDStandardEOBDAndOBD,
+		OBDStandardEOBDOBDAndOBDII,
+		OBDStandardJOBDAndEOBD,
+		OBDStandardJOBDEOBDAndOBDII,
+		OBDStandardHDEOBDI,
+		OBDStandardHDEOBDIN,
+		OBDStandardHDEOBDII,
+		OBDStandardHDEOBDIIN,
+		OBDStandardHDEOBDIV:
+		return true
+	}
+
+	return false
+}
+
 // OBDStandards represents a command that checks the OBD standards this vehicle
-// conforms to as a single decimal value:
-//
-// - 1       OBD-II as defined by the CARB
-// - 2       OBD as defined by the EPA
-// - 3       OBD and OBD-II
-// - 4       OBD-I
-// - 5       Not OBD compliant
-// - 6       EOBD (Europe)
-// - 7       EOBD and OBD-II
-// - 8       EOBD and OBD
-// - 9       EOBD, OBD and OBD II
-// - 10      JOBD (Japan)
-// - 11      JOBD and OBD II
-// - 12      JOBD and EOBD
-// - 13      JOBD, EOBD, and OBD II
-// - 14      Reserved
-// - 15      Reserved
-// - 16      Reserved
-// - 17      Engine Manufacturer Diagnostics (EMD)
-// - 18      Engine Manufacturer Diagnostics Enhanced (EMD+)
-// - 19      Heavy Duty On-Board Diagnostics (Child/Partial) (HD OBD-C)
-// - 20      Heavy Duty On-Board Diagnostics (HD OBD)
-// - 21      World Wide Harmonized OBD (WWH OBD)
-// - 22      Reserved
-// - 23      Heavy Duty Euro OBD Stage I without NOx control (HD EOBD-I)
-// - 24      Heavy Duty Euro OBD Stage I with NOx control (HD EOBD-I N)
-// - 25      Heavy Duty Euro OBD Stage II without NOx control (HD EOBD-II)
-// - 26      Heavy Duty Euro OBD Stage II with NOx control (HD EOBD-II N)
-// - 27      Reserved
-// - 28      Brazil OBD Phase 1 (OBDBr-1)
-// - 29      Brazil OBD Phase 2 (OBDBr-2)
-// - 30      Korean OBD (KOBD)
-// - 31      India OBD I (IOBD I)
-// - 32      India OBD II (IOBD II)
-// - 33      Heavy Duty Euro OBD Stage VI (HD EOBD-IV)
-// - 34-250  Reserved
-// - 251-255 Not available for assignment (SAE J1939 special meaning)
+// conforms to as a single decimal value, see OBDStandard for the meaning of
+// the values and Standard for the value as an OBDStandard.
 type OBDStandards struct {
 	baseCommand
 	UIntCommand
@@ -916,6 +1129,12 @@ func (cmd *OBDStandards) SetValue(result *Result) error {
 	return nil
 }
 
+// Standard retrieves the value as an OBDStandard, such as
+// OBDStandardEOBDAndOBDII.
+func (cmd *OBDStandards) Standard() OBDStandard {
+	return OBDStandard(cmd.Value)
+}
+
 // RuntimeSinceStart represents a command that checks the run time since engine
 // start.
 //
@@ -961,6 +1180,12 @@ func (cmd *ResultLessCommand) ValueAsLit() string {
 	return ""
 }
 
+// value retrieves the value of the command, which is always nil since the
+// command has no result.
+func (cmd *ResultLessCommand) value() interface{} {
+	return nil
+}
+
 // NewClearTroubleCodes creates a new ClearTroubleCodes with the right parameters..
 func NewClearTroubleCodes() *ClearTroubleCodes {
 	return &ClearTroubleCodes{
@@ -997,6 +1222,334 @@ func GetSensorCommands() []OBDCommand {
 	return sensorCommands
 }
 
+// commandRegistry holds constructors of all the defined commands that read
+// values from the car, keyed by the key of the command.
+var commandRegistry = map[string]func() OBDCommand{
+	"monitor_status":                         func() OBDCommand { return NewMonitorStatus() },
+	"engine_load":                            func() OBDCommand { return NewEngineLoad() },
+	"fuel":                                   func() OBDCommand { return NewFuel() },
+	"dist_since_dtc_clean":                   func() OBDCommand { return NewDistSinceDTCClear() },
+	"odometer":                               func() OBDCommand { return NewOdometer() },
+	"transmission_actual_gear":               func() OBDCommand { return NewTransmissionActualGear() },
+	"coolant_temperature":                    func() OBDCommand { return NewCoolantTemperature() },
+	"short_term_fuel_trim_bank1":             func() OBDCommand { return NewShortFuelTrim1() },
+	"long_term_fuel_trim_bank1":              func() OBDCommand { return NewLongFuelTrim1() },
+	"short_term_fuel_trim_bank2":             func() OBDCommand { return NewShortFuelTrim2() },
+	"long_term_fuel_trim_bank2":              func() OBDCommand { return NewLongFuelTrim2() },
+	"fuel_pressure":                          func() OBDCommand { return NewFuelPressure() },
+	"intake_manifold_pressure":               func() OBDCommand { return NewIntakeManifoldPressure() },
+	"engine_rpm":                             func() OBDCommand { return NewEngineRPM() },
+	"vehicle_speed":                          func() OBDCommand { return NewVehicleSpeed() },
+	"timing_advance":                         func() OBDCommand { return NewTimingAdvance() },
+	"intake_air_temperature":                 func() OBDCommand { return NewIntakeAirTemperature() },
+	"maf_air_flow_rate":                      func() OBDCommand { return NewMafAirFlowRate() },
+	"throttle_position":                      func() OBDCommand { return NewThrottlePosition() },
+	"obd_standards":                          func() OBDCommand { return NewOBDStandards() },
+	"runtime_since_engine_start":             func() OBDCommand { return NewRuntimeSinceStart() },
+	"control_module_voltage":                 func() OBDCommand { return NewControlModuleVoltage() },
+	"ambient_temperature":                    func() OBDCommand { return NewAmbientTemperature() },
+	"engine_oil_temperature":                 func() OBDCommand { return NewEngineOilTemperature() },
+	"absolute_barometric_pressure":           func() OBDCommand { return NewAbsoluteBarometricPressure() },
+	"commanded_secondary_air_status":         func() OBDCommand { return NewCommandedSecondaryAirStatus() },
+	"o2_sensors_present":                     func() OBDCommand { return NewO2SensorsPresent() },
+	"o2_sensors_present_4_banks":             func() OBDCommand { return NewO2SensorsPresent4Banks() },
+	"o2_sensor_voltage_bank1_sensor1":        func() OBDCommand { return NewO2SensorVoltage(1, 1) },
+	"o2_sensor_voltage_bank1_sensor2":        func() OBDCommand { return NewO2SensorVoltage(1, 2) },
+	"o2_sensor_voltage_bank1_sensor3":        func() OBDCommand { return NewO2SensorVoltage(1, 3) },
+	"o2_sensor_voltage_bank1_sensor4":        func() OBDCommand { return NewO2SensorVoltage(1, 4) },
+	"o2_sensor_voltage_bank2_sensor1":        func() OBDCommand { return NewO2SensorVoltage(2, 1) },
+	"o2_sensor_voltage_bank2_sensor2":        func() OBDCommand { return NewO2SensorVoltage(2, 2) },
+	"o2_sensor_voltage_bank2_sensor3":        func() OBDCommand { return NewO2SensorVoltage(2, 3) },
+	"o2_sensor_voltage_bank2_sensor4":        func() OBDCommand { return NewO2SensorVoltage(2, 4) },
+	"dist_with_mil_on":                       func() OBDCommand { return NewDistWithMILOn() },
+	"fuel_rail_pressure":                     func() OBDCommand { return NewFuelRailPressure() },
+	"fuel_rail_gauge_pressure":               func() OBDCommand { return NewFuelRailGaugePressure() },
+	"o2_sensor_lambda_voltage_bank1_sensor1": func() OBDCommand { return NewO2SensorLambdaVoltage(1, 1) },
+	"o2_sensor_lambda_voltage_bank1_sensor2": func() OBDCommand { return NewO2SensorLambdaVoltage(1, 2) },
+	"o2_sensor_lambda_voltage_bank1_sensor3": func() OBDCommand { return NewO2SensorLambdaVoltage(1, 3) },
+	"o2_sensor_lambda_voltage_bank1_sensor4": func() OBDCommand { return NewO2SensorLambdaVoltage(1, 4) },
+	"o2_sensor_lambda_voltage_bank2_sensor1": func() OBDCommand { return NewO2SensorLambdaVoltage(2, 1) },
+	"o2_sensor_lambda_voltage_bank2_sensor2": func() OBDCommand { return NewO2SensorLambdaVoltage(2, 2) },
+	"o2_sensor_lambda_voltage_bank2_sensor3": func() OBDCommand { return NewO2SensorLambdaVoltage(2, 3) },
+	"o2_sensor_lambda_voltage_bank2_sensor4": func() OBDCommand { return NewO2SensorLambdaVoltage(2, 4) },
+	"o2_sensor_lambda_current_bank1_sensor1": func() OBDCommand { return NewO2SensorLambdaCurrent(1, 1) },
+	"o2_sensor_lambda_current_bank1_sensor2": func() OBDCommand { return NewO2SensorLambdaCurrent(1, 2) },
+	"o2_sensor_lambda_current_bank1_sensor3": func() OBDCommand { return NewO2SensorLambdaCurrent(1, 3) },
+	"o2_sensor_lambda_current_bank1_sensor4": func() OBDCommand { return NewO2SensorLambdaCurrent(1, 4) },
+	"o2_sensor_lambda_current_bank2_sensor1": func() OBDCommand { return NewO2SensorLambdaCurrent(2, 1) },
+	"o2_sensor_lambda_current_bank2_sensor2": func() OBDCommand { return NewO2SensorLambdaCurrent(2, 2) },
+	"o2_sensor_lambda_current_bank2_sensor3": func() OBDCommand { return NewO2SensorLambdaCurrent(2, 3) },
+	"o2_sensor_lambda_current_bank2_sensor4": func() OBDCommand { return NewO2SensorLambdaCurrent(2, 4) },
+	"commanded_egr":                          func() OBDCommand { return NewCommandedEGR() },
+	"egr_error":                              func() OBDCommand { return NewEGRError() },
+	"commanded_evaporative_purge":            func() OBDCommand { return NewCommandedEvaporativePurge() },
+	"warm_ups_since_dtc_clear":               func() OBDCommand { return NewWarmUpsSinceDTCClear() },
+	"evap_vapor_pressure":                    func() OBDCommand { return NewEvapVaporPressure() },
+	"absolute_evap_vapor_pressure":           func() OBDCommand { return NewAbsoluteEvapVaporPressure() },
+	"evap_vapor_pressure_wide":               func() OBDCommand { return NewEvapVaporPressureWide() },
+	"monitor_status_this_drive_cycle":        func() OBDCommand { return NewMonitorStatusThisDriveCycle() },
+	"absolute_load":                          func() OBDCommand { return NewAbsoluteLoad() },
+	"commanded_equivalence_ratio":            func() OBDCommand { return NewCommandedEquivalenceRatio() },
+	"relative_throttle_position":             func() OBDCommand { return NewRelativeThrottlePosition() },
+	"absolute_throttle_position_b":           func() OBDCommand { return NewAbsoluteThrottlePositionB() },
+	"absolute_throttle_position_c":           func() OBDCommand { return NewAbsoluteThrottlePositionC() },
+	"accelerator_pedal_position_d":           func() OBDCommand { return NewAcceleratorPedalPositionD() },
+	"accelerator_pedal_position_e":           func() OBDCommand { return NewAcceleratorPedalPositionE() },
+	"accelerator_pedal_position_f":           func() OBDCommand { return NewAcceleratorPedalPositionF() },
+	"commanded_throttle_actuator":            func() OBDCommand { return NewCommandedThrottleActuator() },
+	"time_with_mil_on":                       func() OBDCommand { return NewTimeWithMILOn() },
+	"time_since_dtc_clear":                   func() OBDCommand { return NewTimeSinceDTCClear() },
+	"maximum_values":                         func() OBDCommand { return NewMaximumValues() },
+	"maximum_maf_air_flow_rate":              func() OBDCommand { return NewMaximumMafAirFlowRate() },
+	"fuel_type":                              func() OBDCommand { return NewFuelType() },
+	"ethanol_fuel":                           func() OBDCommand { return NewEthanolFuel() },
+	"relative_accelerator_pedal_position":    func() OBDCommand { return NewRelativeAcceleratorPedalPosition() },
+	"hybrid_battery_remaining_life":          func() OBDCommand { return NewHybridBatteryRemainingLife() },
+	"fuel_injection_timing":                  func() OBDCommand { return NewFuelInjectionTiming() },
+	"engine_fuel_rate":                       func() OBDCommand { return NewEngineFuelRate() },
+	"driver_demand_torque":                   func() OBDCommand { return NewDriverDemandTorque() },
+	"actual_engine_torque":                   func() OBDCommand { return NewActualEngineTorque() },
+	"engine_reference_torque":                func() OBDCommand { return NewEngineReferenceTorque() },
+	"engine_percent_torque_data":             func() OBDCommand { return NewEnginePercentTorqueData() },
+	"auxiliary_input_output":                 func() OBDCommand { return NewAuxiliaryInputOutput() },
+	"maf_air_flow_rate_sensors":              func() OBDCommand { return NewMafAirFlowRateSensors() },
+	"coolant_temperature_sensors":            func() OBDCommand { return NewCoolantTemperatureSensors() },
+	"intake_air_temperature_sensors":         func() OBDCommand { return NewIntakeAirTemperatureSensors() },
+	"fuel_pressure_control":                  func() OBDCommand { return NewFuelPressureControl() },
+	"boost_pressure_control":                 func() OBDCommand { return NewBoostPressureControl() },
+	"variable_geometry_turbo_control":        func() OBDCommand { return NewVariableGeometryTurboControl() },
+	"wastegate_control":                      func() OBDCommand { return NewWastegateControl() },
+	"exhaust_pressure":                       func() OBDCommand { return NewExhaustPressure() },
+	"turbocharger_rpm":                       func() OBDCommand { return NewTurbochargerRPM() },
+	"turbocharger_a_temperature":             func() OBDCommand { return NewTurbochargerATemperature() },
+	"turbocharger_b_temperature":             func() OBDCommand { return NewTurbochargerBTemperature() },
+	"charge_air_cooler_temperature":          func() OBDCommand { return NewChargeAirCoolerTemperature() },
+	"exhaust_gas_temperature_bank1":          func() OBDCommand { return NewExhaustGasTemperatureBank1() },
+	"exhaust_gas_temperature_bank2":          func() OBDCommand { return NewExhaustGasTemperatureBank2() },
+	"particulate_filter_pressure_bank1":      func() OBDCommand { return NewParticulateFilterPressureBank1() },
+	"particulate_filter_pressure_bank2":      func() OBDCommand { return NewParticulateFilterPressureBank2() },
+	"particulate_filter_temperature":         func() OBDCommand { return NewParticulateFilterTemperature() },
+	"nox_sensor":                             func() OBDCommand { return NewNOxSensor() },
+	"particulate_matter_sensor":              func() OBDCommand { return NewParticulateMatterSensor() },
+	"nox_reagent_system":                     func() OBDCommand { return NewNOxReagentSystem() },
+	"scr_inducement_system":                  func() OBDCommand { return NewSCRInducementSystem() },
+	"diesel_exhaust_fluid_sensor":            func() OBDCommand { return NewDieselExhaustFluidSensor() },
+	"engine_run_time":                        func() OBDCommand { return NewEngineRunTime() },
+	"engine_run_time_aecd_1_5":               func() OBDCommand { return NewEngineRunTimeAECD1To5() },
+	"engine_run_time_aecd_6_10":              func() OBDCommand { return NewEngineRunTimeAECD6To10() },
+	"engine_friction_torque":                 func() OBDCommand { return NewEngineFrictionTorque() },
+	"cylinder_fuel_rate":                     func() OBDCommand { return NewCylinderFuelRate() },
+	"hybrid_system_data":                     func() OBDCommand { return NewHybridSystemData() },
+	"engine_fuel_mass_rate":                  func() OBDCommand { return NewEngineFuelMassRate() },
+	"exhaust_flow_rate":                      func() OBDCommand { return NewExhaustFlowRate() },
+}
+
+// NewCommandByKey creates a new command from the key of the command, such as
+// "engine_rpm". The second return value is false if there's no command with
+// the given key.
+func NewCommandByKey(key string) (OBDCommand, bool) {
+	constructor, ok := commandRegistry[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	return constructor(), true
+}
+
+// GetCommandKeys returns the keys of all the defined commands that read
+// values from the car, sorted alphabetically.
+func GetCommandKeys() []string {
+	keys := make([]string, 0, len(commandRegistry))
+
+	for key := range commandRegistry {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// commandUnits maps the keys of the defined commands to the unit of their
+// value. Commands with values without a unit are left out.
+var commandUnits = map[string]string{
+	"engine_load":                         "ratio",
+	"fuel":                                "ratio",
+	"dist_since_dtc_clean":                "km",
+	"odometer":                            "km",
+	"transmission_actual_gear":            "ratio",
+	"coolant_temperature":                 "°C",
+	"short_term_fuel_trim_bank1":          "%",
+	"long_term_fuel_trim_bank1":           "%",
+	"short_term_fuel_trim_bank2":          "%",
+	"long_term_fuel_trim_bank2":           "%",
+	"fuel_pressure":                       "kPa",
+	"intake_manifold_pressure":            "kPa",
+	"engine_rpm":                          "rpm",
+	"vehicle_speed":                       "km/h",
+	"timing_advance":                      "°",
+	"intake_air_temperature":              "°C",
+	"maf_air_flow_rate":                   "g/s",
+	"throttle_position":                   "ratio",
+	"runtime_since_engine_start":          "s",
+	"control_module_voltage":              "V",
+	"ambient_temperature":                 "°C",
+	"engine_oil_temperature":              "°C",
+	"absolute_barometric_pressure":        "kPa",
+	"dist_with_mil_on":                    "km",
+	"fuel_rail_pressure":                  "kPa",
+	"fuel_rail_gauge_pressure":            "kPa",
+	"commanded_egr":                       "ratio",
+	"egr_error":                           "%",
+	"commanded_evaporative_purge":         "ratio",
+	"evap_vapor_pressure":                 "Pa",
+	"absolute_evap_vapor_pressure":        "kPa",
+	"evap_vapor_pressure_wide":            "Pa",
+	"absolute_load":                       "ratio",
+	"commanded_equivalence_ratio":         "ratio",
+	"relative_throttle_position":          "ratio",
+	"absolute_throttle_position_b":        "ratio",
+	"absolute_throttle_position_c":        "ratio",
+	"accelerator_pedal_position_d":        "ratio",
+	"accelerator_pedal_position_e":        "ratio",
+	"accelerator_pedal_position_f":        "ratio",
+	"commanded_throttle_actuator":         "ratio",
+	"time_with_mil_on":                    "min",
+	"time_since_dtc_clear":                "min",
+	"maximum_maf_air_flow_rate":           "g/s",
+	"ethanol_fuel":                        "ratio",
+	"relative_accelerator_pedal_position": "ratio",
+	"hybrid_battery_remaining_life":       "ratio",
+	"fuel_injection_timing":               "°",
+	"engine_fuel_rate":                    "L/h",
+	"driver_demand_torque":                "%",
+	"actual_engine_torque":                "%",
+	"engine_reference_torque":             "Nm",
+	"engine_friction_torque":              "%",
+	"cylinder_fuel_rate":                  "mg/stroke",
+	"exhaust_flow_rate":                   "kg/h",
+}
+
+// ValueRange represents the minimum and maximum value of a command.
+type ValueRange struct {
+	Min float64
+	Max float64
+}
+
+// Contains checks if the given value is within the range.
+func (vr ValueRange) Contains(val float64) bool {
+	return vr.Min <= val && val <= vr.Max
+}
+
+// commandRanges maps the keys of the defined commands to the range of their
+// value, as documented on each command.
+var commandRanges = map[string]ValueRange{
+	"engine_load":                         {0, 1},
+	"fuel":                                {0, 1},
+	"dist_since_dtc_clean":                {0, 65535},
+	"odometer":                            {0, 429496729.5},
+	"transmission_actual_gear":            {0, 65.535},
+	"coolant_temperature":                 {-40, 215},
+	"short_term_fuel_trim_bank1":          {-100, 99.21875},
+	"long_term_fuel_trim_bank1":           {-100, 99.21875},
+	"short_term_fuel_trim_bank2":          {-100, 99.21875},
+	"long_term_fuel_trim_bank2":           {-100, 99.21875},
+	"fuel_pressure":                       {0, 765},
+	"intake_manifold_pressure":            {0, 255},
+	"engine_rpm":                          {0, 16383.75},
+	"vehicle_speed":                       {0, 255},
+	"timing_advance":                      {-64, 63.5},
+	"intake_air_temperature":              {-40, 215},
+	"maf_air_flow_rate":                   {0, 655.35},
+	"throttle_position":                   {0, 1},
+	"runtime_since_engine_start":          {0, 65535},
+	"control_module_voltage":              {0, 65.535},
+	"ambient_temperature":                 {-40, 215},
+	"engine_oil_temperature":              {-40, 215},
+	"absolute_barometric_pressure":        {0, 255},
+	"dist_with_mil_on":                    {0, 65535},
+	"fuel_rail_pressure":                  {0, 5177.265},
+	"fuel_rail_gauge_pressure":            {0, 655350},
+	"commanded_egr":                       {0, 1},
+	"egr_error":                           {-100, 99.21875},
+	"commanded_evaporative_purge":         {0, 1},
+	"warm_ups_since_dtc_clear":            {0, 255},
+	"evap_vapor_pressure":                 {-8192, 8191.75},
+	"absolute_evap_vapor_pressure":        {0, 327.675},
+	"evap_vapor_pressure_wide":            {-32768, 32767},
+	"absolute_load":                       {0, 257},
+	"commanded_equivalence_ratio":         {0, 2},
+	"relative_throttle_position":          {0, 1},
+	"absolute_throttle_position_b":        {0, 1},
+	"absolute_throttle_position_c":        {0, 1},
+	"accelerator_pedal_position_d":        {0, 1},
+	"accelerator_pedal_position_e":        {0, 1},
+	"accelerator_pedal_position_f":        {0, 1},
+	"commanded_throttle_actuator":         {0, 1},
+	"time_with_mil_on":                    {0, 65535},
+	"time_since_dtc_clear":                {0, 65535},
+	"maximum_maf_air_flow_rate":           {0, 2550},
+	"ethanol_fuel":                        {0, 1},
+	"relative_accelerator_pedal_position": {0, 1},
+	"hybrid_battery_remaining_life":       {0, 1},
+	"fuel_injection_timing":               {-210, 301.9921875},
+	"engine_fuel_rate":                    {0, 3276.75},
+	"driver_demand_torque":                {-125, 130},
+	"actual_engine_torque":                {-125, 130},
+	"engine_reference_torque":             {0, 65535},
+	"engine_friction_torque":              {-125, 130},
+	"cylinder_fuel_rate":                  {0, 2047.97},
+	"exhaust_flow_rate":                   {0, 13107},
+}
+
+// plausibleRanges maps the keys of the commands whose payload can encode
+// values a car does not report, such as an engine speed of 16383.75 rpm, to
+// the range of the values a car can report. They are only checked when
+// enabled with Device.SetPlausibilityCheck.
+var plausibleRanges = map[string]ValueRange{
+	"odometer":                     {0, 2000000},
+	"transmission_actual_gear":     {0, 20},
+	"coolant_temperature":          {-40, 150},
+	"engine_rpm":                   {0, 10000},
+	"intake_air_temperature":       {-40, 150},
+	"maf_air_flow_rate":            {0, 600},
+	"control_module_voltage":       {0, 36},
+	"ambient_temperature":          {-40, 80},
+	"engine_oil_temperature":       {-40, 180},
+	"absolute_barometric_pressure": {0, 115},
+	"fuel_rail_gauge_pressure":     {0, 300000},
+	"absolute_load":                {0, 10},
+	"engine_fuel_rate":             {0, 500},
+	"engine_reference_torque":      {0, 10000},
+	"cylinder_fuel_rate":           {0, 500},
+	"exhaust_flow_rate":            {0, 5000},
+}
+
+// GetCommandRange returns the range of the value of the given command, the
+// second return value is false if the range of the command is unknown.
+func GetCommandRange(cmd OBDCommand) (ValueRange, bool) {
+	vr, ok := commandRanges[cmd.Key()]
+
+	return vr, ok
+}
+
+// GetPlausibleRange returns the range of the values a car can report for the
+// given command, which is narrower than the range of GetCommandRange for
+// commands such as engine_rpm. The second return value is false if the
+// command has no narrower range.
+func GetPlausibleRange(cmd OBDCommand) (ValueRange, bool) {
+	vr, ok := plausibleRanges[cmd.Key()]
+
+	return vr, ok
+}
+
+// GetCommandUnit returns the unit of the value of the given command, or an
+// empty string if the value has no unit or the command is unknown.
+func GetCommandUnit(cmd OBDCommand) string {
+	return commandUnits[cmd.Key()]
+}
+
 // Control module voltage
 type ControlModuleVoltage struct {
 	baseCommand
@@ -1019,7 +1572,7 @@ func (cmd *ControlModuleVoltage) SetValue(result *Result) error {
 		return err
 	}
 
-	cmd.Value = float32(payload) / 1000
+	cmd.SetFloat64(float64(payload) / 1000)
 
 	return nil
 }
@@ -1115,3 +1668,3706 @@ func (cmd *AbsoluteBarometricPressure) SetValue(result *Result) error {
 
 	return nil
 }
+
+// SecondaryAirStatus represents the state of the secondary air system, see
+// CommandedSecondaryAirStatus.
+type SecondaryAirStatus byte
+
+const (
+	// SecondaryAirUpstream means the air is led upstream of the catalytic
+	// converter.
+	SecondaryAirUpstream SecondaryAirStatus = 0x01
+	// SecondaryAirDownstream means the air is led downstream of the
+	// catalytic converter.
+	SecondaryAirDownstream SecondaryAirStatus = 0x02
+	// SecondaryAirOff means the air is led to the outside atmosphere or the
+	// system is off.
+	SecondaryAirOff SecondaryAirStatus = 0x04
+	// SecondaryAirDiagnostics means the pump is commanded on for
+	// diagnostics.
+	SecondaryAirDiagnostics SecondaryAirStatus = 0x08
+)
+
+// String returns the literal representation of the status.
+func (status SecondaryAirStatus) String() string {
+	switch status {
+	case SecondaryAirUpstream:
+		return "upstream"
+	case SecondaryAirDownstream:
+		return "downstream"
+	case SecondaryAirOff:
+		return "off"
+	case SecondaryAirDiagnostics:
+		return "diagnostics"
+	}
+
+	return fmt.Sprintf("unknown (0x%02X)", byte(status))
+}
+
+// CommandedSecondaryAirStatus represents a command that checks the state of
+// the secondary air system, used when diagnosing cold start emission faults.
+type CommandedSecondaryAirStatus struct {
+	baseCommand
+	Value SecondaryAirStatus
+}
+
+// NewCommandedSecondaryAirStatus creates a new CommandedSecondaryAirStatus
+// with the right parameters.
+func NewCommandedSecondaryAirStatus() *CommandedSecondaryAirStatus {
+	return &CommandedSecondaryAirStatus{
+		baseCommand{SERVICE_01_ID, 0x12, 1, "commanded_secondary_air_status"},
+		0,
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *CommandedSecondaryAirStatus) ValueAsLit() string {
+	return cmd.Value.String()
+}
+
+// value retrieves the value as a string, used when exporting readings.
+func (cmd *CommandedSecondaryAirStatus) value() interface{} {
+	return cmd.Value.String()
+}
+
+// SetValue processes the byte array value into the right status.
+func (cmd *CommandedSecondaryAirStatus) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = SecondaryAirStatus(payload)
+
+	return nil
+}
+
+// o2SensorsPresent is an abstract type for the commands checking which
+// oxygen sensors are present, where each bit of the payload represents one
+// sensor. The bits are grouped by bank starting with the least significant
+// bit, with the given amount of sensors per bank.
+type o2SensorsPresent struct {
+	baseCommand
+	sensorsPerBank int
+	Sensors        map[int][]int
+}
+
+// SetValue processes the byte array value into the sensors present per bank.
+func (cmd *o2SensorsPresent) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Sensors = map[int][]int{}
+
+	for bit := 0; bit < 8; bit++ {
+		if payload&(1<<bit) == 0 {
+			continue
+		}
+
+		bank := bit/cmd.sensorsPerBank + 1
+		sensor := bit%cmd.sensorsPerBank + 1
+
+		cmd.Sensors[bank] = append(cmd.Sensors[bank], sensor)
+	}
+
+	return nil
+}
+
+// IsPresent checks if the given sensor of the given bank is present, both
+// numbered from 1.
+func (cmd *o2SensorsPresent) IsPresent(bank int, sensor int) bool {
+	for _, present := range cmd.Sensors[bank] {
+		if present == sensor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValueAsLit retrieves the value as a literal representation, which is a
+// JSON object with the banks as keys and the sensors present as values.
+func (cmd *o2SensorsPresent) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the sensors present per bank, used when exporting
+// readings.
+func (cmd *o2SensorsPresent) value() interface{} {
+	if cmd.Sensors == nil {
+		return map[int][]int{}
+	}
+
+	return cmd.Sensors
+}
+
+// O2SensorsPresent represents a command that checks which oxygen sensors are
+// present, for cars with 2 banks of up to 4 sensors each.
+//
+// Sensors maps each bank to the sensors present in it, such as bank 1 having
+// sensor 1 and 2 present.
+type O2SensorsPresent struct {
+	o2SensorsPresent
+}
+
+// NewO2SensorsPresent creates a new O2SensorsPresent with the right
+// parameters.
+func NewO2SensorsPresent() *O2SensorsPresent {
+	return &O2SensorsPresent{
+		o2SensorsPresent{
+			baseCommand{SERVICE_01_ID, 0x13, 1, "o2_sensors_present"},
+			4,
+			nil,
+		},
+	}
+}
+
+// O2SensorsPresent4Banks represents a command that checks which oxygen
+// sensors are present, for cars with 4 banks of up to 2 sensors each.
+//
+// Sensors maps each bank to the sensors present in it, such as bank 3 having
+// sensor 1 present.
+type O2SensorsPresent4Banks struct {
+	o2SensorsPresent
+}
+
+// NewO2SensorsPresent4Banks creates a new O2SensorsPresent4Banks with the
+// right parameters.
+func NewO2SensorsPresent4Banks() *O2SensorsPresent4Banks {
+	return &O2SensorsPresent4Banks{
+		o2SensorsPresent{
+			baseCommand{SERVICE_01_ID, 0x1D, 1, "o2_sensors_present_4_banks"},
+			2,
+			nil,
+		},
+	}
+}
+
+// clampO2Sensor clamps the given bank to 1 to 2 and the given sensor to 1 to
+// 4, the oxygen sensors numbered as for O2SensorsPresent.
+func clampO2Sensor(bank int, sensor int) (int, int) {
+	if bank < 1 {
+		bank = 1
+	} else if bank > 2 {
+		bank = 2
+	}
+
+	if sensor < 1 {
+		sensor = 1
+	} else if sensor > 4 {
+		sensor = 4
+	}
+
+	return bank, sensor
+}
+
+// o2SensorParameterID returns the PID of the given oxygen sensor, for the
+// family of commands starting with the given PID. The bank and sensor are
+// expected to be clamped with clampO2Sensor.
+func o2SensorParameterID(first OBDParameterID, bank int, sensor int) OBDParameterID {
+	return first + OBDParameterID((bank-1)*4+sensor-1)
+}
+
+// O2SensorVoltage represents a command that checks the voltage of a
+// narrowband oxygen sensor and the short term fuel trim associated with it.
+//
+// The sensors are numbered as for O2SensorsPresent, with 2 banks of 4
+// sensors. For cars with 4 banks of 2 sensors (see O2SensorsPresent4Banks),
+// bank 1 sensor 3 is bank 2 sensor 1, bank 2 sensor 1 is bank 3 sensor 1 and
+// so on.
+//
+// UsedForTrim is false when the sensor is not used in the fuel trim
+// calculation, then ShortTermFuelTrim is 0.
+//
+// Voltage Min: 0
+// Voltage Max: 1.275
+// ShortTermFuelTrim Min: -100 (too rich)
+// ShortTermFuelTrim Max: 99.2 (too lean)
+type O2SensorVoltage struct {
+	baseCommand
+	Bank              int
+	Sensor            int
+	Voltage           float64
+	ShortTermFuelTrim float64
+	UsedForTrim       bool
+}
+
+// NewO2SensorVoltage creates a new O2SensorVoltage for the given sensor of
+// the given bank (PIDs 14 to 1B). The bank is clamped to 1 to 2 and the
+// sensor to 1 to 4.
+func NewO2SensorVoltage(bank int, sensor int) *O2SensorVoltage {
+	bank, sensor = clampO2Sensor(bank, sensor)
+
+	return &O2SensorVoltage{
+		baseCommand{
+			SERVICE_01_ID,
+			o2SensorParameterID(0x14, bank, sensor),
+			2,
+			fmt.Sprintf("o2_sensor_voltage_bank%d_sensor%d", bank, sensor),
+		},
+		bank,
+		sensor,
+		0,
+		0,
+		false,
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *O2SensorVoltage) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *O2SensorVoltage) value() interface{} {
+	return struct {
+		Voltage           float64 `json:"voltage"`
+		ShortTermFuelTrim float64 `json:"short_term_fuel_trim"`
+		UsedForTrim       bool    `json:"used_for_trim"`
+	}{
+		cmd.Voltage,
+		cmd.ShortTermFuelTrim,
+		cmd.UsedForTrim,
+	}
+}
+
+// floatFields returns the voltage, and the fuel trim when the sensor is used
+// for the fuel trim, see Smoother.
+func (cmd *O2SensorVoltage) floatFields() map[string]*float64 {
+	fields := map[string]*float64{"voltage": &cmd.Voltage}
+
+	if cmd.UsedForTrim {
+		fields["short_term_fuel_trim"] = &cmd.ShortTermFuelTrim
+	}
+
+	return fields
+}
+
+// SetValue processes the byte array value into the voltage and fuel trim.
+func (cmd *O2SensorVoltage) SetValue(result *Result) error {
+	expAmount := 2
+	payload := result.value[2:]
+	amount := len(payload)
+
+	if amount != expAmount {
+		return fmt.Errorf(
+			"Expected %d bytes of payload, got %d", expAmount, amount,
+		)
+	}
+
+	cmd.Voltage = float64(payload[0]) / 200
+	cmd.UsedForTrim = payload[1] != 0xFF
+	cmd.ShortTermFuelTrim = 0
+
+	if cmd.UsedForTrim {
+		cmd.ShortTermFuelTrim = float64(payload[1])/1.28 - 100
+	}
+
+	return nil
+}
+
+// DistWithMILOn represents a command that checks the distance traveled in
+// kilometers with the malfunction indicator lamp (check engine light) on.
+//
+// Min: 0
+// Max: 65535
+type DistWithMILOn struct {
+	baseCommand
+	UIntCommand
+}
+
+// NewDistWithMILOn creates a new DistWithMILOn with the right parameters.
+func NewDistWithMILOn() *DistWithMILOn {
+	return &DistWithMILOn{
+		baseCommand{SERVICE_01_ID, 0x21, 2, "dist_with_mil_on"},
+		UIntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right uint value.
+func (cmd *DistWithMILOn) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = uint32(payload)
+
+	return nil
+}
+
+// FuelRailPressure represents a command that checks the fuel rail pressure
+// in kPa, relative to the manifold vacuum.
+//
+// Min: 0
+// Max: 5177.265
+type FuelRailPressure struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewFuelRailPressure creates a new FuelRailPressure with the right
+// parameters.
+func NewFuelRailPressure() *FuelRailPressure {
+	return &FuelRailPressure{
+		baseCommand{SERVICE_01_ID, 0x22, 2, "fuel_rail_pressure"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *FuelRailPressure) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) * 0.079)
+
+	return nil
+}
+
+// FuelRailGaugePressure represents a command that checks the fuel rail gauge
+// pressure in kPa, as reported by diesel and gasoline direct injection
+// engines.
+//
+// Min: 0
+// Max: 655350
+type FuelRailGaugePressure struct {
+	baseCommand
+	UIntCommand
+}
+
+// NewFuelRailGaugePressure creates a new FuelRailGaugePressure with the right
+// parameters.
+func NewFuelRailGaugePressure() *FuelRailGaugePressure {
+	return &FuelRailGaugePressure{
+		baseCommand{SERVICE_01_ID, 0x23, 2, "fuel_rail_gauge_pressure"},
+		UIntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right uint value.
+func (cmd *FuelRailGaugePressure) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = uint32(payload) * 10
+
+	return nil
+}
+
+// widebandO2Payload splits the payload of the wideband oxygen sensor
+// commands into the equivalence ratio (lambda) and the raw value of the
+// second measurement, which is the voltage or the current of the sensor.
+func widebandO2Payload(result *Result) (float64, uint16, error) {
+	payload, err := result.PayloadAsUInt32()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lambda := float64(payload>>16) * 2 / 65536
+
+	return lambda, uint16(payload & 0xFFFF), nil
+}
+
+// O2SensorLambdaVoltage represents a command that checks the equivalence
+// ratio (lambda) and the voltage of a wideband oxygen sensor. The air-fuel
+// ratio is the lambda multiplied with the stoichiometric air-fuel ratio of
+// the fuel (see FuelProperties).
+//
+// The sensors are numbered as for O2SensorVoltage.
+//
+// Lambda Min: 0
+// Lambda Max: 2
+// Voltage Min: 0
+// Voltage Max: 8
+type O2SensorLambdaVoltage struct {
+	baseCommand
+	Bank    int
+	Sensor  int
+	Lambda  float64
+	Voltage float64
+}
+
+// NewO2SensorLambdaVoltage creates a new O2SensorLambdaVoltage for the given
+// sensor of the given bank (PIDs 24 to 2B). The bank is clamped to 1 to 2
+// and the sensor to 1 to 4.
+func NewO2SensorLambdaVoltage(bank int, sensor int) *O2SensorLambdaVoltage {
+	bank, sensor = clampO2Sensor(bank, sensor)
+
+	return &O2SensorLambdaVoltage{
+		baseCommand{
+			SERVICE_01_ID,
+			o2SensorParameterID(0x24, bank, sensor),
+			4,
+			fmt.Sprintf("o2_sensor_lambda_voltage_bank%d_sensor%d", bank, sensor),
+		},
+		bank,
+		sensor,
+		0,
+		0,
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *O2SensorLambdaVoltage) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *O2SensorLambdaVoltage) value() interface{} {
+	return struct {
+		Lambda  float64 `json:"lambda"`
+		Voltage float64 `json:"voltage"`
+	}{
+		cmd.Lambda,
+		cmd.Voltage,
+	}
+}
+
+// floatFields returns the lambda and the voltage, see Smoother.
+func (cmd *O2SensorLambdaVoltage) floatFields() map[string]*float64 {
+	return map[string]*float64{"lambda": &cmd.Lambda, "voltage": &cmd.Voltage}
+}
+
+// SetValue processes the byte array value into the lambda and voltage.
+func (cmd *O2SensorLambdaVoltage) SetValue(result *Result) error {
+	lambda, voltage, err := widebandO2Payload(result)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Lambda = lambda
+	cmd.Voltage = float64(voltage) * 8 / 65536
+
+	return nil
+}
+
+// O2SensorLambdaCurrent represents a command that checks the equivalence
+// ratio (lambda) and the current in mA of a wideband oxygen sensor, for cars
+// reporting the current instead of the voltage (see O2SensorLambdaVoltage).
+//
+// The sensors are numbered as for O2SensorVoltage.
+//
+// Lambda Min: 0
+// Lambda Max: 2
+// Current Min: -128
+// Current Max: 128
+type O2SensorLambdaCurrent struct {
+	baseCommand
+	Bank    int
+	Sensor  int
+	Lambda  float64
+	Current float64
+}
+
+// NewO2SensorLambdaCurrent creates a new O2SensorLambdaCurrent for the given
+// sensor of the given bank (PIDs 34 to 3B). The bank is clamped to 1 to 2
+// and the sensor to 1 to 4.
+func NewO2SensorLambdaCurrent(bank int, sensor int) *O2SensorLambdaCurrent {
+	bank, sensor = clampO2Sensor(bank, sensor)
+
+	return &O2SensorLambdaCurrent{
+		baseCommand{
+			SERVICE_01_ID,
+			o2SensorParameterID(0x34, bank, sensor),
+			4,
+			fmt.Sprintf("o2_sensor_lambda_current_bank%d_sensor%d", bank, sensor),
+		},
+		bank,
+		sensor,
+		0,
+		0,
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *O2SensorLambdaCurrent) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *O2SensorLambdaCurrent) value() interface{} {
+	return struct {
+		Lambda  float64 `json:"lambda"`
+		Current float64 `json:"current"`
+	}{
+		cmd.Lambda,
+		cmd.Current,
+	}
+}
+
+// floatFields returns the lambda and the current, see Smoother.
+func (cmd *O2SensorLambdaCurrent) floatFields() map[string]*float64 {
+	return map[string]*float64{"lambda": &cmd.Lambda, "current": &cmd.Current}
+}
+
+// SetValue processes the byte array value into the lambda and current.
+func (cmd *O2SensorLambdaCurrent) SetValue(result *Result) error {
+	lambda, current, err := widebandO2Payload(result)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Lambda = lambda
+	cmd.Current = float64(current)/256 - 128
+
+	return nil
+}
+
+// CommandedEGR represents a command that checks the commanded exhaust gas
+// recirculation in percent.
+//
+// Min: 0.0
+// Max: 1.0
+type CommandedEGR struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewCommandedEGR creates a new CommandedEGR with the right parameters.
+func NewCommandedEGR() *CommandedEGR {
+	return &CommandedEGR{
+		baseCommand{SERVICE_01_ID, 0x2C, 1, "commanded_egr"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *CommandedEGR) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 255)
+
+	return nil
+}
+
+// EGRError represents a command that checks the exhaust gas recirculation
+// error in percent, which is how far the actual EGR is from the commanded EGR
+// (see CommandedEGR).
+//
+// Min: -100 (less than commanded)
+// Max: 99.2 (more than commanded)
+type EGRError struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewEGRError creates a new EGRError with the right parameters.
+func NewEGRError() *EGRError {
+	return &EGRError{
+		baseCommand{SERVICE_01_ID, 0x2D, 1, "egr_error"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *EGRError) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64((float64(payload) / 1.28) - 100)
+
+	return nil
+}
+
+// CommandedEvaporativePurge represents a command that checks the commanded
+// duty cycle of the evaporative purge valve in percent.
+//
+// Min: 0.0
+// Max: 1.0
+type CommandedEvaporativePurge struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewCommandedEvaporativePurge creates a new CommandedEvaporativePurge with
+// the right parameters.
+func NewCommandedEvaporativePurge() *CommandedEvaporativePurge {
+	return &CommandedEvaporativePurge{
+		baseCommand{SERVICE_01_ID, 0x2E, 1, "commanded_evaporative_purge"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *CommandedEvaporativePurge) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 255)
+
+	return nil
+}
+
+// WarmUpsSinceDTCClear represents a command that checks the amount of
+// warm-up cycles since the trouble codes were cleared last time.
+//
+// Min: 0
+// Max: 255
+type WarmUpsSinceDTCClear struct {
+	baseCommand
+	UIntCommand
+}
+
+// NewWarmUpsSinceDTCClear creates a new WarmUpsSinceDTCClear with the right
+// parameters.
+func NewWarmUpsSinceDTCClear() *WarmUpsSinceDTCClear {
+	return &WarmUpsSinceDTCClear{
+		baseCommand{SERVICE_01_ID, 0x30, 1, "warm_ups_since_dtc_clear"},
+		UIntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right uint value.
+func (cmd *WarmUpsSinceDTCClear) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = uint32(payload)
+
+	return nil
+}
+
+// EvapVaporPressure represents a command that checks the vapor pressure of
+// the evaporative system in Pa, such as when diagnosing small leaks.
+//
+// Min: -8192
+// Max: 8191.75
+type EvapVaporPressure struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewEvapVaporPressure creates a new EvapVaporPressure with the right
+// parameters.
+func NewEvapVaporPressure() *EvapVaporPressure {
+	return &EvapVaporPressure{
+		baseCommand{SERVICE_01_ID, 0x32, 2, "evap_vapor_pressure"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value. The
+// payload is a signed two's complement value.
+func (cmd *EvapVaporPressure) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(int16(payload)) / 4)
+
+	return nil
+}
+
+// AbsoluteEvapVaporPressure represents a command that checks the absolute
+// vapor pressure of the evaporative system in kPa.
+//
+// Min: 0
+// Max: 327.675
+type AbsoluteEvapVaporPressure struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewAbsoluteEvapVaporPressure creates a new AbsoluteEvapVaporPressure with
+// the right parameters.
+func NewAbsoluteEvapVaporPressure() *AbsoluteEvapVaporPressure {
+	return &AbsoluteEvapVaporPressure{
+		baseCommand{SERVICE_01_ID, 0x53, 2, "absolute_evap_vapor_pressure"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *AbsoluteEvapVaporPressure) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 200)
+
+	return nil
+}
+
+// EvapVaporPressureWide represents a command that checks the vapor pressure
+// of the evaporative system in Pa, with a wider range but lower resolution
+// than EvapVaporPressure.
+//
+// Min: -32768
+// Max: 32767
+type EvapVaporPressureWide struct {
+	baseCommand
+	IntCommand
+}
+
+// NewEvapVaporPressureWide creates a new EvapVaporPressureWide with the right
+// parameters.
+func NewEvapVaporPressureWide() *EvapVaporPressureWide {
+	return &EvapVaporPressureWide{
+		baseCommand{SERVICE_01_ID, 0x54, 2, "evap_vapor_pressure_wide"},
+		IntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right integer value. The
+// payload is a signed two's complement value.
+func (cmd *EvapVaporPressureWide) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = int(int16(payload))
+
+	return nil
+}
+
+// monitorPayload retrieves the 4 byte payload of the monitor status
+// commands, see MonitorStatus and MonitorStatusThisDriveCycle.
+func monitorPayload(result *Result) ([]byte, error) {
+	expAmount := 4
+	payload := result.value[2:]
+	amount := len(payload)
+
+	if amount != expAmount {
+		return nil, fmt.Errorf(
+			"Expected %d bytes of payload, got %d", expAmount, amount,
+		)
+	}
+
+	return payload, nil
+}
+
+// MonitorTest represents the state of one of the on-board monitors, which
+// test the emission related systems of the car.
+//
+// Continuous monitors (misfire, fuel system and components) run all the
+// time, while the non-continuous monitors run once the conditions for the
+// test are met during a drive cycle.
+type MonitorTest struct {
+	Name       string `json:"name"`
+	Continuous bool   `json:"continuous"`
+	Available  bool   `json:"available"`
+	Complete   bool   `json:"complete"`
+}
+
+// Monitors represents the state of all the on-board monitors, as reported by
+// the monitor status commands.
+//
+// The first 3 tests (misfire, fuel system and components) are the continuous
+// monitors, the rest are the non-continuous monitors, which differ between
+// spark ignition (petrol) and compression ignition (diesel) engines.
+type Monitors struct {
+	CompressionIgnition bool          `json:"compression_ignition"`
+	Tests               []MonitorTest `json:"tests"`
+}
+
+// Test retrieves the test with the given name, such as "catalyst". The second
+// return value is false if there is no test with that name for the ignition
+// type of the car.
+func (mon Monitors) Test(name string) (MonitorTest, bool) {
+	for _, test := range mon.Tests {
+		if test.Name == name {
+			return test, true
+		}
+	}
+
+	return MonitorTest{}, false
+}
+
+// Ready returns true if all available monitors are complete, which is what
+// emission inspections check.
+func (mon Monitors) Ready() bool {
+	for _, test := range mon.Tests {
+		if test.Available && !test.Complete {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Incomplete retrieves the available monitors that are not complete.
+func (mon Monitors) Incomplete() []MonitorTest {
+	var tests []MonitorTest
+
+	for _, test := range mon.Tests {
+		if test.Available && !test.Complete {
+			tests = append(tests, test)
+		}
+	}
+
+	return tests
+}
+
+// continuousMonitors are the names of the monitors of byte B of the monitor
+// status payload, starting with the least significant bit.
+var continuousMonitors = []string{
+	"misfire",
+	"fuel_system",
+	"components",
+}
+
+// sparkMonitors and compressionMonitors are the names of the monitors of byte
+// C and D of the monitor status payload, starting with the least significant
+// bit. Reserved bits are left empty.
+var sparkMonitors = []string{
+	"catalyst",
+	"heated_catalyst",
+	"evaporative_system",
+	"secondary_air_system",
+	"",
+	"oxygen_sensor",
+	"oxygen_sensor_heater",
+	"egr_vvt_system",
+}
+
+var compressionMonitors = []string{
+	"nmhc_catalyst",
+	"nox_scr_monitor",
+	"",
+	"boost_pressure",
+	"",
+	"exhaust_gas_sensor",
+	"pm_filter",
+	"egr_vvt_system",
+}
+
+// decodeMonitors decodes the bytes B, C and D of a monitor status payload.
+//
+// In byte B the lower 3 bits tell which continuous monitors are available and
+// the upper 3 bits which are incomplete, bit 3 is set for compression
+// ignition. Byte C tells which non-continuous monitors are available and byte
+// D which are incomplete.
+func decodeMonitors(b byte, c byte, d byte) Monitors {
+	mon := Monitors{CompressionIgnition: b&0x08 == 0x08}
+
+	for bit, name := range continuousMonitors {
+		available := b&(1<<bit) != 0
+		incomplete := b&(1<<(bit+4)) != 0
+
+		mon.Tests = append(mon.Tests, MonitorTest{name, true, available, available && !incomplete})
+	}
+
+	names := sparkMonitors
+
+	if mon.CompressionIgnition {
+		names = compressionMonitors
+	}
+
+	for bit, name := range names {
+		if name == "" {
+			continue
+		}
+
+		available := c&(1<<bit) != 0
+		incomplete := d&(1<<bit) != 0
+
+		mon.Tests = append(mon.Tests, MonitorTest{name, false, available, available && !incomplete})
+	}
+
+	return mon
+}
+
+// MonitorStatusThisDriveCycle represents a command that checks the state of
+// the on-board monitors during the current drive cycle. Unlike MonitorStatus
+// the state is reset at the start of each drive cycle, and Available tells
+// whether the monitor is enabled for this drive cycle.
+type MonitorStatusThisDriveCycle struct {
+	baseCommand
+	Monitors
+}
+
+// NewMonitorStatusThisDriveCycle creates a new MonitorStatusThisDriveCycle
+// with the right parameters.
+func NewMonitorStatusThisDriveCycle() *MonitorStatusThisDriveCycle {
+	return &MonitorStatusThisDriveCycle{
+		baseCommand{SERVICE_01_ID, 0x41, 4, "monitor_status_this_drive_cycle"},
+		Monitors{},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation, which is the
+// monitors as JSON.
+func (cmd *MonitorStatusThisDriveCycle) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.Monitors)
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the monitors, used when exporting readings.
+func (cmd *MonitorStatusThisDriveCycle) value() interface{} {
+	return cmd.Monitors
+}
+
+// SetValue processes the byte array value into the state of the monitors.
+func (cmd *MonitorStatusThisDriveCycle) SetValue(result *Result) error {
+	payload, err := monitorPayload(result)
+
+	if err != nil {
+		return err
+	}
+
+	// Byte A is reserved for this PID
+	cmd.Monitors = decodeMonitors(payload[1], payload[2], payload[3])
+
+	return nil
+}
+
+// AbsoluteLoad represents a command that checks the absolute load value in
+// percent, which is the air mass per intake stroke relative to the air mass
+// at full load. Unlike EngineLoad the value is not limited to 1.0, it goes
+// above 1.0 on boosted engines.
+//
+// Min: 0.0
+// Max: 257.0
+type AbsoluteLoad struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewAbsoluteLoad creates a new AbsoluteLoad with the right parameters.
+func NewAbsoluteLoad() *AbsoluteLoad {
+	return &AbsoluteLoad{
+		baseCommand{SERVICE_01_ID, 0x43, 2, "absolute_load"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *AbsoluteLoad) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 255)
+
+	return nil
+}
+
+// CommandedEquivalenceRatio represents a command that checks the commanded
+// equivalence ratio (lambda) of the air-fuel mixture, where 1.0 is a
+// stoichiometric mixture, below 1.0 is rich and above 1.0 is lean.
+//
+// Min: 0
+// Max: 2
+type CommandedEquivalenceRatio struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewCommandedEquivalenceRatio creates a new CommandedEquivalenceRatio with
+// the right parameters.
+func NewCommandedEquivalenceRatio() *CommandedEquivalenceRatio {
+	return &CommandedEquivalenceRatio{
+		baseCommand{SERVICE_01_ID, 0x44, 2, "commanded_equivalence_ratio"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *CommandedEquivalenceRatio) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) * 2 / 65536)
+
+	return nil
+}
+
+// percentage is an abstract type for commands with a single byte value in
+// percentage, such as the throttle and pedal positions.
+//
+// Min: 0.0
+// Max: 1.0
+type percentage struct {
+	baseCommand
+	FloatCommand
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *percentage) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 255)
+
+	return nil
+}
+
+// RelativeThrottlePosition represents a command that checks the throttle
+// position in percentage, relative to the learned closed position.
+//
+// Min: 0.0
+// Max: 1.0
+type RelativeThrottlePosition struct {
+	percentage
+}
+
+// NewRelativeThrottlePosition creates a new RelativeThrottlePosition with the
+// right parameters.
+func NewRelativeThrottlePosition() *RelativeThrottlePosition {
+	return &RelativeThrottlePosition{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x45, 1, "relative_throttle_position"},
+			FloatCommand{},
+		},
+	}
+}
+
+// AbsoluteThrottlePositionB represents a command that checks the position of
+// the second throttle position sensor in percentage. Drive-by-wire throttles
+// have redundant sensors, which are compared against each other.
+//
+// Min: 0.0
+// Max: 1.0
+type AbsoluteThrottlePositionB struct {
+	percentage
+}
+
+// NewAbsoluteThrottlePositionB creates a new AbsoluteThrottlePositionB with
+// the right parameters.
+func NewAbsoluteThrottlePositionB() *AbsoluteThrottlePositionB {
+	return &AbsoluteThrottlePositionB{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x47, 1, "absolute_throttle_position_b"},
+			FloatCommand{},
+		},
+	}
+}
+
+// AbsoluteThrottlePositionC represents a command that checks the position of
+// the third throttle position sensor in percentage.
+//
+// Min: 0.0
+// Max: 1.0
+type AbsoluteThrottlePositionC struct {
+	percentage
+}
+
+// NewAbsoluteThrottlePositionC creates a new AbsoluteThrottlePositionC with
+// the right parameters.
+func NewAbsoluteThrottlePositionC() *AbsoluteThrottlePositionC {
+	return &AbsoluteThrottlePositionC{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x48, 1, "absolute_throttle_position_c"},
+			FloatCommand{},
+		},
+	}
+}
+
+// AcceleratorPedalPositionD represents a command that checks the position of
+// accelerator pedal sensor D in percentage. Drive-by-wire pedals have
+// redundant sensors (D, E and F), which are compared against each other.
+//
+// Min: 0.0
+// Max: 1.0
+type AcceleratorPedalPositionD struct {
+	percentage
+}
+
+// NewAcceleratorPedalPositionD creates a new AcceleratorPedalPositionD with
+// the right parameters.
+func NewAcceleratorPedalPositionD() *AcceleratorPedalPositionD {
+	return &AcceleratorPedalPositionD{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x49, 1, "accelerator_pedal_position_d"},
+			FloatCommand{},
+		},
+	}
+}
+
+// AcceleratorPedalPositionE represents a command that checks the position of
+// accelerator pedal sensor E in percentage.
+//
+// Min: 0.0
+// Max: 1.0
+type AcceleratorPedalPositionE struct {
+	percentage
+}
+
+// NewAcceleratorPedalPositionE creates a new AcceleratorPedalPositionE with
+// the right parameters.
+func NewAcceleratorPedalPositionE() *AcceleratorPedalPositionE {
+	return &AcceleratorPedalPositionE{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x4A, 1, "accelerator_pedal_position_e"},
+			FloatCommand{},
+		},
+	}
+}
+
+// AcceleratorPedalPositionF represents a command that checks the position of
+// accelerator pedal sensor F in percentage.
+//
+// Min: 0.0
+// Max: 1.0
+type AcceleratorPedalPositionF struct {
+	percentage
+}
+
+// NewAcceleratorPedalPositionF creates a new AcceleratorPedalPositionF with
+// the right parameters.
+func NewAcceleratorPedalPositionF() *AcceleratorPedalPositionF {
+	return &AcceleratorPedalPositionF{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x4B, 1, "accelerator_pedal_position_f"},
+			FloatCommand{},
+		},
+	}
+}
+
+// CommandedThrottleActuator represents a command that checks the commanded
+// position of the throttle actuator in percentage, which is compared against
+// the accelerator pedal positions to tell how the driver demand is acted on.
+//
+// Min: 0.0
+// Max: 1.0
+type CommandedThrottleActuator struct {
+	percentage
+}
+
+// NewCommandedThrottleActuator creates a new CommandedThrottleActuator with
+// the right parameters.
+func NewCommandedThrottleActuator() *CommandedThrottleActuator {
+	return &CommandedThrottleActuator{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x4C, 1, "commanded_throttle_actuator"},
+			FloatCommand{},
+		},
+	}
+}
+
+// TimeWithMILOn represents a command that checks the time in minutes the
+// engine has been run with the malfunction indicator lamp (check engine
+// light) on.
+//
+// Min: 0
+// Max: 65535
+type TimeWithMILOn struct {
+	baseCommand
+	UIntCommand
+}
+
+// NewTimeWithMILOn creates a new TimeWithMILOn with the right parameters.
+func NewTimeWithMILOn() *TimeWithMILOn {
+	return &TimeWithMILOn{
+		baseCommand{SERVICE_01_ID, 0x4D, 2, "time_with_mil_on"},
+		UIntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right uint value.
+func (cmd *TimeWithMILOn) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = uint32(payload)
+
+	return nil
+}
+
+// TimeSinceDTCClear represents a command that checks the time in minutes
+// since the trouble codes were cleared last time. A low value together with
+// a low DistSinceDTCClear means the codes were cleared recently.
+//
+// Min: 0
+// Max: 65535
+type TimeSinceDTCClear struct {
+	baseCommand
+	UIntCommand
+}
+
+// NewTimeSinceDTCClear creates a new TimeSinceDTCClear with the right
+// parameters.
+func NewTimeSinceDTCClear() *TimeSinceDTCClear {
+	return &TimeSinceDTCClear{
+		baseCommand{SERVICE_01_ID, 0x4E, 2, "time_since_dtc_clear"},
+		UIntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right uint value.
+func (cmd *TimeSinceDTCClear) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = uint32(payload)
+
+	return nil
+}
+
+// MaximumValues represents a command that checks the maximum values the car
+// reports for the equivalence ratio, the oxygen sensor voltage and current,
+// and the intake manifold absolute pressure. Used for scaling gauges of the
+// values for the car.
+//
+// A value of 0 means the maximum is not reported.
+//
+// MaxEquivalenceRatio Max: 255
+// MaxO2Voltage Max: 255 (V)
+// MaxO2Current Max: 255 (mA)
+// MaxIntakeManifoldPressure Max: 2550 (kPa)
+type MaximumValues struct {
+	baseCommand
+	MaxEquivalenceRatio       uint32
+	MaxO2Voltage              uint32
+	MaxO2Current              uint32
+	MaxIntakeManifoldPressure uint32
+}
+
+// NewMaximumValues creates a new MaximumValues with the right parameters.
+func NewMaximumValues() *MaximumValues {
+	return &MaximumValues{
+		baseCommand{SERVICE_01_ID, 0x4F, 4, "maximum_values"},
+		0,
+		0,
+		0,
+		0,
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *MaximumValues) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *MaximumValues) value() interface{} {
+	return struct {
+		MaxEquivalenceRatio       uint32 `json:"max_equivalence_ratio"`
+		MaxO2Voltage              uint32 `json:"max_o2_voltage"`
+		MaxO2Current              uint32 `json:"max_o2_current"`
+		MaxIntakeManifoldPressure uint32 `json:"max_intake_manifold_pressure"`
+	}{
+		cmd.MaxEquivalenceRatio,
+		cmd.MaxO2Voltage,
+		cmd.MaxO2Current,
+		cmd.MaxIntakeManifoldPressure,
+	}
+}
+
+// SetValue processes the byte array value into the maximum values.
+func (cmd *MaximumValues) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt32()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.MaxEquivalenceRatio = payload >> 24
+	cmd.MaxO2Voltage = (payload >> 16) & 0xFF
+	cmd.MaxO2Current = (payload >> 8) & 0xFF
+	cmd.MaxIntakeManifoldPressure = (payload & 0xFF) * 10
+
+	return nil
+}
+
+// MaximumMafAirFlowRate represents a command that checks the maximum value
+// the car reports for the mass air flow rate in grams/sec.
+//
+// Min: 0
+// Max: 2550
+type MaximumMafAirFlowRate struct {
+	baseCommand
+	UIntCommand
+}
+
+// NewMaximumMafAirFlowRate creates a new MaximumMafAirFlowRate with the right
+// parameters.
+func NewMaximumMafAirFlowRate() *MaximumMafAirFlowRate {
+	return &MaximumMafAirFlowRate{
+		baseCommand{SERVICE_01_ID, 0x50, 4, "maximum_maf_air_flow_rate"},
+		UIntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right uint value. Only
+// the first byte is used, the other bytes are reserved.
+func (cmd *MaximumMafAirFlowRate) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt32()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = (payload >> 24) * 10
+
+	return nil
+}
+
+// FuelKind represents the type of fuel the car runs on, see FuelType.
+type FuelKind byte
+
+// The fuel kinds, as defined by SAE J1979.
+const (
+	FuelNotAvailable FuelKind = iota
+	FuelGasoline
+	FuelMethanol
+	FuelEthanol
+	FuelDiesel
+	FuelLPG
+	FuelCNG
+	FuelPropane
+	FuelElectric
+	FuelBifuelGasoline
+	FuelBifuelMethanol
+	FuelBifuelEthanol
+	FuelBifuelLPG
+	FuelBifuelCNG
+	FuelBifuelPropane
+	FuelBifuelElectric
+	FuelBifuelElectricCombustion
+	FuelHybridGasoline
+	FuelHybridEthanol
+	FuelHybridDiesel
+	FuelHybridElectric
+	FuelHybridElectricCombustion
+	FuelHybridRegenerative
+	FuelBifuelDiesel
+)
+
+var fuelKindNames = []string{
+	"not available",
+	"gasoline",
+	"methanol",
+	"ethanol",
+	"diesel",
+	"LPG",
+	"CNG",
+	"propane",
+	"electric",
+	"bifuel running gasoline",
+	"bifuel running methanol",
+	"bifuel running ethanol",
+	"bifuel running LPG",
+	"bifuel running CNG",
+	"bifuel running propane",
+	"bifuel running electricity",
+	"bifuel running electric and combustion engine",
+	"hybrid gasoline",
+	"hybrid ethanol",
+	"hybrid diesel",
+	"hybrid electric",
+	"hybrid running electric and combustion engine",
+	"hybrid regenerative",
+	"bifuel running diesel",
+}
+
+// String returns the description of the fuel kind, such as "hybrid diesel".
+func (kind FuelKind) String() string {
+	if int(kind) < len(fuelKindNames) {
+		return fuelKindNames[kind]
+	}
+
+	return fmt.Sprintf("unknown (%d)", byte(kind))
+}
+
+// Properties returns the properties of the fuel used when calculating the
+// fuel consumption (see Device.GetFuelConsumption). The second return value
+// is false if the properties of the fuel kind are not known.
+func (kind FuelKind) Properties() (FuelProperties, bool) {
+	switch kind {
+	case FuelGasoline, FuelBifuelGasoline, FuelHybridGasoline:
+		return Gasoline, true
+	case FuelDiesel, FuelBifuelDiesel, FuelHybridDiesel:
+		return Diesel, true
+	}
+
+	return FuelProperties{}, false
+}
+
+// FuelType represents a command that checks the type of fuel the car runs
+// on.
+type FuelType struct {
+	baseCommand
+	Value FuelKind
+}
+
+// NewFuelType creates a new FuelType with the right parameters.
+func NewFuelType() *FuelType {
+	return &FuelType{
+		baseCommand{SERVICE_01_ID, 0x51, 1, "fuel_type"},
+		FuelNotAvailable,
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *FuelType) ValueAsLit() string {
+	return cmd.Value.String()
+}
+
+// value retrieves the value as a string, used when exporting readings.
+func (cmd *FuelType) value() interface{} {
+	return cmd.Value.String()
+}
+
+// SetValue processes the byte array value into the right fuel kind.
+func (cmd *FuelType) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = FuelKind(payload)
+
+	return nil
+}
+
+// EthanolFuel represents a command that checks the ethanol content of the
+// fuel in percentage, as measured on flex fuel cars.
+//
+// Min: 0.0
+// Max: 1.0
+type EthanolFuel struct {
+	percentage
+}
+
+// NewEthanolFuel creates a new EthanolFuel with the right parameters.
+func NewEthanolFuel() *EthanolFuel {
+	return &EthanolFuel{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x52, 1, "ethanol_fuel"},
+			FloatCommand{},
+		},
+	}
+}
+
+// RelativeAcceleratorPedalPosition represents a command that checks the
+// accelerator pedal position in percentage, relative to the learned released
+// position.
+//
+// Min: 0.0
+// Max: 1.0
+type RelativeAcceleratorPedalPosition struct {
+	percentage
+}
+
+// NewRelativeAcceleratorPedalPosition creates a new
+// RelativeAcceleratorPedalPosition with the right parameters.
+func NewRelativeAcceleratorPedalPosition() *RelativeAcceleratorPedalPosition {
+	return &RelativeAcceleratorPedalPosition{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x5A, 1, "relative_accelerator_pedal_position"},
+			FloatCommand{},
+		},
+	}
+}
+
+// HybridBatteryRemainingLife represents a command that checks the remaining
+// charge of the hybrid battery pack in percentage.
+//
+// Min: 0.0
+// Max: 1.0
+type HybridBatteryRemainingLife struct {
+	percentage
+}
+
+// NewHybridBatteryRemainingLife creates a new HybridBatteryRemainingLife with
+// the right parameters.
+func NewHybridBatteryRemainingLife() *HybridBatteryRemainingLife {
+	return &HybridBatteryRemainingLife{
+		percentage{
+			baseCommand{SERVICE_01_ID, 0x5B, 1, "hybrid_battery_remaining_life"},
+			FloatCommand{},
+		},
+	}
+}
+
+// FuelInjectionTiming represents a command that checks the fuel injection
+// timing in degrees, relative to top dead center.
+//
+// Min: -210
+// Max: 301.9921875
+type FuelInjectionTiming struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewFuelInjectionTiming creates a new FuelInjectionTiming with the right
+// parameters.
+func NewFuelInjectionTiming() *FuelInjectionTiming {
+	return &FuelInjectionTiming{
+		baseCommand{SERVICE_01_ID, 0x5D, 2, "fuel_injection_timing"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *FuelInjectionTiming) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload)/128 - 210)
+
+	return nil
+}
+
+// EngineFuelRate represents a command that checks the fuel rate of the
+// engine in liters per hour.
+//
+// Min: 0
+// Max: 3276.75
+type EngineFuelRate struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewEngineFuelRate creates a new EngineFuelRate with the right parameters.
+func NewEngineFuelRate() *EngineFuelRate {
+	return &EngineFuelRate{
+		baseCommand{SERVICE_01_ID, 0x5E, 2, "engine_fuel_rate"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *EngineFuelRate) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 20)
+
+	return nil
+}
+
+// percentTorque is an abstract type for the commands checking the engine
+// torque in percent of the engine reference torque (see
+// EngineReferenceTorque).
+//
+// Min: -125
+// Max: 130
+type percentTorque struct {
+	baseCommand
+	IntCommand
+}
+
+// SetValue processes the byte array value into the right integer value.
+func (cmd *percentTorque) SetValue(result *Result) error {
+	payload, err := result.PayloadAsByte()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = int(payload) - 125
+
+	return nil
+}
+
+// Torque calculates the torque in Nm from the value, using the given
+// processed reference torque.
+func (cmd *percentTorque) Torque(reference *EngineReferenceTorque) float64 {
+	return float64(cmd.Value) / 100 * float64(reference.Value)
+}
+
+// DriverDemandTorque represents a command that checks the torque requested by
+// the driver in percent of the engine reference torque.
+//
+// Min: -125
+// Max: 130
+type DriverDemandTorque struct {
+	percentTorque
+}
+
+// NewDriverDemandTorque creates a new DriverDemandTorque with the right
+// parameters.
+func NewDriverDemandTorque() *DriverDemandTorque {
+	return &DriverDemandTorque{
+		percentTorque{
+			baseCommand{SERVICE_01_ID, 0x61, 1, "driver_demand_torque"},
+			IntCommand{},
+		},
+	}
+}
+
+// ActualEngineTorque represents a command that checks the torque the engine
+// produces in percent of the engine reference torque. Use Torque to get the
+// actual torque output in Nm.
+//
+// Min: -125
+// Max: 130
+type ActualEngineTorque struct {
+	percentTorque
+}
+
+// NewActualEngineTorque creates a new ActualEngineTorque with the right
+// parameters.
+func NewActualEngineTorque() *ActualEngineTorque {
+	return &ActualEngineTorque{
+		percentTorque{
+			baseCommand{SERVICE_01_ID, 0x62, 1, "actual_engine_torque"},
+			IntCommand{},
+		},
+	}
+}
+
+// EngineReferenceTorque represents a command that checks the reference torque
+// of the engine in Nm, which the percent torque values are relative to.
+//
+// Min: 0
+// Max: 65535
+type EngineReferenceTorque struct {
+	baseCommand
+	UIntCommand
+}
+
+// NewEngineReferenceTorque creates a new EngineReferenceTorque with the right
+// parameters.
+func NewEngineReferenceTorque() *EngineReferenceTorque {
+	return &EngineReferenceTorque{
+		baseCommand{SERVICE_01_ID, 0x63, 2, "engine_reference_torque"},
+		UIntCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right uint value.
+func (cmd *EngineReferenceTorque) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Value = uint32(payload)
+
+	return nil
+}
+
+// EnginePercentTorqueData represents a command that checks the torque of the
+// engine at idle and at four engine operating points, in percent of the
+// engine reference torque (see EngineReferenceTorque).
+//
+// Min: -125
+// Max: 130
+type EnginePercentTorqueData struct {
+	baseCommand
+	Idle   int
+	Points [4]int
+}
+
+// NewEnginePercentTorqueData creates a new EnginePercentTorqueData with the
+// right parameters.
+func NewEnginePercentTorqueData() *EnginePercentTorqueData {
+	return &EnginePercentTorqueData{
+		baseCommand{SERVICE_01_ID, 0x64, 5, "engine_percent_torque_data"},
+		0,
+		[4]int{},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *EnginePercentTorqueData) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *EnginePercentTorqueData) value() interface{} {
+	return struct {
+		Idle   int    `json:"idle"`
+		Points [4]int `json:"points"`
+	}{
+		cmd.Idle,
+		cmd.Points,
+	}
+}
+
+// SetValue processes the byte array value into the torque at idle and at
+// each engine operating point.
+func (cmd *EnginePercentTorqueData) SetValue(result *Result) error {
+	expAmount := 5
+	payload := result.value[2:]
+	amount := len(payload)
+
+	if amount != expAmount {
+		return fmt.Errorf(
+			"Expected %d bytes of payload, got %d", expAmount, amount,
+		)
+	}
+
+	cmd.Idle = int(payload[0]) - 125
+
+	for i := range cmd.Points {
+		cmd.Points[i] = int(payload[i+1]) - 125
+	}
+
+	return nil
+}
+
+// AuxiliaryStatus represents the status of one of the auxiliary inputs and
+// outputs, see AuxiliaryInputOutput.
+type AuxiliaryStatus struct {
+	Supported bool `json:"supported"`
+	Active    bool `json:"active"`
+}
+
+// AuxiliaryInputOutput represents a command that checks the status of the
+// auxiliary inputs and outputs, used on work trucks and diesel engines:
+//
+// - PowerTakeOff is active when the power take-off is engaged
+// - AutoTransNeutral is active when the automatic transmission is in neutral
+// - ManualTransNeutral is active when the manual transmission is in neutral
+//   or the clutch is pressed
+// - GlowPlugLamp is active when the glow plug lamp is lit
+type AuxiliaryInputOutput struct {
+	baseCommand
+	PowerTakeOff       AuxiliaryStatus
+	AutoTransNeutral   AuxiliaryStatus
+	ManualTransNeutral AuxiliaryStatus
+	GlowPlugLamp       AuxiliaryStatus
+}
+
+// NewAuxiliaryInputOutput creates a new AuxiliaryInputOutput with the right
+// parameters.
+func NewAuxiliaryInputOutput() *AuxiliaryInputOutput {
+	return &AuxiliaryInputOutput{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x65, 2, "auxiliary_input_output"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *AuxiliaryInputOutput) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *AuxiliaryInputOutput) value() interface{} {
+	return struct {
+		PowerTakeOff       AuxiliaryStatus `json:"power_take_off"`
+		AutoTransNeutral   AuxiliaryStatus `json:"auto_trans_neutral"`
+		ManualTransNeutral AuxiliaryStatus `json:"manual_trans_neutral"`
+		GlowPlugLamp       AuxiliaryStatus `json:"glow_plug_lamp"`
+	}{
+		cmd.PowerTakeOff,
+		cmd.AutoTransNeutral,
+		cmd.ManualTransNeutral,
+		cmd.GlowPlugLamp,
+	}
+}
+
+// SetValue processes the byte array value into the status of each input and
+// output. The first byte tells which are supported and the second byte tells
+// which are active.
+func (cmd *AuxiliaryInputOutput) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	supported := byte(payload >> 8)
+	active := byte(payload)
+
+	status := func(bit uint) AuxiliaryStatus {
+		return AuxiliaryStatus{
+			Supported: supported&(1<<bit) != 0,
+			Active:    supported&active&(1<<bit) != 0,
+		}
+	}
+
+	cmd.PowerTakeOff = status(0)
+	cmd.AutoTransNeutral = status(1)
+	cmd.ManualTransNeutral = status(2)
+	cmd.GlowPlugLamp = status(3)
+
+	return nil
+}
+
+// SensorValue represents the value of one of the sensors of a command that
+// reports several sensors at once. The value is zero when the sensor is not
+// supported.
+type SensorValue struct {
+	Supported bool    `json:"supported"`
+	Value     float64 `json:"value"`
+}
+
+// multiSensorPayload retrieves the payload of the commands that report
+// several sensors at once. The first byte tells which sensors are supported,
+// it is returned separately from the rest of the payload.
+func multiSensorPayload(result *Result, expAmount int) (byte, []byte, error) {
+	payload := result.value[2:]
+	amount := len(payload)
+
+	if amount != expAmount {
+		return 0, nil, fmt.Errorf(
+			"Expected %d bytes of payload, got %d", expAmount, amount,
+		)
+	}
+
+	return payload[0], payload[1:], nil
+}
+
+// newSensorValue creates a SensorValue for the sensor with the given bit in
+// the support byte of a multi sensor command.
+func newSensorValue(supported byte, bit uint, value float64) SensorValue {
+	if supported&(1<<bit) == 0 {
+		return SensorValue{}
+	}
+
+	return SensorValue{true, value}
+}
+
+// MafAirFlowRateSensors represents a command that checks the air flow rate
+// of each mass air flow sensor in grams/sec, used on engines with one sensor
+// per bank.
+//
+// Min: 0
+// Max: 2047.97
+type MafAirFlowRateSensors struct {
+	baseCommand
+	SensorA SensorValue
+	SensorB SensorValue
+}
+
+// NewMafAirFlowRateSensors creates a new MafAirFlowRateSensors with the right
+// parameters.
+func NewMafAirFlowRateSensors() *MafAirFlowRateSensors {
+	return &MafAirFlowRateSensors{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x66, 5, "maf_air_flow_rate_sensors"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *MafAirFlowRateSensors) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *MafAirFlowRateSensors) value() interface{} {
+	return struct {
+		SensorA SensorValue `json:"sensor_a"`
+		SensorB SensorValue `json:"sensor_b"`
+	}{cmd.SensorA, cmd.SensorB}
+}
+
+// SetValue processes the byte array value into the air flow rate of each
+// sensor.
+func (cmd *MafAirFlowRateSensors) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 5)
+
+	if err != nil {
+		return err
+	}
+
+	rate := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1])) / 32
+	}
+
+	cmd.SensorA = newSensorValue(supported, 0, rate(0))
+	cmd.SensorB = newSensorValue(supported, 1, rate(2))
+
+	return nil
+}
+
+// CoolantTemperatureSensors represents a command that checks the temperature
+// of each engine coolant temperature sensor in Celsius, used on engines with
+// more than one sensor.
+//
+// Min: -40
+// Max: 215
+type CoolantTemperatureSensors struct {
+	baseCommand
+	Sensor1 SensorValue
+	Sensor2 SensorValue
+}
+
+// NewCoolantTemperatureSensors creates a new CoolantTemperatureSensors with
+// the right parameters.
+func NewCoolantTemperatureSensors() *CoolantTemperatureSensors {
+	return &CoolantTemperatureSensors{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x67, 3, "coolant_temperature_sensors"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *CoolantTemperatureSensors) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *CoolantTemperatureSensors) value() interface{} {
+	return struct {
+		Sensor1 SensorValue `json:"sensor_1"`
+		Sensor2 SensorValue `json:"sensor_2"`
+	}{cmd.Sensor1, cmd.Sensor2}
+}
+
+// SetValue processes the byte array value into the temperature of each
+// sensor.
+func (cmd *CoolantTemperatureSensors) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 3)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Sensor1 = newSensorValue(supported, 0, float64(payload[0])-40)
+	cmd.Sensor2 = newSensorValue(supported, 1, float64(payload[1])-40)
+
+	return nil
+}
+
+// IntakeAirTemperatureSensors represents a command that checks the
+// temperature of each intake air temperature sensor in Celsius, used on
+// engines with more than one sensor. There are up to 3 sensors per bank, the
+// first sensor of each bank is at index 0.
+//
+// Min: -40
+// Max: 215
+type IntakeAirTemperatureSensors struct {
+	baseCommand
+	Bank1 [3]SensorValue
+	Bank2 [3]SensorValue
+}
+
+// NewIntakeAirTemperatureSensors creates a new IntakeAirTemperatureSensors
+// with the right parameters.
+func NewIntakeAirTemperatureSensors() *IntakeAirTemperatureSensors {
+	return &IntakeAirTemperatureSensors{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x68, 7, "intake_air_temperature_sensors"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *IntakeAirTemperatureSensors) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *IntakeAirTemperatureSensors) value() interface{} {
+	return struct {
+		Bank1 [3]SensorValue `json:"bank_1"`
+		Bank2 [3]SensorValue `json:"bank_2"`
+	}{cmd.Bank1, cmd.Bank2}
+}
+
+// SetValue processes the byte array value into the temperature of each
+// sensor.
+func (cmd *IntakeAirTemperatureSensors) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 7)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range cmd.Bank1 {
+		cmd.Bank1[i] = newSensorValue(supported, uint(i), float64(payload[i])-40)
+		cmd.Bank2[i] = newSensorValue(supported, uint(i+3), float64(payload[i+3])-40)
+	}
+
+	return nil
+}
+
+// FuelRailControl represents the commanded and actual pressure in kPa and the
+// fuel temperature in Celsius of one fuel rail, see FuelPressureControl.
+type FuelRailControl struct {
+	Commanded   SensorValue `json:"commanded"`
+	Actual      SensorValue `json:"actual"`
+	Temperature SensorValue `json:"temperature"`
+}
+
+// FuelPressureControl represents a command that checks the fuel pressure
+// control system, used on common rail diesel engines. Engines with two fuel
+// rails report both RailA and RailB.
+//
+// Pressure Min: 0
+// Pressure Max: 655350
+// Temperature Min: -40
+// Temperature Max: 215
+type FuelPressureControl struct {
+	baseCommand
+	RailA FuelRailControl
+	RailB FuelRailControl
+}
+
+// NewFuelPressureControl creates a new FuelPressureControl with the right
+// parameters.
+func NewFuelPressureControl() *FuelPressureControl {
+	return &FuelPressureControl{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x6D, 11, "fuel_pressure_control"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *FuelPressureControl) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *FuelPressureControl) value() interface{} {
+	return struct {
+		RailA FuelRailControl `json:"rail_a"`
+		RailB FuelRailControl `json:"rail_b"`
+	}{cmd.RailA, cmd.RailB}
+}
+
+// SetValue processes the byte array value into the pressures and temperature
+// of each fuel rail.
+func (cmd *FuelPressureControl) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 11)
+
+	if err != nil {
+		return err
+	}
+
+	rail := func(offset int, bit uint) FuelRailControl {
+		pressure := func(i int) float64 {
+			return float64(uint16(payload[i])<<8|uint16(payload[i+1])) * 10
+		}
+
+		return FuelRailControl{
+			Commanded:   newSensorValue(supported, bit, pressure(offset)),
+			Actual:      newSensorValue(supported, bit+1, pressure(offset+2)),
+			Temperature: newSensorValue(supported, bit+2, float64(payload[offset+4])-40),
+		}
+	}
+
+	cmd.RailA = rail(0, 0)
+	cmd.RailB = rail(5, 3)
+
+	return nil
+}
+
+// ControlLoopStatus represents the state of a control system, such as the
+// boost pressure control, see BoostPressureControl.
+type ControlLoopStatus byte
+
+const (
+	// ControlLoopUnknown means the status is not supported.
+	ControlLoopUnknown ControlLoopStatus = 0
+	// ControlLoopOpen means the system runs in open loop, without feedback.
+	ControlLoopOpen ControlLoopStatus = 1
+	// ControlLoopClosed means the system runs in closed loop, using the
+	// actual value as feedback.
+	ControlLoopClosed ControlLoopStatus = 2
+	// ControlLoopFault means a fault is present in the system.
+	ControlLoopFault ControlLoopStatus = 3
+)
+
+// String returns the literal representation of the status.
+func (status ControlLoopStatus) String() string {
+	switch status {
+	case ControlLoopUnknown:
+		return "unknown"
+	case ControlLoopOpen:
+		return "open loop"
+	case ControlLoopClosed:
+		return "closed loop"
+	case ControlLoopFault:
+		return "fault"
+	}
+
+	return fmt.Sprintf("unknown (0x%02X)", byte(status))
+}
+
+// MarshalText encodes the status as its literal representation, so that it
+// is readable when exporting readings.
+func (status ControlLoopStatus) MarshalText() ([]byte, error) {
+	return []byte(status.String()), nil
+}
+
+// BoostControl represents the commanded and actual boost pressure in kPa and
+// the control status of one turbocharger, see BoostPressureControl.
+type BoostControl struct {
+	Commanded SensorValue       `json:"commanded"`
+	Actual    SensorValue       `json:"actual"`
+	Status    ControlLoopStatus `json:"status"`
+}
+
+// BoostPressureControl represents a command that checks the boost pressure
+// control of the turbochargers, engines with two turbochargers report both
+// TurboA and TurboB.
+//
+// Pressure Min: 0
+// Pressure Max: 2047.97
+type BoostPressureControl struct {
+	baseCommand
+	TurboA BoostControl
+	TurboB BoostControl
+}
+
+// NewBoostPressureControl creates a new BoostPressureControl with the right
+// parameters.
+func NewBoostPressureControl() *BoostPressureControl {
+	return &BoostPressureControl{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x70, 10, "boost_pressure_control"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *BoostPressureControl) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *BoostPressureControl) value() interface{} {
+	return struct {
+		TurboA BoostControl `json:"turbo_a"`
+		TurboB BoostControl `json:"turbo_b"`
+	}{cmd.TurboA, cmd.TurboB}
+}
+
+// SetValue processes the byte array value into the boost pressures and
+// control status of each turbocharger.
+func (cmd *BoostPressureControl) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 10)
+
+	if err != nil {
+		return err
+	}
+
+	pressure := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1])) / 32
+	}
+
+	status := func(bit uint, shift uint) ControlLoopStatus {
+		if supported&(1<<bit) == 0 {
+			return ControlLoopUnknown
+		}
+
+		return ControlLoopStatus(payload[8] >> shift & 0x03)
+	}
+
+	cmd.TurboA = BoostControl{
+		Commanded: newSensorValue(supported, 0, pressure(0)),
+		Actual:    newSensorValue(supported, 1, pressure(2)),
+		Status:    status(2, 0),
+	}
+	cmd.TurboB = BoostControl{
+		Commanded: newSensorValue(supported, 3, pressure(4)),
+		Actual:    newSensorValue(supported, 4, pressure(6)),
+		Status:    status(5, 2),
+	}
+
+	return nil
+}
+
+// VGTControl represents the commanded and actual position of the vanes of
+// one variable geometry turbocharger, in percentage, and its control status,
+// see VariableGeometryTurboControl.
+type VGTControl struct {
+	Commanded SensorValue       `json:"commanded"`
+	Actual    SensorValue       `json:"actual"`
+	Status    ControlLoopStatus `json:"status"`
+}
+
+// VariableGeometryTurboControl represents a command that checks the control
+// of the variable geometry turbochargers (VGT), engines with two
+// turbochargers report both TurboA and TurboB.
+//
+// Position Min: 0.0
+// Position Max: 1.0
+type VariableGeometryTurboControl struct {
+	baseCommand
+	TurboA VGTControl
+	TurboB VGTControl
+}
+
+// NewVariableGeometryTurboControl creates a new VariableGeometryTurboControl
+// with the right parameters.
+func NewVariableGeometryTurboControl() *VariableGeometryTurboControl {
+	return &VariableGeometryTurboControl{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x71, 6, "variable_geometry_turbo_control"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *VariableGeometryTurboControl) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *VariableGeometryTurboControl) value() interface{} {
+	return struct {
+		TurboA VGTControl `json:"turbo_a"`
+		TurboB VGTControl `json:"turbo_b"`
+	}{cmd.TurboA, cmd.TurboB}
+}
+
+// SetValue processes the byte array value into the vane positions and
+// control status of each turbocharger.
+func (cmd *VariableGeometryTurboControl) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 6)
+
+	if err != nil {
+		return err
+	}
+
+	status := func(bit uint, shift uint) ControlLoopStatus {
+		if supported&(1<<bit) == 0 {
+			return ControlLoopUnknown
+		}
+
+		return ControlLoopStatus(payload[4] >> shift & 0x03)
+	}
+
+	cmd.TurboA = VGTControl{
+		Commanded: newSensorValue(supported, 0, float64(payload[0])/255),
+		Actual:    newSensorValue(supported, 1, float64(payload[1])/255),
+		Status:    status(2, 0),
+	}
+	cmd.TurboB = VGTControl{
+		Commanded: newSensorValue(supported, 3, float64(payload[2])/255),
+		Actual:    newSensorValue(supported, 4, float64(payload[3])/255),
+		Status:    status(5, 2),
+	}
+
+	return nil
+}
+
+// WastegatePosition represents the commanded and actual position of one
+// wastegate in percentage, see WastegateControl.
+type WastegatePosition struct {
+	Commanded SensorValue `json:"commanded"`
+	Actual    SensorValue `json:"actual"`
+}
+
+// WastegateControl represents a command that checks the position of the
+// wastegates of the turbochargers, engines with two turbochargers report both
+// TurboA and TurboB.
+//
+// Min: 0.0
+// Max: 1.0
+type WastegateControl struct {
+	baseCommand
+	TurboA WastegatePosition
+	TurboB WastegatePosition
+}
+
+// NewWastegateControl creates a new WastegateControl with the right
+// parameters.
+func NewWastegateControl() *WastegateControl {
+	return &WastegateControl{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x72, 5, "wastegate_control"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *WastegateControl) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *WastegateControl) value() interface{} {
+	return struct {
+		TurboA WastegatePosition `json:"turbo_a"`
+		TurboB WastegatePosition `json:"turbo_b"`
+	}{cmd.TurboA, cmd.TurboB}
+}
+
+// SetValue processes the byte array value into the wastegate positions of
+// each turbocharger.
+func (cmd *WastegateControl) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 5)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.TurboA = WastegatePosition{
+		Commanded: newSensorValue(supported, 0, float64(payload[0])/255),
+		Actual:    newSensorValue(supported, 1, float64(payload[1])/255),
+	}
+	cmd.TurboB = WastegatePosition{
+		Commanded: newSensorValue(supported, 2, float64(payload[2])/255),
+		Actual:    newSensorValue(supported, 3, float64(payload[3])/255),
+	}
+
+	return nil
+}
+
+// ExhaustPressure represents a command that checks the exhaust pressure of
+// each bank in kPa.
+//
+// Min: 0
+// Max: 655.35
+type ExhaustPressure struct {
+	baseCommand
+	Bank1 SensorValue
+	Bank2 SensorValue
+}
+
+// NewExhaustPressure creates a new ExhaustPressure with the right parameters.
+func NewExhaustPressure() *ExhaustPressure {
+	return &ExhaustPressure{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x73, 5, "exhaust_pressure"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *ExhaustPressure) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *ExhaustPressure) value() interface{} {
+	return struct {
+		Bank1 SensorValue `json:"bank_1"`
+		Bank2 SensorValue `json:"bank_2"`
+	}{cmd.Bank1, cmd.Bank2}
+}
+
+// SetValue processes the byte array value into the exhaust pressure of each
+// bank.
+func (cmd *ExhaustPressure) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 5)
+
+	if err != nil {
+		return err
+	}
+
+	pressure := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1])) / 100
+	}
+
+	cmd.Bank1 = newSensorValue(supported, 0, pressure(0))
+	cmd.Bank2 = newSensorValue(supported, 1, pressure(2))
+
+	return nil
+}
+
+// TurbochargerRPM represents a command that checks the shaft speed of each
+// turbocharger in RPM.
+//
+// Min: 0
+// Max: 655350
+type TurbochargerRPM struct {
+	baseCommand
+	TurboA SensorValue
+	TurboB SensorValue
+}
+
+// NewTurbochargerRPM creates a new TurbochargerRPM with the right parameters.
+func NewTurbochargerRPM() *TurbochargerRPM {
+	return &TurbochargerRPM{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x74, 5, "turbocharger_rpm"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *TurbochargerRPM) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *TurbochargerRPM) value() interface{} {
+	return struct {
+		TurboA SensorValue `json:"turbo_a"`
+		TurboB SensorValue `json:"turbo_b"`
+	}{cmd.TurboA, cmd.TurboB}
+}
+
+// SetValue processes the byte array value into the shaft speed of each
+// turbocharger.
+func (cmd *TurbochargerRPM) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 5)
+
+	if err != nil {
+		return err
+	}
+
+	rpm := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1])) * 10
+	}
+
+	cmd.TurboA = newSensorValue(supported, 0, rpm(0))
+	cmd.TurboB = newSensorValue(supported, 1, rpm(2))
+
+	return nil
+}
+
+// turbochargerTemperature is an abstract type for the temperatures of a
+// turbocharger in Celsius, both for turbocharger A and B.
+//
+// Compressor Min: -40
+// Compressor Max: 215
+// Turbine Min: -40
+// Turbine Max: 6513.5
+type turbochargerTemperature struct {
+	baseCommand
+	CompressorInlet  SensorValue
+	CompressorOutlet SensorValue
+	TurbineInlet     SensorValue
+	TurbineOutlet    SensorValue
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *turbochargerTemperature) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *turbochargerTemperature) value() interface{} {
+	return struct {
+		CompressorInlet  SensorValue `json:"compressor_inlet"`
+		CompressorOutlet SensorValue `json:"compressor_outlet"`
+		TurbineInlet     SensorValue `json:"turbine_inlet"`
+		TurbineOutlet    SensorValue `json:"turbine_outlet"`
+	}{
+		cmd.CompressorInlet,
+		cmd.CompressorOutlet,
+		cmd.TurbineInlet,
+		cmd.TurbineOutlet,
+	}
+}
+
+// SetValue processes the byte array value into the temperatures of the
+// turbocharger.
+func (cmd *turbochargerTemperature) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 7)
+
+	if err != nil {
+		return err
+	}
+
+	turbine := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1]))/10 - 40
+	}
+
+	cmd.CompressorInlet = newSensorValue(supported, 0, float64(payload[0])-40)
+	cmd.CompressorOutlet = newSensorValue(supported, 1, float64(payload[1])-40)
+	cmd.TurbineInlet = newSensorValue(supported, 2, turbine(2))
+	cmd.TurbineOutlet = newSensorValue(supported, 3, turbine(4))
+
+	return nil
+}
+
+// TurbochargerATemperature represents a command that checks the temperatures
+// of turbocharger A.
+type TurbochargerATemperature struct {
+	turbochargerTemperature
+}
+
+// NewTurbochargerATemperature creates a new TurbochargerATemperature with the
+// right parameters.
+func NewTurbochargerATemperature() *TurbochargerATemperature {
+	return &TurbochargerATemperature{
+		turbochargerTemperature{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x75, 7, "turbocharger_a_temperature"},
+		},
+	}
+}
+
+// TurbochargerBTemperature represents a command that checks the temperatures
+// of turbocharger B.
+type TurbochargerBTemperature struct {
+	turbochargerTemperature
+}
+
+// NewTurbochargerBTemperature creates a new TurbochargerBTemperature with the
+// right parameters.
+func NewTurbochargerBTemperature() *TurbochargerBTemperature {
+	return &TurbochargerBTemperature{
+		turbochargerTemperature{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x76, 7, "turbocharger_b_temperature"},
+		},
+	}
+}
+
+// ChargeAirCoolerTemperature represents a command that checks the
+// temperature of each charge air cooler (intercooler) sensor in Celsius.
+// There are up to 2 sensors per bank, the first sensor of each bank is at
+// index 0.
+//
+// Min: -40
+// Max: 215
+type ChargeAirCoolerTemperature struct {
+	baseCommand
+	Bank1 [2]SensorValue
+	Bank2 [2]SensorValue
+}
+
+// NewChargeAirCoolerTemperature creates a new ChargeAirCoolerTemperature with
+// the right parameters.
+func NewChargeAirCoolerTemperature() *ChargeAirCoolerTemperature {
+	return &ChargeAirCoolerTemperature{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x77, 5, "charge_air_cooler_temperature"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *ChargeAirCoolerTemperature) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *ChargeAirCoolerTemperature) value() interface{} {
+	return struct {
+		Bank1 [2]SensorValue `json:"bank_1"`
+		Bank2 [2]SensorValue `json:"bank_2"`
+	}{cmd.Bank1, cmd.Bank2}
+}
+
+// SetValue processes the byte array value into the temperature of each
+// sensor.
+func (cmd *ChargeAirCoolerTemperature) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 5)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range cmd.Bank1 {
+		cmd.Bank1[i] = newSensorValue(supported, uint(i), float64(payload[i])-40)
+		cmd.Bank2[i] = newSensorValue(supported, uint(i+2), float64(payload[i+2])-40)
+	}
+
+	return nil
+}
+
+// exhaustGasTemperature is an abstract type for the exhaust gas temperature
+// (EGT) sensors of a bank in Celsius, both for bank 1 and 2. There are up to
+// 4 sensors per bank, the first sensor is at index 0.
+//
+// Min: -40
+// Max: 6513.5
+type exhaustGasTemperature struct {
+	baseCommand
+	Sensors [4]SensorValue
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *exhaustGasTemperature) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *exhaustGasTemperature) value() interface{} {
+	return struct {
+		Sensors [4]SensorValue `json:"sensors"`
+	}{cmd.Sensors}
+}
+
+// SetValue processes the byte array value into the temperature of each
+// sensor.
+func (cmd *exhaustGasTemperature) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 9)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range cmd.Sensors {
+		raw := uint16(payload[i*2])<<8 | uint16(payload[i*2+1])
+
+		cmd.Sensors[i] = newSensorValue(supported, uint(i), float64(raw)/10-40)
+	}
+
+	return nil
+}
+
+// ExhaustGasTemperatureBank1 represents a command that checks the exhaust
+// gas temperature sensors of bank 1.
+type ExhaustGasTemperatureBank1 struct {
+	exhaustGasTemperature
+}
+
+// NewExhaustGasTemperatureBank1 creates a new ExhaustGasTemperatureBank1 with
+// the right parameters.
+func NewExhaustGasTemperatureBank1() *ExhaustGasTemperatureBank1 {
+	return &ExhaustGasTemperatureBank1{
+		exhaustGasTemperature{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x78, 9, "exhaust_gas_temperature_bank1"},
+		},
+	}
+}
+
+// ExhaustGasTemperatureBank2 represents a command that checks the exhaust
+// gas temperature sensors of bank 2.
+type ExhaustGasTemperatureBank2 struct {
+	exhaustGasTemperature
+}
+
+// NewExhaustGasTemperatureBank2 creates a new ExhaustGasTemperatureBank2 with
+// the right parameters.
+func NewExhaustGasTemperatureBank2() *ExhaustGasTemperatureBank2 {
+	return &ExhaustGasTemperatureBank2{
+		exhaustGasTemperature{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x79, 9, "exhaust_gas_temperature_bank2"},
+		},
+	}
+}
+
+// particulateFilterPressure is an abstract type for the pressures of a
+// diesel particulate filter (DPF) in kPa, both for bank 1 and 2. The
+// differential pressure over the filter rises as soot builds up.
+//
+// Differential Min: -327.68
+// Differential Max: 327.67
+// Inlet/Outlet Min: 0
+// Inlet/Outlet Max: 655.35
+type particulateFilterPressure struct {
+	baseCommand
+	Differential SensorValue
+	Inlet        SensorValue
+	Outlet       SensorValue
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *particulateFilterPressure) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *particulateFilterPressure) value() interface{} {
+	return struct {
+		Differential SensorValue `json:"differential"`
+		Inlet        SensorValue `json:"inlet"`
+		Outlet       SensorValue `json:"outlet"`
+	}{cmd.Differential, cmd.Inlet, cmd.Outlet}
+}
+
+// SetValue processes the byte array value into the pressures of the filter.
+func (cmd *particulateFilterPressure) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 7)
+
+	if err != nil {
+		return err
+	}
+
+	raw := func(i int) uint16 {
+		return uint16(payload[i])<<8 | uint16(payload[i+1])
+	}
+
+	cmd.Differential = newSensorValue(supported, 0, float64(int16(raw(0)))/100)
+	cmd.Inlet = newSensorValue(supported, 1, float64(raw(2))/100)
+	cmd.Outlet = newSensorValue(supported, 2, float64(raw(4))/100)
+
+	return nil
+}
+
+// ParticulateFilterPressureBank1 represents a command that checks the
+// pressures of the diesel particulate filter of bank 1.
+type ParticulateFilterPressureBank1 struct {
+	particulateFilterPressure
+}
+
+// NewParticulateFilterPressureBank1 creates a new
+// ParticulateFilterPressureBank1 with the right parameters.
+func NewParticulateFilterPressureBank1() *ParticulateFilterPressureBank1 {
+	return &ParticulateFilterPressureBank1{
+		particulateFilterPressure{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x7A, 7, "particulate_filter_pressure_bank1"},
+		},
+	}
+}
+
+// ParticulateFilterPressureBank2 represents a command that checks the
+// pressures of the diesel particulate filter of bank 2.
+type ParticulateFilterPressureBank2 struct {
+	particulateFilterPressure
+}
+
+// NewParticulateFilterPressureBank2 creates a new
+// ParticulateFilterPressureBank2 with the right parameters.
+func NewParticulateFilterPressureBank2() *ParticulateFilterPressureBank2 {
+	return &ParticulateFilterPressureBank2{
+		particulateFilterPressure{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x7B, 7, "particulate_filter_pressure_bank2"},
+		},
+	}
+}
+
+// ParticulateFilterTemperatures represents the inlet and outlet temperature
+// of one diesel particulate filter in Celsius, see
+// ParticulateFilterTemperature.
+type ParticulateFilterTemperatures struct {
+	Inlet  SensorValue `json:"inlet"`
+	Outlet SensorValue `json:"outlet"`
+}
+
+// ParticulateFilterTemperature represents a command that checks the
+// temperatures of the diesel particulate filters (DPF) of each bank, used to
+// follow the regeneration of the filters.
+//
+// Min: -40
+// Max: 6513.5
+type ParticulateFilterTemperature struct {
+	baseCommand
+	Bank1 ParticulateFilterTemperatures
+	Bank2 ParticulateFilterTemperatures
+}
+
+// NewParticulateFilterTemperature creates a new ParticulateFilterTemperature
+// with the right parameters.
+func NewParticulateFilterTemperature() *ParticulateFilterTemperature {
+	return &ParticulateFilterTemperature{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x7C, 9, "particulate_filter_temperature"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *ParticulateFilterTemperature) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *ParticulateFilterTemperature) value() interface{} {
+	return struct {
+		Bank1 ParticulateFilterTemperatures `json:"bank_1"`
+		Bank2 ParticulateFilterTemperatures `json:"bank_2"`
+	}{cmd.Bank1, cmd.Bank2}
+}
+
+// SetValue processes the byte array value into the temperatures of the
+// filter of each bank.
+func (cmd *ParticulateFilterTemperature) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 9)
+
+	if err != nil {
+		return err
+	}
+
+	temperature := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1]))/10 - 40
+	}
+
+	cmd.Bank1 = ParticulateFilterTemperatures{
+		Inlet:  newSensorValue(supported, 0, temperature(0)),
+		Outlet: newSensorValue(supported, 1, temperature(2)),
+	}
+	cmd.Bank2 = ParticulateFilterTemperatures{
+		Inlet:  newSensorValue(supported, 2, temperature(4)),
+		Outlet: newSensorValue(supported, 3, temperature(6)),
+	}
+
+	return nil
+}
+
+// NOxSensor represents a command that checks the concentration of nitrogen
+// oxides (NOx) measured by each NOx sensor in ppm. There are up to 2 sensors
+// per bank, the first sensor of each bank is at index 0.
+//
+// Min: 0
+// Max: 65535
+type NOxSensor struct {
+	baseCommand
+	Bank1 [2]SensorValue
+	Bank2 [2]SensorValue
+}
+
+// NewNOxSensor creates a new NOxSensor with the right parameters.
+func NewNOxSensor() *NOxSensor {
+	return &NOxSensor{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x83, 9, "nox_sensor"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *NOxSensor) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *NOxSensor) value() interface{} {
+	return struct {
+		Bank1 [2]SensorValue `json:"bank_1"`
+		Bank2 [2]SensorValue `json:"bank_2"`
+	}{cmd.Bank1, cmd.Bank2}
+}
+
+// SetValue processes the byte array value into the concentration measured
+// by each sensor.
+func (cmd *NOxSensor) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 9)
+
+	if err != nil {
+		return err
+	}
+
+	concentration := func(i int) float64 {
+		return float64(uint16(payload[i*2])<<8 | uint16(payload[i*2+1]))
+	}
+
+	for i := range cmd.Bank1 {
+		cmd.Bank1[i] = newSensorValue(supported, uint(i), concentration(i))
+		cmd.Bank2[i] = newSensorValue(supported, uint(i+2), concentration(i+2))
+	}
+
+	return nil
+}
+
+// ParticulateMatterSensor represents a command that checks the mass
+// concentration of particulate matter (PM) measured by the sensor of each
+// bank in mg/m³.
+//
+// Min: 0
+// Max: 819.1875
+type ParticulateMatterSensor struct {
+	baseCommand
+	Bank1 SensorValue
+	Bank2 SensorValue
+}
+
+// NewParticulateMatterSensor creates a new ParticulateMatterSensor with the
+// right parameters.
+func NewParticulateMatterSensor() *ParticulateMatterSensor {
+	return &ParticulateMatterSensor{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x86, 5, "particulate_matter_sensor"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *ParticulateMatterSensor) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *ParticulateMatterSensor) value() interface{} {
+	return struct {
+		Bank1 SensorValue `json:"bank_1"`
+		Bank2 SensorValue `json:"bank_2"`
+	}{cmd.Bank1, cmd.Bank2}
+}
+
+// SetValue processes the byte array value into the concentration measured
+// by the sensor of each bank.
+func (cmd *ParticulateMatterSensor) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 5)
+
+	if err != nil {
+		return err
+	}
+
+	concentration := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1])) / 80
+	}
+
+	cmd.Bank1 = newSensorValue(supported, 0, concentration(0))
+	cmd.Bank2 = newSensorValue(supported, 1, concentration(2))
+
+	return nil
+}
+
+// NOxReagentSystem represents a command that checks the NOx reagent system,
+// which doses diesel exhaust fluid (DEF, also known as AdBlue) into the
+// selective catalytic reduction (SCR) system:
+//
+// - AverageConsumption is the average reagent consumption in L/h
+// - AverageDemandedConsumption is the average demanded reagent consumption
+//   in L/h
+// - TankLevel is the level of the reagent tank in percentage
+// - WarningTime is the time the NOx warning indicator has been on in seconds
+type NOxReagentSystem struct {
+	baseCommand
+	AverageConsumption         SensorValue
+	AverageDemandedConsumption SensorValue
+	TankLevel                  SensorValue
+	WarningTime                SensorValue
+}
+
+// NewNOxReagentSystem creates a new NOxReagentSystem with the right
+// parameters.
+func NewNOxReagentSystem() *NOxReagentSystem {
+	return &NOxReagentSystem{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x85, 10, "nox_reagent_system"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *NOxReagentSystem) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *NOxReagentSystem) value() interface{} {
+	return struct {
+		AverageConsumption         SensorValue `json:"average_consumption"`
+		AverageDemandedConsumption SensorValue `json:"average_demanded_consumption"`
+		TankLevel                  SensorValue `json:"tank_level"`
+		WarningTime                SensorValue `json:"warning_time"`
+	}{
+		cmd.AverageConsumption,
+		cmd.AverageDemandedConsumption,
+		cmd.TankLevel,
+		cmd.WarningTime,
+	}
+}
+
+// SetValue processes the byte array value into the values of the reagent
+// system.
+func (cmd *NOxReagentSystem) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 10)
+
+	if err != nil {
+		return err
+	}
+
+	consumption := func(i int) float64 {
+		return float64(uint16(payload[i])<<8|uint16(payload[i+1])) * 0.005
+	}
+
+	warningTime := uint32(payload[5])<<24 | uint32(payload[6])<<16 |
+		uint32(payload[7])<<8 | uint32(payload[8])
+
+	cmd.AverageConsumption = newSensorValue(supported, 0, consumption(0))
+	cmd.AverageDemandedConsumption = newSensorValue(supported, 1, consumption(2))
+	cmd.TankLevel = newSensorValue(supported, 2, float64(payload[4])/255)
+	cmd.WarningTime = newSensorValue(supported, 3, float64(warningTime))
+
+	return nil
+}
+
+// SCRInducementSystem represents a command that checks the inducement system
+// of the selective catalytic reduction (SCR) system, which limits the
+// performance of the engine when the NOx emissions are not kept in check.
+//
+// The reasons tell why the inducement system is active, the distances are
+// the distances in km travelled while the inducement system was active for
+// each reason. CurrentBlockDistance is the distance travelled in the current
+// block of 10 000 km and Blocks is the amount of such blocks.
+type SCRInducementSystem struct {
+	baseCommand
+	Active                      bool
+	ReagentLevelTooLow          bool
+	IncorrectReagent            bool
+	ReagentConsumptionDeviation bool
+	NOxEmissionsTooHigh         bool
+	ReagentLevelDistance        uint16
+	IncorrectReagentDistance    uint16
+	ReagentConsumptionDistance  uint16
+	NOxEmissionsDistance        uint16
+	CurrentBlockDistance        uint16
+	Blocks                      uint16
+}
+
+// NewSCRInducementSystem creates a new SCRInducementSystem with the right
+// parameters.
+func NewSCRInducementSystem() *SCRInducementSystem {
+	return &SCRInducementSystem{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x88, 13, "scr_inducement_system"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *SCRInducementSystem) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *SCRInducementSystem) value() interface{} {
+	return struct {
+		Active                      bool   `json:"active"`
+		ReagentLevelTooLow          bool   `json:"reagent_level_too_low"`
+		IncorrectReagent            bool   `json:"incorrect_reagent"`
+		ReagentConsumptionDeviation bool   `json:"reagent_consumption_deviation"`
+		NOxEmissionsTooHigh         bool   `json:"nox_emissions_too_high"`
+		ReagentLevelDistance        uint16 `json:"reagent_level_distance"`
+		IncorrectReagentDistance    uint16 `json:"incorrect_reagent_distance"`
+		ReagentConsumptionDistance  uint16 `json:"reagent_consumption_distance"`
+		NOxEmissionsDistance        uint16 `json:"nox_emissions_distance"`
+		CurrentBlockDistance        uint16 `json:"current_block_distance"`
+		Blocks                      uint16 `json:"blocks"`
+	}{
+		cmd.Active,
+		cmd.ReagentLevelTooLow,
+		cmd.IncorrectReagent,
+		cmd.ReagentConsumptionDeviation,
+		cmd.NOxEmissionsTooHigh,
+		cmd.ReagentLevelDistance,
+		cmd.IncorrectReagentDistance,
+		cmd.ReagentConsumptionDistance,
+		cmd.NOxEmissionsDistance,
+		cmd.CurrentBlockDistance,
+		cmd.Blocks,
+	}
+}
+
+// SetValue processes the byte array value into the status and distances of
+// the inducement system.
+func (cmd *SCRInducementSystem) SetValue(result *Result) error {
+	status, payload, err := multiSensorPayload(result, 13)
+
+	if err != nil {
+		return err
+	}
+
+	distance := func(i int) uint16 {
+		return uint16(payload[i*2])<<8 | uint16(payload[i*2+1])
+	}
+
+	cmd.Active = status&0x80 != 0
+	cmd.ReagentLevelTooLow = status&0x01 != 0
+	cmd.IncorrectReagent = status&0x02 != 0
+	cmd.ReagentConsumptionDeviation = status&0x04 != 0
+	cmd.NOxEmissionsTooHigh = status&0x08 != 0
+	cmd.ReagentLevelDistance = distance(0)
+	cmd.IncorrectReagentDistance = distance(1)
+	cmd.ReagentConsumptionDistance = distance(2)
+	cmd.NOxEmissionsDistance = distance(3)
+	cmd.CurrentBlockDistance = distance(4)
+	cmd.Blocks = distance(5)
+
+	return nil
+}
+
+// DieselExhaustFluidSensor represents a command that checks the diesel
+// exhaust fluid (DEF) sensor data:
+//
+// - Concentration is the urea concentration of the fluid in percentage
+// - Temperature is the temperature of the fluid in the tank in Celsius
+// - Level is the level of the tank in percentage
+//
+// Type is the raw first byte of the payload, telling the type of sensor.
+type DieselExhaustFluidSensor struct {
+	baseCommand
+	Type          byte
+	Concentration float64
+	Temperature   int
+	Level         float64
+}
+
+// NewDieselExhaustFluidSensor creates a new DieselExhaustFluidSensor with the
+// right parameters.
+func NewDieselExhaustFluidSensor() *DieselExhaustFluidSensor {
+	return &DieselExhaustFluidSensor{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x9B, 4, "diesel_exhaust_fluid_sensor"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *DieselExhaustFluidSensor) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *DieselExhaustFluidSensor) value() interface{} {
+	return struct {
+		Type          byte    `json:"type"`
+		Concentration float64 `json:"concentration"`
+		Temperature   int     `json:"temperature"`
+		Level         float64 `json:"level"`
+	}{cmd.Type, cmd.Concentration, cmd.Temperature, cmd.Level}
+}
+
+// SetValue processes the byte array value into the values of the sensor.
+func (cmd *DieselExhaustFluidSensor) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt32()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Type = byte(payload >> 24)
+	cmd.Concentration = float64(byte(payload>>16)) * 0.25 / 100
+	cmd.Temperature = int(byte(payload>>8)) - 40
+	cmd.Level = float64(byte(payload)) / 255
+
+	return nil
+}
+
+// EngineRunTime represents a command that checks the run time of the engine
+// in seconds, in total, while idling and while the power take-off (PTO) was
+// active.
+type EngineRunTime struct {
+	baseCommand
+	Total SensorValue
+	Idle  SensorValue
+	PTO   SensorValue
+}
+
+// NewEngineRunTime creates a new EngineRunTime with the right parameters.
+func NewEngineRunTime() *EngineRunTime {
+	return &EngineRunTime{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x7F, 13, "engine_run_time"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *EngineRunTime) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *EngineRunTime) value() interface{} {
+	return struct {
+		Total SensorValue `json:"total"`
+		Idle  SensorValue `json:"idle"`
+		PTO   SensorValue `json:"pto"`
+	}{cmd.Total, cmd.Idle, cmd.PTO}
+}
+
+// SetValue processes the byte array value into the run times.
+func (cmd *EngineRunTime) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 13)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Total = newSensorValue(supported, 0, float64(runTimePayload(payload[0:4])))
+	cmd.Idle = newSensorValue(supported, 1, float64(runTimePayload(payload[4:8])))
+	cmd.PTO = newSensorValue(supported, 2, float64(runTimePayload(payload[8:12])))
+
+	return nil
+}
+
+// runTimePayload decodes the 4 byte run time counters in seconds of the
+// engine run time commands.
+func runTimePayload(payload []byte) uint32 {
+	return uint32(payload[0])<<24 | uint32(payload[1])<<16 |
+		uint32(payload[2])<<8 | uint32(payload[3])
+}
+
+// AECDRunTime represents the run time of an auxiliary emission control
+// device (AECD) in seconds. Timer1 counts the time the device was active
+// while it increased the emissions up to the emission limit, and Timer2 the
+// time it was active while it increased the emissions above the limit.
+type AECDRunTime struct {
+	Timer1 SensorValue `json:"timer_1"`
+	Timer2 SensorValue `json:"timer_2"`
+}
+
+// engineRunTimeAECD is an abstract type for the run times of the auxiliary
+// emission control devices, for 5 devices at a time.
+type engineRunTimeAECD struct {
+	baseCommand
+	Devices [5]AECDRunTime
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *engineRunTimeAECD) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *engineRunTimeAECD) value() interface{} {
+	return struct {
+		Devices [5]AECDRunTime `json:"devices"`
+	}{cmd.Devices}
+}
+
+// SetValue processes the byte array value into the run times of each
+// device.
+func (cmd *engineRunTimeAECD) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 41)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range cmd.Devices {
+		offset := i * 8
+		timer1 := runTimePayload(payload[offset : offset+4])
+		timer2 := runTimePayload(payload[offset+4 : offset+8])
+
+		cmd.Devices[i] = AECDRunTime{
+			Timer1: newSensorValue(supported, uint(i), float64(timer1)),
+			Timer2: newSensorValue(supported, uint(i), float64(timer2)),
+		}
+	}
+
+	return nil
+}
+
+// EngineRunTimeAECD1To5 represents a command that checks the run time of
+// the auxiliary emission control devices #1 to #5, where #1 is at index 0.
+type EngineRunTimeAECD1To5 struct {
+	engineRunTimeAECD
+}
+
+// NewEngineRunTimeAECD1To5 creates a new EngineRunTimeAECD1To5 with the right
+// parameters.
+func NewEngineRunTimeAECD1To5() *EngineRunTimeAECD1To5 {
+	return &EngineRunTimeAECD1To5{
+		engineRunTimeAECD{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x81, 41, "engine_run_time_aecd_1_5"},
+		},
+	}
+}
+
+// EngineRunTimeAECD6To10 represents a command that checks the run time of
+// the auxiliary emission control devices #6 to #10, where #6 is at index 0.
+type EngineRunTimeAECD6To10 struct {
+	engineRunTimeAECD
+}
+
+// NewEngineRunTimeAECD6To10 creates a new EngineRunTimeAECD6To10 with the
+// right parameters.
+func NewEngineRunTimeAECD6To10() *EngineRunTimeAECD6To10 {
+	return &EngineRunTimeAECD6To10{
+		engineRunTimeAECD{
+			baseCommand: baseCommand{SERVICE_01_ID, 0x82, 41, "engine_run_time_aecd_6_10"},
+		},
+	}
+}
+
+// EngineFrictionTorque represents a command that checks the torque lost to
+// the friction of the engine in percent of the engine reference torque.
+//
+// Min: -125
+// Max: 130
+type EngineFrictionTorque struct {
+	percentTorque
+}
+
+// NewEngineFrictionTorque creates a new EngineFrictionTorque with the right
+// parameters.
+func NewEngineFrictionTorque() *EngineFrictionTorque {
+	return &EngineFrictionTorque{
+		percentTorque{
+			baseCommand{SERVICE_01_ID, 0x8E, 1, "engine_friction_torque"},
+			IntCommand{},
+		},
+	}
+}
+
+// CylinderFuelRate represents a command that checks the amount of fuel
+// injected into a cylinder per intake stroke in mg/stroke.
+//
+// Min: 0
+// Max: 2047.97
+type CylinderFuelRate struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewCylinderFuelRate creates a new CylinderFuelRate with the right
+// parameters.
+func NewCylinderFuelRate() *CylinderFuelRate {
+	return &CylinderFuelRate{
+		baseCommand{SERVICE_01_ID, 0xA2, 2, "cylinder_fuel_rate"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *CylinderFuelRate) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 32)
+
+	return nil
+}
+
+// HybridChargingMode represents the charging mode of the battery of a hybrid
+// or electric vehicle, see HybridSystemData.
+type HybridChargingMode byte
+
+const (
+	// HybridChargeSustaining means the state of charge of the battery is
+	// kept within a range, the usual mode of hybrids.
+	HybridChargeSustaining HybridChargingMode = 0
+	// HybridChargeDepleting means the battery is discharged to drive the
+	// vehicle, such as for plug-in hybrids driving electrically.
+	HybridChargeDepleting HybridChargingMode = 1
+	// HybridChargeIncreasing means the battery is being charged.
+	HybridChargeIncreasing HybridChargingMode = 2
+)
+
+// String returns the literal representation of the charging mode.
+func (mode HybridChargingMode) String() string {
+	switch mode {
+	case HybridChargeSustaining:
+		return "charge sustaining"
+	case HybridChargeDepleting:
+		return "charge depleting"
+	case HybridChargeIncreasing:
+		return "charge increasing"
+	}
+
+	return fmt.Sprintf("unknown (0x%02X)", byte(mode))
+}
+
+// MarshalText encodes the charging mode as its literal representation, so
+// that it is readable when exporting readings.
+func (mode HybridChargingMode) MarshalText() ([]byte, error) {
+	return []byte(mode.String()), nil
+}
+
+// HybridSystemData represents a command that checks the system data of a
+// hybrid or electric vehicle: the charging mode, the voltage of the battery
+// system in V and the current of the battery system in A, which is negative
+// while charging.
+//
+// Voltage Min: 0
+// Voltage Max: 1023.98
+// Current Min: -3276.8
+// Current Max: 3276.7
+type HybridSystemData struct {
+	baseCommand
+	ChargingModeSupported bool
+	ChargingMode          HybridChargingMode
+	Voltage               SensorValue
+	Current               SensorValue
+}
+
+// NewHybridSystemData creates a new HybridSystemData with the right
+// parameters.
+func NewHybridSystemData() *HybridSystemData {
+	return &HybridSystemData{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x9A, 6, "hybrid_system_data"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *HybridSystemData) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *HybridSystemData) value() interface{} {
+	var mode interface{}
+
+	if cmd.ChargingModeSupported {
+		mode = cmd.ChargingMode
+	}
+
+	return struct {
+		ChargingMode interface{} `json:"charging_mode"`
+		Voltage      SensorValue `json:"voltage"`
+		Current      SensorValue `json:"current"`
+	}{mode, cmd.Voltage, cmd.Current}
+}
+
+// SetValue processes the byte array value into the charging mode, voltage
+// and current.
+func (cmd *HybridSystemData) SetValue(result *Result) error {
+	supported, payload, err := multiSensorPayload(result, 6)
+
+	if err != nil {
+		return err
+	}
+
+	voltage := uint16(payload[1])<<8 | uint16(payload[2])
+	current := int16(uint16(payload[3])<<8 | uint16(payload[4]))
+
+	cmd.ChargingModeSupported = supported&0x01 != 0
+	cmd.ChargingMode = 0
+
+	if cmd.ChargingModeSupported {
+		cmd.ChargingMode = HybridChargingMode(payload[0] & 0x03)
+	}
+
+	cmd.Voltage = newSensorValue(supported, 1, float64(voltage)/64)
+	cmd.Current = newSensorValue(supported, 2, float64(current)/10)
+
+	return nil
+}
+
+// EngineFuelMassRate represents a command that checks the fuel mass rate of
+// the engine and of the whole vehicle in grams/sec. The vehicle fuel rate
+// includes fuel burned outside the engine, such as by fuel-fired heaters or
+// for regenerating the particulate filter.
+//
+// Unlike EngineFuelRate, which is in liters per hour, the rate is a mass, so
+// it does not depend on the density of the fuel.
+//
+// Min: 0
+// Max: 1310.7
+type EngineFuelMassRate struct {
+	baseCommand
+	Engine  float64
+	Vehicle float64
+}
+
+// NewEngineFuelMassRate creates a new EngineFuelMassRate with the right
+// parameters.
+func NewEngineFuelMassRate() *EngineFuelMassRate {
+	return &EngineFuelMassRate{
+		baseCommand: baseCommand{SERVICE_01_ID, 0x9D, 4, "engine_fuel_mass_rate"},
+	}
+}
+
+// ValueAsLit retrieves the value as a literal representation.
+func (cmd *EngineFuelMassRate) ValueAsLit() string {
+	lit, err := json.Marshal(cmd.value())
+
+	if err != nil {
+		return ""
+	}
+
+	return string(lit)
+}
+
+// value retrieves the value as a struct, used when exporting readings.
+func (cmd *EngineFuelMassRate) value() interface{} {
+	return struct {
+		Engine  float64 `json:"engine"`
+		Vehicle float64 `json:"vehicle"`
+	}{
+		cmd.Engine,
+		cmd.Vehicle,
+	}
+}
+
+// SetValue processes the byte array value into the fuel mass rates.
+func (cmd *EngineFuelMassRate) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt32()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.Engine = float64(payload>>16) / 50
+	cmd.Vehicle = float64(payload&0xFFFF) / 50
+
+	return nil
+}
+
+// ExhaustFlowRate represents a command that checks the mass flow rate of
+// the exhaust gas of the engine in kg/h.
+//
+// Min: 0
+// Max: 13107
+type ExhaustFlowRate struct {
+	baseCommand
+	FloatCommand
+}
+
+// NewExhaustFlowRate creates a new ExhaustFlowRate with the right
+// parameters.
+func NewExhaustFlowRate() *ExhaustFlowRate {
+	return &ExhaustFlowRate{
+		baseCommand{SERVICE_01_ID, 0x9E, 2, "exhaust_flow_rate"},
+		FloatCommand{},
+	}
+}
+
+// SetValue processes the byte array value into the right float value.
+func (cmd *ExhaustFlowRate) SetValue(result *Result) error {
+	payload, err := result.PayloadAsUInt16()
+
+	if err != nil {
+		return err
+	}
+
+	cmd.SetFloat64(float64(payload) / 5)
+
+	return nil
+}
@@ -18,6 +18,33 @@ func TestMonitorStatusResult(t *testing.T) {
 	assert(t, command.DtcAmount == 127, "DTCs were not 127")
 }
 
+func TestMonitorStatusReadiness(t *testing.T) {
+	command := NewMonitorStatus()
+	outputs := []string{"41 01 82 07 65 21"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MonitorStatus)
+
+	assertEqual(t, command.MilActive, true)
+	assertEqual(t, command.DtcAmount, byte(2))
+	assertEqual(t, command.CompressionIgnition, false)
+	assertEqual(t, command.Ready(), false)
+
+	incomplete := command.Incomplete()
+
+	assertEqual(t, len(incomplete), 2)
+	assertEqual(t, incomplete[0], MonitorTest{"catalyst", false, true, false})
+	assertEqual(t, incomplete[1], MonitorTest{"oxygen_sensor", false, true, false})
+
+	outputs = []string{"41 01 00 0F 48 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MonitorStatus)
+
+	assertEqual(t, command.CompressionIgnition, true)
+	assertEqual(t, command.Ready(), true)
+
+	filter, _ := command.Test("pm_filter")
+
+	assertEqual(t, filter, MonitorTest{"pm_filter", false, true, true})
+}
+
 func TestPartSupportedCommandInRange(t *testing.T) {
 	type scenario struct {
 		part        *PartSupported
@@ -46,6 +73,16 @@ func TestPartSupportedCommandInRange(t *testing.T) {
 			[]OBDParameterID{0x61, 0x70, 0x80},
 			[]OBDParameterID{0x00, 0x01, 0x60, 0x81},
 		},
+		{
+			NewPartSupported(7),
+			[]OBDParameterID{0xC1, 0xC3, 0xE0},
+			[]OBDParameterID{0x00, 0x01, 0xC0, 0xE1},
+		},
+		{
+			NewPartSupported(8),
+			[]OBDParameterID{0xE1, 0xF0, 0xFF},
+			[]OBDParameterID{0x00, 0x01, 0xE0},
+		},
 	}
 
 	for _, scen := range scenarios {
@@ -108,3 +145,881 @@ func TestService04Command(t *testing.T) {
 	command := NewClearTroubleCodes()
 	assert(t, command.ModeID() == SERVICE_04_ID, fmt.Sprintf("Service id is not %d", SERVICE_04_ID))
 }
+
+func TestTimingAdvanceHalfDegrees(t *testing.T) {
+	command := NewTimingAdvance()
+	outputs := []string{"41 0E 4F"}
+	command = assertOBDParseSuccess(t, command, outputs).(*TimingAdvance)
+
+	assertEqual(t, command.Value, float32(-24.5))
+	assertEqual(t, command.Float64(), -24.5)
+}
+
+func TestFloatCommandFloat64(t *testing.T) {
+	command := NewEngineLoad()
+	outputs := []string{"41 04 56"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EngineLoad)
+
+	assertEqual(t, command.Float64(), 86.0/255)
+	assertEqual(t, command.Value, float32(86.0/255))
+
+	command.Value = 0.5
+
+	assertEqual(t, command.Float64(), 0.5)
+}
+
+func TestCommandedSecondaryAirStatus(t *testing.T) {
+	command := NewCommandedSecondaryAirStatus()
+	outputs := []string{"41 12 02"}
+	command = assertOBDParseSuccess(t, command, outputs).(*CommandedSecondaryAirStatus)
+
+	assertEqual(t, command.Value, SecondaryAirDownstream)
+	assertEqual(t, command.ValueAsLit(), "downstream")
+	assertEqual(t, SecondaryAirStatus(0x10).String(), "unknown (0x10)")
+}
+
+func TestO2SensorsPresent(t *testing.T) {
+	command := NewO2SensorsPresent()
+	outputs := []string{"41 13 13"}
+	command = assertOBDParseSuccess(t, command, outputs).(*O2SensorsPresent)
+
+	assertEqual(t, len(command.Sensors), 2)
+	assertEqual(t, command.IsPresent(1, 1), true)
+	assertEqual(t, command.IsPresent(1, 2), true)
+	assertEqual(t, command.IsPresent(1, 3), false)
+	assertEqual(t, command.IsPresent(2, 1), true)
+	assertEqual(t, command.ValueAsLit(), `{"1":[1,2],"2":[1]}`)
+}
+
+func TestO2SensorsPresent4Banks(t *testing.T) {
+	command := NewO2SensorsPresent4Banks()
+	outputs := []string{"41 1D 93"}
+	command = assertOBDParseSuccess(t, command, outputs).(*O2SensorsPresent4Banks)
+
+	assertEqual(t, command.ValueAsLit(), `{"1":[1,2],"3":[1],"4":[2]}`)
+	assertEqual(t, command.IsPresent(2, 1), false)
+}
+
+func TestO2SensorVoltage(t *testing.T) {
+	command := NewO2SensorVoltage(2, 3)
+	outputs := []string{"41 1A 5A 84"}
+
+	assertEqual(t, command.ParameterID(), OBDParameterID(0x1A))
+	assertEqual(t, command.Key(), "o2_sensor_voltage_bank2_sensor3")
+
+	command = assertOBDParseSuccess(t, command, outputs).(*O2SensorVoltage)
+
+	assertAlmostEqual(t, command.Voltage, 0.45)
+	assertAlmostEqual(t, command.ShortTermFuelTrim, 3.125)
+	assertEqual(t, command.UsedForTrim, true)
+
+	outputs = []string{"41 1A 5A FF"}
+	command = assertOBDParseSuccess(t, command, outputs).(*O2SensorVoltage)
+
+	assertEqual(t, command.UsedForTrim, false)
+	assertEqual(t, command.ShortTermFuelTrim, 0.0)
+	assertEqual(t, command.ValueAsLit(), `{"voltage":0.45,"short_term_fuel_trim":0,"used_for_trim":false}`)
+}
+
+func TestO2SensorClamped(t *testing.T) {
+	command := NewO2SensorVoltage(3, 0)
+
+	assertEqual(t, command.ParameterID(), OBDParameterID(0x18))
+	assertEqual(t, command.Key(), "o2_sensor_voltage_bank2_sensor1")
+	assertEqual(t, command.Bank, 2)
+	assertEqual(t, command.Sensor, 1)
+
+	wideband := NewO2SensorLambdaCurrent(-1, 9)
+
+	assertEqual(t, wideband.ParameterID(), OBDParameterID(0x37))
+	assertEqual(t, wideband.Key(), "o2_sensor_lambda_current_bank1_sensor4")
+}
+
+func TestDistWithMILOn(t *testing.T) {
+	command := NewDistWithMILOn()
+	outputs := []string{"41 21 01 2C"}
+	command = assertOBDParseSuccess(t, command, outputs).(*DistWithMILOn)
+
+	assertEqual(t, command.Value, uint32(300))
+}
+
+func TestFuelRailPressure(t *testing.T) {
+	command := NewFuelRailPressure()
+	outputs := []string{"41 22 03 E8"}
+	command = assertOBDParseSuccess(t, command, outputs).(*FuelRailPressure)
+
+	assertAlmostEqual(t, command.Float64(), 79)
+}
+
+func TestFuelRailGaugePressure(t *testing.T) {
+	command := NewFuelRailGaugePressure()
+	outputs := []string{"41 23 FF FF"}
+	command = assertOBDParseSuccess(t, command, outputs).(*FuelRailGaugePressure)
+
+	assertEqual(t, command.Value, uint32(655350))
+}
+
+func TestO2SensorLambdaVoltage(t *testing.T) {
+	command := NewO2SensorLambdaVoltage(1, 2)
+	outputs := []string{"41 25 70 00 40 00"}
+
+	assertEqual(t, command.ParameterID(), OBDParameterID(0x25))
+
+	command = assertOBDParseSuccess(t, command, outputs).(*O2SensorLambdaVoltage)
+
+	assertEqual(t, command.Lambda, 0.875)
+	assertEqual(t, command.Voltage, 2.0)
+	assertEqual(t, command.ValueAsLit(), `{"lambda":0.875,"voltage":2}`)
+}
+
+func TestO2SensorLambdaCurrent(t *testing.T) {
+	command := NewO2SensorLambdaCurrent(2, 4)
+	outputs := []string{"41 3B 90 00 7F 80"}
+
+	assertEqual(t, command.ParameterID(), OBDParameterID(0x3B))
+
+	command = assertOBDParseSuccess(t, command, outputs).(*O2SensorLambdaCurrent)
+
+	assertEqual(t, command.Lambda, 1.125)
+	assertEqual(t, command.Current, -0.5)
+	assertEqual(t, command.ValueAsLit(), `{"lambda":1.125,"current":-0.5}`)
+}
+
+func TestCommandedEGR(t *testing.T) {
+	command := NewCommandedEGR()
+	outputs := []string{"41 2C 33"}
+	command = assertOBDParseSuccess(t, command, outputs).(*CommandedEGR)
+
+	assertAlmostEqual(t, command.Float64(), 0.2)
+}
+
+func TestEGRError(t *testing.T) {
+	command := NewEGRError()
+	outputs := []string{"41 2D 60"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EGRError)
+
+	assertEqual(t, command.Float64(), -25.0)
+}
+
+func TestCommandedEvaporativePurge(t *testing.T) {
+	command := NewCommandedEvaporativePurge()
+	outputs := []string{"41 2E FF"}
+	command = assertOBDParseSuccess(t, command, outputs).(*CommandedEvaporativePurge)
+
+	assertEqual(t, command.Float64(), 1.0)
+}
+
+func TestWarmUpsSinceDTCClear(t *testing.T) {
+	command := NewWarmUpsSinceDTCClear()
+	outputs := []string{"41 30 0C"}
+	command = assertOBDParseSuccess(t, command, outputs).(*WarmUpsSinceDTCClear)
+
+	assertEqual(t, command.Value, uint32(12))
+}
+
+func TestEvapVaporPressure(t *testing.T) {
+	command := NewEvapVaporPressure()
+	outputs := []string{"41 32 FF 38"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EvapVaporPressure)
+
+	assertEqual(t, command.Float64(), -50.0)
+
+	outputs = []string{"41 32 01 90"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EvapVaporPressure)
+
+	assertEqual(t, command.Float64(), 100.0)
+}
+
+func TestAbsoluteEvapVaporPressure(t *testing.T) {
+	command := NewAbsoluteEvapVaporPressure()
+	outputs := []string{"41 53 4F 1A"}
+	command = assertOBDParseSuccess(t, command, outputs).(*AbsoluteEvapVaporPressure)
+
+	assertAlmostEqual(t, command.Float64(), 101.25)
+}
+
+func TestEvapVaporPressureWide(t *testing.T) {
+	command := NewEvapVaporPressureWide()
+	outputs := []string{"41 54 80 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EvapVaporPressureWide)
+
+	assertEqual(t, command.Value, -32768)
+}
+
+func TestMonitorStatusThisDriveCycle(t *testing.T) {
+	command := NewMonitorStatusThisDriveCycle()
+	outputs := []string{"41 41 00 17 65 21"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MonitorStatusThisDriveCycle)
+
+	assertEqual(t, command.CompressionIgnition, false)
+	assertEqual(t, len(command.Tests), 10)
+
+	misfire, _ := command.Test("misfire")
+
+	assertEqual(t, misfire, MonitorTest{"misfire", true, true, false})
+
+	catalyst, _ := command.Test("catalyst")
+
+	assertEqual(t, catalyst, MonitorTest{"catalyst", false, true, false})
+
+	evap, _ := command.Test("evaporative_system")
+
+	assertEqual(t, evap, MonitorTest{"evaporative_system", false, true, true})
+
+	oxygen, _ := command.Test("oxygen_sensor")
+
+	assertEqual(t, oxygen, MonitorTest{"oxygen_sensor", false, true, false})
+
+	_, ok := command.Test("pm_filter")
+
+	assertEqual(t, ok, false)
+}
+
+func TestMonitorStatusThisDriveCycleDiesel(t *testing.T) {
+	command := NewMonitorStatusThisDriveCycle()
+	outputs := []string{"41 41 00 0F 48 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MonitorStatusThisDriveCycle)
+
+	assertEqual(t, command.CompressionIgnition, true)
+
+	filter, _ := command.Test("pm_filter")
+
+	assertEqual(t, filter, MonitorTest{"pm_filter", false, true, true})
+
+	boost, _ := command.Test("boost_pressure")
+
+	assertEqual(t, boost, MonitorTest{"boost_pressure", false, true, true})
+}
+
+func TestAbsoluteLoad(t *testing.T) {
+	command := NewAbsoluteLoad()
+	outputs := []string{"41 43 01 FE"}
+	command = assertOBDParseSuccess(t, command, outputs).(*AbsoluteLoad)
+
+	assertEqual(t, command.Float64(), 2.0)
+}
+
+func TestCommandedEquivalenceRatio(t *testing.T) {
+	command := NewCommandedEquivalenceRatio()
+	outputs := []string{"41 44 80 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*CommandedEquivalenceRatio)
+
+	assertEqual(t, command.Float64(), 1.0)
+}
+
+func TestThrottlePositions(t *testing.T) {
+	relative := NewRelativeThrottlePosition()
+	relative = assertOBDParseSuccess(t, relative, []string{"41 45 33"}).(*RelativeThrottlePosition)
+
+	assertAlmostEqual(t, relative.Float64(), 0.2)
+
+	positionB := NewAbsoluteThrottlePositionB()
+	positionB = assertOBDParseSuccess(t, positionB, []string{"41 47 FF"}).(*AbsoluteThrottlePositionB)
+
+	assertEqual(t, positionB.Float64(), 1.0)
+
+	positionC := NewAbsoluteThrottlePositionC()
+	positionC = assertOBDParseSuccess(t, positionC, []string{"41 48 00"}).(*AbsoluteThrottlePositionC)
+
+	assertEqual(t, positionC.Float64(), 0.0)
+}
+
+func TestAcceleratorPedalPositions(t *testing.T) {
+	pedalD := NewAcceleratorPedalPositionD()
+	pedalD = assertOBDParseSuccess(t, pedalD, []string{"41 49 33"}).(*AcceleratorPedalPositionD)
+
+	assertAlmostEqual(t, pedalD.Float64(), 0.2)
+
+	pedalE := NewAcceleratorPedalPositionE()
+	pedalE = assertOBDParseSuccess(t, pedalE, []string{"41 4A 66"}).(*AcceleratorPedalPositionE)
+
+	assertAlmostEqual(t, pedalE.Float64(), 0.4)
+
+	pedalF := NewAcceleratorPedalPositionF()
+
+	assertEqual(t, pedalF.ParameterID(), OBDParameterID(0x4B))
+	assertEqual(t, pedalF.Key(), "accelerator_pedal_position_f")
+}
+
+func TestCommandedThrottleActuator(t *testing.T) {
+	command := NewCommandedThrottleActuator()
+	outputs := []string{"41 4C 99"}
+	command = assertOBDParseSuccess(t, command, outputs).(*CommandedThrottleActuator)
+
+	assertAlmostEqual(t, command.Float64(), 0.6)
+}
+
+func TestTimeWithMILOn(t *testing.T) {
+	command := NewTimeWithMILOn()
+	outputs := []string{"41 4D 00 3C"}
+	command = assertOBDParseSuccess(t, command, outputs).(*TimeWithMILOn)
+
+	assertEqual(t, command.Value, uint32(60))
+}
+
+func TestTimeSinceDTCClear(t *testing.T) {
+	command := NewTimeSinceDTCClear()
+	outputs := []string{"41 4E 10 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*TimeSinceDTCClear)
+
+	assertEqual(t, command.Value, uint32(4096))
+}
+
+func TestMaximumValues(t *testing.T) {
+	command := NewMaximumValues()
+	outputs := []string{"41 4F 02 08 80 19"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MaximumValues)
+
+	assertEqual(t, command.MaxEquivalenceRatio, uint32(2))
+	assertEqual(t, command.MaxO2Voltage, uint32(8))
+	assertEqual(t, command.MaxO2Current, uint32(128))
+	assertEqual(t, command.MaxIntakeManifoldPressure, uint32(250))
+	assertEqual(
+		t,
+		command.ValueAsLit(),
+		`{"max_equivalence_ratio":2,"max_o2_voltage":8,"max_o2_current":128,"max_intake_manifold_pressure":250}`,
+	)
+}
+
+func TestMaximumMafAirFlowRate(t *testing.T) {
+	command := NewMaximumMafAirFlowRate()
+	outputs := []string{"41 50 41 00 00 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MaximumMafAirFlowRate)
+
+	assertEqual(t, command.Value, uint32(650))
+}
+
+func TestFuelType(t *testing.T) {
+	command := NewFuelType()
+	outputs := []string{"41 51 13"}
+	command = assertOBDParseSuccess(t, command, outputs).(*FuelType)
+
+	assertEqual(t, command.Value, FuelHybridDiesel)
+	assertEqual(t, command.ValueAsLit(), "hybrid diesel")
+
+	props, ok := command.Value.Properties()
+
+	assertEqual(t, ok, true)
+	assertEqual(t, props, Diesel)
+
+	_, ok = FuelCNG.Properties()
+
+	assertEqual(t, ok, false)
+	assertEqual(t, FuelBifuelDiesel.String(), "bifuel running diesel")
+	assertEqual(t, FuelKind(200).String(), "unknown (200)")
+}
+
+func TestOBDStandards(t *testing.T) {
+	command := NewOBDStandards()
+	outputs := []string{"41 1C 07"}
+	command = assertOBDParseSuccess(t, command, outputs).(*OBDStandards)
+
+	assertEqual(t, command.Value, uint32(7))
+	assertEqual(t, command.Standard(), OBDStandardEOBDAndOBDII)
+	assertEqual(t, command.Standard().String(), "EOBD and OBD-II")
+	assertEqual(t, command.Standard().IsEOBD(), true)
+	assertEqual(t, command.Standard().IsOBDII(), true)
+	assertEqual(t, OBDStandardJOBD.IsEOBD(), false)
+	assertEqual(t, OBDStandardJOBD.IsOBDII(), false)
+	assertEqual(t, OBDStandardOBDIICARB.IsOBDII(), true)
+	assertEqual(t, OBDStandardHDEOBDIV.IsEOBD(), true)
+	assertEqual(t, OBDStandard(14).String(), "reserved (14)")
+	assertEqual(t, OBDStandard(255).String(), "not available (255)")
+}
+
+func TestEthanolFuel(t *testing.T) {
+	command := NewEthanolFuel()
+	outputs := []string{"41 52 D9"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EthanolFuel)
+
+	assertAlmostEqual(t, command.Float64(), 0.85098039)
+}
+
+func TestRelativeAcceleratorPedalPosition(t *testing.T) {
+	command := NewRelativeAcceleratorPedalPosition()
+	outputs := []string{"41 5A 1A"}
+	command = assertOBDParseSuccess(t, command, outputs).(*RelativeAcceleratorPedalPosition)
+
+	assertAlmostEqual(t, command.Float64(), 26.0/255)
+}
+
+func TestHybridBatteryRemainingLife(t *testing.T) {
+	command := NewHybridBatteryRemainingLife()
+	outputs := []string{"41 5B 80"}
+	command = assertOBDParseSuccess(t, command, outputs).(*HybridBatteryRemainingLife)
+
+	assertAlmostEqual(t, command.Float64(), 128.0/255)
+}
+
+func TestFuelInjectionTiming(t *testing.T) {
+	command := NewFuelInjectionTiming()
+	outputs := []string{"41 5D 68 C0"}
+	command = assertOBDParseSuccess(t, command, outputs).(*FuelInjectionTiming)
+
+	assertEqual(t, command.Float64(), -0.5)
+}
+
+func TestEngineFuelRate(t *testing.T) {
+	command := NewEngineFuelRate()
+	outputs := []string{"41 5E 00 FF"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EngineFuelRate)
+
+	assertEqual(t, command.Float64(), 12.75)
+}
+
+func TestEngineTorque(t *testing.T) {
+	demand := NewDriverDemandTorque()
+	demand = assertOBDParseSuccess(t, demand, []string{"41 61 AF"}).(*DriverDemandTorque)
+
+	assertEqual(t, demand.Value, 50)
+
+	actual := NewActualEngineTorque()
+	actual = assertOBDParseSuccess(t, actual, []string{"41 62 A5"}).(*ActualEngineTorque)
+
+	assertEqual(t, actual.Value, 40)
+
+	reference := NewEngineReferenceTorque()
+	reference = assertOBDParseSuccess(t, reference, []string{"41 63 01 90"}).(*EngineReferenceTorque)
+
+	assertEqual(t, reference.Value, uint32(400))
+	assertEqual(t, actual.Torque(reference), 160.0)
+	assertEqual(t, demand.Torque(reference), 200.0)
+}
+
+func TestEnginePercentTorqueData(t *testing.T) {
+	command := NewEnginePercentTorqueData()
+	outputs := []string{"41 64 8C E1 DC D7 BE"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EnginePercentTorqueData)
+
+	assertEqual(t, command.Idle, 15)
+	assertEqual(t, command.Points, [4]int{100, 95, 90, 65})
+	assertEqual(t, command.ValueAsLit(), `{"idle":15,"points":[100,95,90,65]}`)
+}
+
+func TestAuxiliaryInputOutput(t *testing.T) {
+	command := NewAuxiliaryInputOutput()
+	outputs := []string{"41 65 0B 0D"}
+	command = assertOBDParseSuccess(t, command, outputs).(*AuxiliaryInputOutput)
+
+	assertEqual(t, command.PowerTakeOff, AuxiliaryStatus{true, true})
+	assertEqual(t, command.AutoTransNeutral, AuxiliaryStatus{true, false})
+	assertEqual(t, command.ManualTransNeutral, AuxiliaryStatus{false, false})
+	assertEqual(t, command.GlowPlugLamp, AuxiliaryStatus{true, true})
+}
+
+func TestMafAirFlowRateSensors(t *testing.T) {
+	command := NewMafAirFlowRateSensors()
+	outputs := []string{"41 66 01 0C 80 0F A0"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MafAirFlowRateSensors)
+
+	assertEqual(t, command.SensorA, SensorValue{true, 100})
+	assertEqual(t, command.SensorB, SensorValue{})
+
+	command = NewMafAirFlowRateSensors()
+	outputs = []string{"41 66 03 0C 80 0F A0"}
+	command = assertOBDParseSuccess(t, command, outputs).(*MafAirFlowRateSensors)
+
+	assertEqual(t, command.SensorA, SensorValue{true, 100})
+	assertEqual(t, command.SensorB, SensorValue{true, 125})
+}
+
+func TestCoolantTemperatureSensors(t *testing.T) {
+	command := NewCoolantTemperatureSensors()
+	outputs := []string{"41 67 03 5A 64"}
+	command = assertOBDParseSuccess(t, command, outputs).(*CoolantTemperatureSensors)
+
+	assertEqual(t, command.Sensor1, SensorValue{true, 50})
+	assertEqual(t, command.Sensor2, SensorValue{true, 60})
+}
+
+func TestIntakeAirTemperatureSensors(t *testing.T) {
+	command := NewIntakeAirTemperatureSensors()
+	outputs := []string{
+		"009",
+		"0: 41 68 09 50 51 52",
+		"1: 53 54 55 00 00 00 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*IntakeAirTemperatureSensors)
+
+	assertEqual(t, command.Bank1[0], SensorValue{true, 40})
+	assertEqual(t, command.Bank1[1], SensorValue{})
+	assertEqual(t, command.Bank1[2], SensorValue{})
+	assertEqual(t, command.Bank2[0], SensorValue{true, 43})
+	assertEqual(t, command.Bank2[1], SensorValue{})
+	assertEqual(t, command.Bank2[2], SensorValue{})
+}
+
+func TestFuelPressureControl(t *testing.T) {
+	command := NewFuelPressureControl()
+	outputs := []string{
+		"00D",
+		"0: 41 6D 07 27 10 26",
+		"1: AC 5A 00 00 00 00 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*FuelPressureControl)
+
+	assertEqual(t, command.RailA.Commanded, SensorValue{true, 100000})
+	assertEqual(t, command.RailA.Actual, SensorValue{true, 99000})
+	assertEqual(t, command.RailA.Temperature, SensorValue{true, 50})
+	assertEqual(t, command.RailB, FuelRailControl{})
+}
+
+func TestBoostPressureControl(t *testing.T) {
+	command := NewBoostPressureControl()
+	outputs := []string{
+		"00C",
+		"0: 41 70 3F 0C 80 0C 00",
+		"1: 0A 00 09 60 0E 00 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*BoostPressureControl)
+
+	assertEqual(t, command.TurboA.Commanded, SensorValue{true, 100})
+	assertEqual(t, command.TurboA.Actual, SensorValue{true, 96})
+	assertEqual(t, command.TurboA.Status, ControlLoopClosed)
+	assertEqual(t, command.TurboB.Commanded, SensorValue{true, 80})
+	assertEqual(t, command.TurboB.Actual, SensorValue{true, 75})
+	assertEqual(t, command.TurboB.Status, ControlLoopFault)
+	assertEqual(
+		t,
+		command.ValueAsLit(),
+		`{"turbo_a":{"commanded":{"supported":true,"value":100},"actual":{"supported":true,"value":96},"status":"closed loop"},`+
+			`"turbo_b":{"commanded":{"supported":true,"value":80},"actual":{"supported":true,"value":75},"status":"fault"}}`,
+	)
+}
+
+func TestVariableGeometryTurboControl(t *testing.T) {
+	command := NewVariableGeometryTurboControl()
+	outputs := []string{"41 71 07 FF 66 00 00 01"}
+	command = assertOBDParseSuccess(t, command, outputs).(*VariableGeometryTurboControl)
+
+	assertEqual(t, command.TurboA.Commanded, SensorValue{true, 1})
+	assertEqual(t, command.TurboA.Actual, SensorValue{true, 0.4})
+	assertEqual(t, command.TurboA.Status, ControlLoopOpen)
+	assertEqual(t, command.TurboB, VGTControl{})
+}
+
+func TestWastegateControl(t *testing.T) {
+	command := NewWastegateControl()
+	outputs := []string{"41 72 0F 33 66 00 FF"}
+	command = assertOBDParseSuccess(t, command, outputs).(*WastegateControl)
+
+	assertEqual(t, command.TurboA.Commanded, SensorValue{true, 0.2})
+	assertEqual(t, command.TurboA.Actual, SensorValue{true, 0.4})
+	assertEqual(t, command.TurboB.Commanded, SensorValue{true, 0})
+	assertEqual(t, command.TurboB.Actual, SensorValue{true, 1})
+}
+
+func TestExhaustPressure(t *testing.T) {
+	command := NewExhaustPressure()
+	outputs := []string{"41 73 01 27 10 00 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*ExhaustPressure)
+
+	assertEqual(t, command.Bank1, SensorValue{true, 100})
+	assertEqual(t, command.Bank2, SensorValue{})
+}
+
+func TestTurbochargerRPM(t *testing.T) {
+	command := NewTurbochargerRPM()
+	outputs := []string{"41 74 01 27 10 00 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*TurbochargerRPM)
+
+	assertEqual(t, command.TurboA, SensorValue{true, 100000})
+	assertEqual(t, command.TurboB, SensorValue{})
+}
+
+func TestTurbochargerTemperature(t *testing.T) {
+	outputs := []string{
+		"009",
+		"0: 41 75 0F 3C 96 1C",
+		"1: 20 18 38 00 00 00 00",
+	}
+	commandA := assertOBDParseSuccess(t, NewTurbochargerATemperature(), outputs).(*TurbochargerATemperature)
+
+	assertEqual(t, commandA.CompressorInlet, SensorValue{true, 20})
+	assertEqual(t, commandA.CompressorOutlet, SensorValue{true, 110})
+	assertEqual(t, commandA.TurbineInlet, SensorValue{true, 680})
+	assertEqual(t, commandA.TurbineOutlet, SensorValue{true, 580})
+
+	outputs = []string{
+		"009",
+		"0: 41 76 01 3C 96 1C",
+		"1: 20 18 38 00 00 00 00",
+	}
+	commandB := assertOBDParseSuccess(t, NewTurbochargerBTemperature(), outputs).(*TurbochargerBTemperature)
+
+	assertEqual(t, commandB.CompressorInlet, SensorValue{true, 20})
+	assertEqual(t, commandB.CompressorOutlet, SensorValue{})
+	assertEqual(t, commandB.TurbineInlet, SensorValue{})
+	assertEqual(t, commandB.TurbineOutlet, SensorValue{})
+}
+
+func TestChargeAirCoolerTemperature(t *testing.T) {
+	command := NewChargeAirCoolerTemperature()
+	outputs := []string{"41 77 05 50 00 5A 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*ChargeAirCoolerTemperature)
+
+	assertEqual(t, command.Bank1[0], SensorValue{true, 40})
+	assertEqual(t, command.Bank1[1], SensorValue{})
+	assertEqual(t, command.Bank2[0], SensorValue{true, 50})
+	assertEqual(t, command.Bank2[1], SensorValue{})
+}
+
+func TestExhaustGasTemperature(t *testing.T) {
+	outputs := []string{
+		"00B",
+		"0: 41 78 0B 1C 20 18",
+		"1: 38 00 00 0D AC 00 00",
+	}
+	command1 := assertOBDParseSuccess(t, NewExhaustGasTemperatureBank1(), outputs).(*ExhaustGasTemperatureBank1)
+
+	assertEqual(t, command1.Sensors[0], SensorValue{true, 680})
+	assertEqual(t, command1.Sensors[1], SensorValue{true, 580})
+	assertEqual(t, command1.Sensors[2], SensorValue{})
+	assertEqual(t, command1.Sensors[3], SensorValue{true, 310})
+
+	outputs = []string{
+		"00B",
+		"0: 41 79 01 1C 20 18",
+		"1: 38 00 00 0D AC 00 00",
+	}
+	command2 := assertOBDParseSuccess(t, NewExhaustGasTemperatureBank2(), outputs).(*ExhaustGasTemperatureBank2)
+
+	assertEqual(t, command2.Sensors[0], SensorValue{true, 680})
+	assertEqual(t, command2.Sensors[1], SensorValue{})
+}
+
+func TestParticulateFilterPressure(t *testing.T) {
+	outputs := []string{
+		"009",
+		"0: 41 7A 07 01 F4 27",
+		"1: 10 25 1C 00 00 00 00",
+	}
+	command1 := assertOBDParseSuccess(t, NewParticulateFilterPressureBank1(), outputs).(*ParticulateFilterPressureBank1)
+
+	assertEqual(t, command1.Differential, SensorValue{true, 5})
+	assertEqual(t, command1.Inlet, SensorValue{true, 100})
+	assertEqual(t, command1.Outlet, SensorValue{true, 95})
+
+	outputs = []string{
+		"009",
+		"0: 41 7B 01 FF 9C 00",
+		"1: 00 00 00 00 00 00 00",
+	}
+	command2 := assertOBDParseSuccess(t, NewParticulateFilterPressureBank2(), outputs).(*ParticulateFilterPressureBank2)
+
+	assertEqual(t, command2.Differential, SensorValue{true, -1})
+	assertEqual(t, command2.Inlet, SensorValue{})
+	assertEqual(t, command2.Outlet, SensorValue{})
+}
+
+func TestParticulateFilterTemperature(t *testing.T) {
+	command := NewParticulateFilterTemperature()
+	outputs := []string{
+		"00B",
+		"0: 41 7C 03 1C 20 18",
+		"1: 38 00 00 00 00 00 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*ParticulateFilterTemperature)
+
+	assertEqual(t, command.Bank1.Inlet, SensorValue{true, 680})
+	assertEqual(t, command.Bank1.Outlet, SensorValue{true, 580})
+	assertEqual(t, command.Bank2, ParticulateFilterTemperatures{})
+}
+
+func TestNOxSensor(t *testing.T) {
+	command := NewNOxSensor()
+	outputs := []string{
+		"00B",
+		"0: 41 83 05 00 96 00",
+		"1: 00 00 1E 00 00 00 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*NOxSensor)
+
+	assertEqual(t, command.Bank1[0], SensorValue{true, 150})
+	assertEqual(t, command.Bank1[1], SensorValue{})
+	assertEqual(t, command.Bank2[0], SensorValue{true, 30})
+	assertEqual(t, command.Bank2[1], SensorValue{})
+}
+
+func TestParticulateMatterSensor(t *testing.T) {
+	command := NewParticulateMatterSensor()
+	outputs := []string{"41 86 01 00 28 00 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*ParticulateMatterSensor)
+
+	assertEqual(t, command.Bank1, SensorValue{true, 0.5})
+	assertEqual(t, command.Bank2, SensorValue{})
+}
+
+func TestNOxReagentSystem(t *testing.T) {
+	command := NewNOxReagentSystem()
+	outputs := []string{
+		"00C",
+		"0: 41 85 0F 00 64 00",
+		"1: C8 CC 00 00 0E 10 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*NOxReagentSystem)
+
+	assertEqual(t, command.AverageConsumption, SensorValue{true, 0.5})
+	assertEqual(t, command.AverageDemandedConsumption, SensorValue{true, 1})
+	assertEqual(t, command.TankLevel, SensorValue{true, 0.8})
+	assertEqual(t, command.WarningTime, SensorValue{true, 3600})
+}
+
+func TestSCRInducementSystem(t *testing.T) {
+	command := NewSCRInducementSystem()
+	outputs := []string{
+		"00F",
+		"0: 41 88 81 00 32 00",
+		"1: 00 00 00 00 00 13 88",
+		"2: 00 02 00 00 00 00 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*SCRInducementSystem)
+
+	assertEqual(t, command.Active, true)
+	assertEqual(t, command.ReagentLevelTooLow, true)
+	assertEqual(t, command.IncorrectReagent, false)
+	assertEqual(t, command.ReagentConsumptionDeviation, false)
+	assertEqual(t, command.NOxEmissionsTooHigh, false)
+	assertEqual(t, command.ReagentLevelDistance, uint16(50))
+	assertEqual(t, command.CurrentBlockDistance, uint16(5000))
+	assertEqual(t, command.Blocks, uint16(2))
+}
+
+func TestDieselExhaustFluidSensor(t *testing.T) {
+	command := NewDieselExhaustFluidSensor()
+	outputs := []string{"41 9B 01 80 41 CC"}
+	command = assertOBDParseSuccess(t, command, outputs).(*DieselExhaustFluidSensor)
+
+	assertEqual(t, command.Type, byte(1))
+	assertEqual(t, command.Concentration, 0.32)
+	assertEqual(t, command.Temperature, 25)
+	assertEqual(t, command.Level, 0.8)
+}
+
+func TestEngineRunTime(t *testing.T) {
+	command := NewEngineRunTime()
+	outputs := []string{
+		"00F",
+		"0: 41 7F 07 00 00 0E",
+		"1: 10 00 00 03 84 00 00",
+		"2: 00 3C 00 00 00 00 00",
+	}
+	command = assertOBDParseSuccess(t, command, outputs).(*EngineRunTime)
+
+	assertEqual(t, command.Total, SensorValue{true, 3600})
+	assertEqual(t, command.Idle, SensorValue{true, 900})
+	assertEqual(t, command.PTO, SensorValue{true, 60})
+}
+
+func TestEngineRunTimeAECD(t *testing.T) {
+	outputs := []string{
+		"02B",
+		"0: 41 81 03 00 00 00",
+		"1: 0A 00 00 00 02 00 00",
+		"2: 01 2C 00 00 00 00 00",
+		"3: 00 00 00 00 00 00 00",
+		"4: 00 00 00 00 00 00 00",
+		"5: 00 00 00 00 00 00 00",
+		"6: 00 00 00 00 00 00 00",
+	}
+	command1 := assertOBDParseSuccess(t, NewEngineRunTimeAECD1To5(), outputs).(*EngineRunTimeAECD1To5)
+
+	assertEqual(t, command1.Devices[0], AECDRunTime{SensorValue{true, 10}, SensorValue{true, 2}})
+	assertEqual(t, command1.Devices[1], AECDRunTime{SensorValue{true, 300}, SensorValue{true, 0}})
+	assertEqual(t, command1.Devices[2], AECDRunTime{})
+
+	outputs = []string{
+		"02B",
+		"0: 41 82 00 00 00 00",
+		"1: 00 00 00 00 00 00 00",
+		"2: 00 00 00 00 00 00 00",
+		"3: 00 00 00 00 00 00 00",
+		"4: 00 00 00 00 00 00 00",
+		"5: 00 00 00 00 00 00 00",
+		"6: 00 00 00 00 00 00 00",
+	}
+	command2 := assertOBDParseSuccess(t, NewEngineRunTimeAECD6To10(), outputs).(*EngineRunTimeAECD6To10)
+
+	for _, device := range command2.Devices {
+		assertEqual(t, device, AECDRunTime{})
+	}
+}
+
+func TestEngineFrictionTorque(t *testing.T) {
+	command := NewEngineFrictionTorque()
+	outputs := []string{"41 8E 8C"}
+	command = assertOBDParseSuccess(t, command, outputs).(*EngineFrictionTorque)
+
+	assertEqual(t, command.Value, 15)
+}
+
+func TestCylinderFuelRate(t *testing.T) {
+	command := NewCylinderFuelRate()
+	outputs := []string{"41 A2 02 D0"}
+	command = assertOBDParseSuccess(t, command, outputs).(*CylinderFuelRate)
+
+	assertEqual(t, command.Float64(), 22.5)
+}
+
+func TestHybridSystemData(t *testing.T) {
+	command := NewHybridSystemData()
+	outputs := []string{"41 9A 07 01 5A 00 FF 38"}
+	command = assertOBDParseSuccess(t, command, outputs).(*HybridSystemData)
+
+	assertEqual(t, command.ChargingModeSupported, true)
+	assertEqual(t, command.ChargingMode, HybridChargeDepleting)
+	assertEqual(t, command.Voltage, SensorValue{true, 360})
+	assertEqual(t, command.Current, SensorValue{true, -20})
+	assertEqual(
+		t,
+		command.ValueAsLit(),
+		`{"charging_mode":"charge depleting","voltage":{"supported":true,"value":360},"current":{"supported":true,"value":-20}}`,
+	)
+
+	command = NewHybridSystemData()
+	outputs = []string{"41 9A 02 00 5A 00 00 00"}
+	command = assertOBDParseSuccess(t, command, outputs).(*HybridSystemData)
+
+	assertEqual(t, command.ChargingModeSupported, false)
+	assertEqual(t, command.Current, SensorValue{})
+	assertEqual(
+		t,
+		command.ValueAsLit(),
+		`{"charging_mode":null,"voltage":{"supported":true,"value":360},"current":{"supported":false,"value":0}}`,
+	)
+}
+
+func TestEngineFuelMassRate(t *testing.T) {
+	cmd := assertOBDParseSuccess(
+		t,
+		NewEngineFuelMassRate(),
+		[]string{"41 9D 00 32 FF FF"},
+	).(*EngineFuelMassRate)
+
+	assertAlmostEqual(t, cmd.Engine, 1)
+	assertAlmostEqual(t, cmd.Vehicle, 1310.7)
+	assertEqual(t, cmd.ValueAsLit(), `{"engine":1,"vehicle":1310.7}`)
+}
+
+func TestExhaustFlowRate(t *testing.T) {
+	cmd := assertOBDParseSuccess(
+		t,
+		NewExhaustFlowRate(),
+		[]string{"41 9E 01 F4"},
+	).(*ExhaustFlowRate)
+
+	assertAlmostEqual(t, cmd.Float64(), 100)
+}
+
+func TestCommandRegistry(t *testing.T) {
+	for _, key := range GetCommandKeys() {
+		cmd, ok := NewCommandByKey(key)
+
+		assert(t, ok, "command "+key+" can be created")
+		assertEqual(t, cmd.Key(), key)
+	}
+
+	_, ok := NewCommandByKey("no_such_command")
+
+	assertEqual(t, ok, false)
+}
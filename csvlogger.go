@@ -0,0 +1,313 @@
+package elmobd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// CSVColumn represents a column that the CSVLogger can write, the value of
+// the column is used as the header of the column.
+type CSVColumn string
+
+// The columns that can be written by the CSVLogger. CSVSchema is the version
+// of the data model of the readings, see SchemaVersion.
+const (
+	CSVTimestamp CSVColumn = "timestamp"
+	CSVKey       CSVColumn = "key"
+	CSVValue     CSVColumn = "value"
+	CSVUnit      CSVColumn = "unit"
+	CSVSchema    CSVColumn = "schema"
+)
+
+// DefaultCSVColumns are the columns used when no columns are given to
+// NewCSVLogger. The schema column is left out, so that existing files keep
+// their columns.
+var DefaultCSVColumns = []CSVColumn{CSVTimestamp, CSVKey, CSVValue, CSVUnit}
+
+// CSVLogger appends processed commands as readings to a CSV file, one row per
+// command.
+//
+// When MaxSize is set, the file is rotated before a write that would make it
+// grow past MaxSize bytes. The rotated file is renamed by adding the time of
+// the rotation to the name, such as "log-20220908T120000.000.csv" for
+// "log.csv". When MaxBackups is set, only that many rotated files are kept.
+//
+// A CSVLogger is safe to use from multiple goroutines.
+type CSVLogger struct {
+	MaxSize    int64
+	MaxBackups int
+
+	mutex   sync.Mutex
+	path    string
+	columns []CSVColumn
+	file    *os.File
+	writer  *csv.Writer
+	counter *countingWriter
+	now     func() time.Time
+}
+
+// NewCSVLogger creates a new CSVLogger that appends to the file at the given
+// path, creating it if it doesn't exist. The header is written when the file
+// is empty.
+func NewCSVLogger(path string, columns ...CSVColumn) (*CSVLogger, error) {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	for _, col := range columns {
+		switch col {
+		case CSVTimestamp, CSVKey, CSVValue, CSVUnit, CSVSchema:
+		default:
+			return nil, fmt.Errorf("unknown CSV column: %q", col)
+		}
+	}
+
+	logger := &CSVLogger{
+		path:    path,
+		columns: columns,
+		now:     time.Now,
+	}
+
+	err := logger.open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return logger, nil
+}
+
+// Log appends the value of the given processed command, timestamped with the
+// current time.
+func (logger *CSVLogger) Log(cmd OBDCommand) error {
+	return logger.LogMany([]OBDCommand{cmd})
+}
+
+// LogMany appends the values of all the given processed commands, such as
+// the result of RunManyOBDCommands, using the same timestamp for all rows.
+func (logger *CSVLogger) LogMany(commands []OBDCommand) error {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.file == nil {
+		return fmt.Errorf("CSV logger is closed")
+	}
+
+	now := logger.now()
+	rows := make([][]string, 0, len(commands))
+	rowsSize := int64(0)
+
+	for _, cmd := range commands {
+		row, err := logger.formatRow(NewReading(cmd, now))
+
+		if err != nil {
+			return err
+		}
+
+		rows = append(rows, row)
+		rowsSize += int64(len(strings.Join(row, ",")) + 1)
+	}
+
+	if logger.MaxSize > 0 && logger.counter.size > 0 && logger.counter.size+rowsSize > logger.MaxSize {
+		err := logger.rotate(now)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		err := logger.writer.Write(row)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.writer.Flush()
+
+	return logger.writer.Error()
+}
+
+// Close flushes and closes the current file.
+func (logger *CSVLogger) Close() error {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.file == nil {
+		return nil
+	}
+
+	logger.writer.Flush()
+
+	err := logger.file.Close()
+
+	logger.file = nil
+
+	return err
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// countingWriter keeps track of the size of the file being written.
+type countingWriter struct {
+	writer io.Writer
+	size   int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.writer.Write(p)
+
+	cw.size += int64(n)
+
+	return n, err
+}
+
+// open opens the file at the path of the logger and writes the header if the
+// file is empty.
+func (logger *CSVLogger) open() error {
+	file, err := os.OpenFile(
+		logger.path,
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		0644,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to open CSV log: %w", err)
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("failed to stat CSV log: %w", err)
+	}
+
+	logger.file = file
+	logger.counter = &countingWriter{file, info.Size()}
+	logger.writer = csv.NewWriter(logger.counter)
+
+	if info.Size() > 0 {
+		return nil
+	}
+
+	header := make([]string, len(logger.columns))
+
+	for i, col := range logger.columns {
+		header[i] = string(col)
+	}
+
+	logger.writer.Write(header)
+	logger.writer.Flush()
+
+	return logger.writer.Error()
+}
+
+// rotate closes the current file, renames it and opens a new file.
+func (logger *CSVLogger) rotate(now time.Time) error {
+	logger.writer.Flush()
+
+	err := logger.file.Close()
+
+	logger.file = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to close CSV log: %w", err)
+	}
+
+	ext := filepath.Ext(logger.path)
+	base := strings.TrimSuffix(logger.path, ext)
+	rotated := fmt.Sprintf("%s-%s%s", base, now.Format("20060102T150405.000"), ext)
+
+	err = os.Rename(logger.path, rotated)
+
+	if err != nil {
+		return fmt.Errorf("failed to rotate CSV log: %w", err)
+	}
+
+	if logger.MaxBackups > 0 {
+		logger.removeOldBackups(base, ext)
+	}
+
+	return logger.open()
+}
+
+// removeOldBackups removes the oldest rotated files, so that only MaxBackups
+// rotated files are left. The time in the name of the files makes the oldest
+// files sort first.
+func (logger *CSVLogger) removeOldBackups(base string, ext string) {
+	backups, err := filepath.Glob(base + "-*" + ext)
+
+	if err != nil || len(backups) <= logger.MaxBackups {
+		return
+	}
+
+	sort.Strings(backups)
+
+	for _, backup := range backups[:len(backups)-logger.MaxBackups] {
+		os.Remove(backup)
+	}
+}
+
+// formatRow formats the reading as the configured columns.
+func (logger *CSVLogger) formatRow(reading Reading) ([]string, error) {
+	row := make([]string, len(logger.columns))
+
+	for i, col := range logger.columns {
+		switch col {
+		case CSVTimestamp:
+			row[i] = reading.Time.Format(time.RFC3339Nano)
+		case CSVKey:
+			row[i] = reading.Key
+		case CSVValue:
+			value, err := formatCSVValue(reading.Value)
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to format value of %s: %w", reading.Key, err)
+			}
+
+			row[i] = value
+		case CSVUnit:
+			row[i] = reading.Unit
+		case CSVSchema:
+			row[i] = fmt.Sprint(reading.Schema)
+		}
+	}
+
+	return row, nil
+}
+
+// formatCSVValue formats the given value of a reading as a cell. Values that
+// are not numbers or strings, such as the struct value of O2SensorVoltage,
+// are encoded as JSON, and commands without a result get an empty cell.
+func formatCSVValue(value interface{}) (string, error) {
+	switch val := value.(type) {
+	case nil:
+		return "", nil
+	case float32, float64, int, int64, uint32, string, bool:
+		return fmt.Sprint(val), nil
+	}
+
+	lit, err := json.Marshal(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(lit), nil
+}
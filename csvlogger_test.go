@@ -0,0 +1,118 @@
+package elmobd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestCSVLoggerColumns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elmobd")
+	assertSuccess(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log.csv")
+
+	logger, err := NewCSVLogger(path, CSVKey, CSVValue)
+	assertSuccess(t, err)
+
+	speed := NewVehicleSpeed()
+	speed.Value = 80
+
+	assertSuccess(t, logger.Log(speed))
+	assertSuccess(t, logger.Close())
+
+	// Reopening appends without writing the header again
+	logger, err = NewCSVLogger(path, CSVKey, CSVValue)
+	assertSuccess(t, err)
+
+	voltage := NewControlModuleVoltage()
+	voltage.Value = 13.5
+
+	assertSuccess(t, logger.Log(voltage))
+	assertSuccess(t, logger.Close())
+
+	content, err := ioutil.ReadFile(path)
+	assertSuccess(t, err)
+
+	assertEqual(t, string(content), "key,value\nvehicle_speed,80\ncontrol_module_voltage,13.5\n")
+
+	_, err = NewCSVLogger(path, CSVColumn("nope"))
+	assert(t, err != nil, "unknown column fails")
+}
+
+func TestCSVLoggerNonScalarValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elmobd")
+	assertSuccess(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log.csv")
+
+	logger, err := NewCSVLogger(path, CSVKey, CSVValue)
+	assertSuccess(t, err)
+
+	o2Sensor := NewO2SensorVoltage(1, 1)
+	o2Sensor.Voltage = 0.45
+	o2Sensor.UsedForTrim = true
+
+	assertSuccess(t, logger.LogMany([]OBDCommand{o2Sensor, NewClearTroubleCodes()}))
+	assertSuccess(t, logger.Close())
+
+	content, err := ioutil.ReadFile(path)
+	assertSuccess(t, err)
+
+	assertEqual(
+		t,
+		string(content),
+		"key,value\n"+
+			`o2_sensor_voltage_bank1_sensor1,"{""voltage"":0.45,""short_term_fuel_trim"":0,""used_for_trim"":true}"`+"\n"+
+			"clear_trouble_codes,\n",
+	)
+}
+
+func TestCSVLoggerRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elmobd")
+	assertSuccess(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log.csv")
+
+	logger, err := NewCSVLogger(path)
+	assertSuccess(t, err)
+	defer logger.Close()
+
+	logger.MaxSize = 100
+	logger.MaxBackups = 2
+
+	at := time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC)
+
+	logger.now = func() time.Time {
+		at = at.Add(time.Second)
+
+		return at
+	}
+
+	rpm := NewEngineRPM()
+
+	for i := 0; i < 10; i++ {
+		assertSuccess(t, logger.Log(rpm))
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "log-*.csv"))
+	assertSuccess(t, err)
+
+	assertEqual(t, len(backups), 2)
+
+	for _, file := range append(backups, path) {
+		info, err := os.Stat(file)
+		assertSuccess(t, err)
+
+		assert(t, info.Size() <= 100, file+" is not larger than MaxSize")
+	}
+}
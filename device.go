@@ -1,11 +1,16 @@
 package elmobd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 /*==============================================================================
@@ -32,42 +37,37 @@ type Result struct {
 // NewResult constructors a Result by taking care of parsing the hex bytes into
 // binary representation.
 func NewResult(rawLine string) (*Result, error) {
-	literals := strings.Split(rawLine, " ")
+	result := Result{make([]byte, 0, len(rawLine)/2+1)}
 
-	if len(literals) < 3 {
-		return nil, fmt.Errorf(
-			"Expected at least 3 OBD literals: %s", rawLine,
-		)
-	}
-
-	result := Result{make([]byte, 0)}
-
-	for i := range literals {
-		curr, err := strconv.ParseUint(
-			literals[i],
-			16,
-			8,
-		)
+	err := result.parse(rawLine)
 
-		if err != nil {
-			return nil, err
-		}
-
-		result.value = append(result.value, uint8(curr))
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
 }
 
 // Validate checks that the result is for the given OBDCommand by:
-// - Comparing the bytes received and the expected amount of bytes to receive
+// - Comparing the bytes received and the expected amount of bytes to receive,
+// or the range of amounts for a VariableWidthCommand
 // - Comparing the received mode ID and the expected mode ID
 // - Comparing the received parameter ID and the expected parameter ID
 func (res *Result) Validate(cmd OBDCommand) error {
 	valueLen := len(res.value)
-	expLen := int(cmd.DataWidth() + 2)
 
-	if valueLen != expLen {
+	if variable, ok := cmd.(VariableWidthCommand); ok {
+		minWidth, maxWidth := variable.DataWidthRange()
+
+		if valueLen < minWidth+2 || valueLen > maxWidth+2 {
+			return fmt.Errorf(
+				"Expected %d to %d bytes, found %d",
+				minWidth+2,
+				maxWidth+2,
+				valueLen,
+			)
+		}
+	} else if expLen := int(cmd.DataWidth() + 2); valueLen != expLen {
 		return fmt.Errorf(
 			"Expected %d bytes, found %d",
 			expLen,
@@ -96,6 +96,104 @@ func (res *Result) Validate(cmd OBDCommand) error {
 	return nil
 }
 
+// ErrValueOutOfRange is matched by the ValueOutOfRangeError returned when a
+// processed value is outside of the range of the command, use errors.Is to
+// check for it.
+var ErrValueOutOfRange = errors.New("value out of range")
+
+// ValueOutOfRangeError represents a processed value that is outside of the
+// range of the command (see GetCommandRange and GetPlausibleRange), which
+// usually means that the ELM327 device gave a garbage response. The raw
+// payload of the response is included to make it possible to investigate what
+// was received.
+type ValueOutOfRangeError struct {
+	Key     string
+	Value   float64
+	Range   ValueRange
+	Payload []byte
+}
+
+// Error describes the out of range value.
+func (err *ValueOutOfRangeError) Error() string {
+	return fmt.Sprintf(
+		"value of %s out of range: %g not in [%g, %g] (payload % X)",
+		err.Key,
+		err.Value,
+		err.Range.Min,
+		err.Range.Max,
+		err.Payload,
+	)
+}
+
+// Is makes errors.Is match the error against ErrValueOutOfRange.
+func (err *ValueOutOfRangeError) Is(target error) bool {
+	return target == ErrValueOutOfRange
+}
+
+// ErrUnknownCommand is matched by the UnknownCommandError returned when the
+// device answers "?" to a command, use errors.Is to check for it.
+var ErrUnknownCommand = errors.New("unknown command")
+
+// UnknownCommandError represents the device answering "?" to a command, which
+// it does for AT commands it does not support (such as ATAT on some clones)
+// and for malformed commands. The command sent is included so that probing
+// can tell which command to fall back from.
+type UnknownCommandError struct {
+	Input string
+}
+
+// Error describes the unknown command.
+func (err *UnknownCommandError) Error() string {
+	return fmt.Sprintf("device does not know command %q", err.Input)
+}
+
+// Is makes errors.Is match the error against ErrUnknownCommand.
+func (err *UnknownCommandError) Is(target error) bool {
+	return target == ErrUnknownCommand
+}
+
+// ValidateRange checks that the processed value of the given command is within
+// the range of the command. Commands without a known range, or without a
+// numeric value, are always valid.
+func (res *Result) ValidateRange(cmd OBDCommand) error {
+	vr, ok := GetCommandRange(cmd)
+
+	if !ok {
+		return nil
+	}
+
+	return res.validateWithin(cmd, vr)
+}
+
+// ValidatePlausibility checks that the processed value of the given command is
+// within the range of the values a car can report (see GetPlausibleRange).
+// Commands without such a range, or without a numeric value, are always
+// valid.
+func (res *Result) ValidatePlausibility(cmd OBDCommand) error {
+	vr, ok := GetPlausibleRange(cmd)
+
+	if !ok {
+		return nil
+	}
+
+	return res.validateWithin(cmd, vr)
+}
+
+// validateWithin checks that the processed value of the given command is
+// within the given range.
+func (res *Result) validateWithin(cmd OBDCommand, vr ValueRange) error {
+	val, ok := AsReading(cmd).Float64()
+
+	if !ok || vr.Contains(val) {
+		return nil
+	}
+
+	payload := make([]byte, len(res.value)-2)
+	copy(payload, res.value[2:])
+
+	return &ValueOutOfRangeError{cmd.Key(), val, vr, payload}
+}
+
 // payloadAsUInt casts the Result as a unsigned 64-bit integer and making sure
 // it has the expected amount of bytes.
 //
@@ -125,6 +223,18 @@ func (res *Result) payloadAsUInt(expAmount int) (uint64, error) {
 	return result, nil
 }
 
+// Payload returns a copy of the payload of the result, which is the bytes
+// after the mode and parameter ID. Used by commands with a variable amount
+// of bytes (see VariableWidthCommand), which can not use the PayloadAs
+// helpers.
+func (res *Result) Payload() []byte {
+	if len(res.value) < 2 {
+		return []byte{}
+	}
+
+	return append([]byte{}, res.value[2:]...)
+}
+
 // PayloadAsUInt64 is a helper for getting payload as uint64.
 func (res *Result) PayloadAsUInt64() (uint64, error) {
 	result, err := res.payloadAsUInt(8)
@@ -191,10 +301,25 @@ type RawDevice interface {
 type Device struct {
 	rawDevice   RawDevice
 	outputDebug bool
+	stats       statsCollector
+	state       stateTracker
+	tracer      Tracer
+	async       asyncQueue
+	limiter     rateLimiter
+	quirks      quirkSet
+	inFlight    int32
+	plausible   bool
 }
 
-// NewDevice constructs a Device by initializing the serial connection and
-// setting the protocol to talk with the car to "automatic".
+// NewDevice constructs a Device by connecting to the device at the given
+// address with the transport registered for its scheme (see
+// RegisterTransport), working around the known quirks of the adapter (see
+// ApplyQuirks) and setting the protocol to talk with the car to "automatic".
+// An adapter that can not be identified is used without the workarounds.
+//
+// The address is a URL such as serial:///dev/ttyUSB0, tcp://192.168.0.10:35000
+// or test:// for the mock device (see NewMockDevice). The path of an existing
+// file is opened as a serial device.
 func NewDevice(addr string, debug bool) (*Device, error) {
 	// If addr is an existing file/device we use it as a serial device
 	if _, err := os.Stat(addr); err == nil {
@@ -208,21 +333,18 @@ func NewDevice(addr string, debug bool) (*Device, error) {
 
 	dev := Device{outputDebug: debug}
 
-	switch u.Scheme {
-	case "serial":
-		dev.rawDevice, err = NewSerialDevice(u)
-	case "tcp", "tcp4", "tcp6", "unix":
-		dev.rawDevice, err = NewNetDevice(u)
-	case "test":
-		dev.rawDevice, err = &MockDevice{}, nil
-	default:
-		err = fmt.Errorf("unknown device scheme: %q", u.Scheme)
-	}
+	dev.rawDevice, err = openTransport(u)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// Detecting the quirks is best effort, not every adapter identifies itself
+	// in a way that can be parsed
+	if _, err := dev.ApplyQuirks(); err != nil && debug {
+		fmt.Printf("Skipping quirks: %s\n", err)
+	}
+
 	err = dev.SetAutomaticProtocol()
 
 	if err != nil {
@@ -232,12 +354,25 @@ func NewDevice(addr string, debug bool) (*Device, error) {
 	return &dev, nil
 }
 
+// SetPlausibilityCheck turns checking the processed values against the range
+// of the values a car can report (see GetPlausibleRange) on or off, it is off
+// by default. When on, commands such as engine_rpm fail with
+// ErrValueOutOfRange for values the payload can encode but a car does not
+// report, such as 16383.75 rpm, which catches garbage from flaky adapters.
+//
+// Keep in mind that it also rejects real readings outside of the ranges, such
+// as a motorcycle revving past 10000 rpm or an overheating engine. The check
+// also applies to the values read through a ValueCache and to freeze frames.
+func (dev *Device) SetPlausibilityCheck(enabled bool) {
+	dev.plausible = enabled
+}
+
 // SetAutomaticProtocol tells the ELM327 device to automatically discover what
 // protocol to talk to the car with. How the protocol is chosen is something
 // that the ELM327 does internally. If you're interested in how this works you
 // can look in the data sheet linked in the beginning of the package description.
 func (dev *Device) SetAutomaticProtocol() error {
-	rawRes := dev.rawDevice.RunCommand("ATSP0")
+	rawRes := dev.runCommand("ATSP0")
 
 	if rawRes.Failed() {
 		return rawRes.GetError()
@@ -259,10 +394,10 @@ func (dev *Device) SetAutomaticProtocol() error {
 	return nil
 }
 
-// GetVersion gets the version of the connected ELM327 device. The latest
-// version being v2.2.
+// GetVersion gets the device description of the connected ELM327 device
+// (AT@1). Use GetChipVersion to get the parsed version of the chip.
 func (dev *Device) GetVersion() (string, error) {
-	rawRes := dev.rawDevice.RunCommand("AT@1")
+	rawRes := dev.runCommand("AT@1")
 
 	if rawRes.Failed() {
 		return "", rawRes.GetError()
@@ -281,7 +416,7 @@ func (dev *Device) GetVersion() (string, error) {
 // GetVoltage gets the current battery voltage of the vehicle as measured
 // by the ELM327 device.
 func (dev *Device) GetVoltage() (float32, error) {
-	rawRes := dev.rawDevice.RunCommand("AT RV")
+	rawRes := dev.runCommand("AT RV")
 
 	if rawRes.Failed() {
 		return -1, rawRes.GetError()
@@ -303,7 +438,7 @@ func (dev *Device) GetVoltage() (float32, error) {
 
 // GetIgnitionState retrieves the current state of the cars ignition
 func (dev *Device) GetIgnitionState() (bool, error) {
-	rawRes := dev.rawDevice.RunCommand("ATIGN")
+	rawRes := dev.runCommand("ATIGN")
 
 	if rawRes.Failed() {
 		return false, rawRes.GetError()
@@ -327,29 +462,70 @@ func (dev *Device) GetIgnitionState() (bool, error) {
 
 // CheckSupportedCommands check which commands are supported by the car connected
 // to the ELM327 device.
+//
+// The parts are checked up to part 8 (PIDs 0xE1 to 0xFF), a part after the
+// first that can not be read is treated as supporting none of its PIDs.
+//
+// The vehicle information of service 09 is checked as well, a car that does
+// not answer it is treated as supporting none of it.
 func (dev *Device) CheckSupportedCommands() (*SupportedCommands, error) {
 	result := &SupportedCommands{
-		[]*PartSupported{},
+		parts: []*PartSupported{},
 	}
 
-	index := byte(1)
-
-	for {
+	for index := byte(1); index <= PartsAmount; index++ {
 		part := NewPartSupported(index)
 
 		partRes, err := dev.RunOBDCommand(part)
 
-		if err == nil {
-			result.AddPart(partRes.(*PartSupported))
+		if err != nil {
+			if index == 1 {
+				return nil, fmt.Errorf("failed to check supported commands: %w", err)
+			}
+
+			break
+		}
+
+		result.AddPart(partRes.(*PartSupported))
+
+		// Check if the car supports the PID that checks if the next part of PIDs
+		// are supported
+		if !part.SupportsNextPart() {
+			break
+		}
+	}
+
+	vehicleInfo := NewVehicleInfoSupported()
+
+	if _, err := dev.RunOBDCommand(vehicleInfo); err == nil {
+		result.SetVehicleInfoPart(vehicleInfo)
+	}
+
+	return result, nil
+}
+
+// CheckSupportedMonitorIDs checks which on-board monitor IDs (MIDs) of
+// service 06 are supported by the car connected to the ELM327 device, so that
+// the test results of exactly those monitors can be requested.
+func (dev *Device) CheckSupportedMonitorIDs() (*SupportedMonitorIDs, error) {
+	result := &SupportedMonitorIDs{}
+
+	for index := byte(1); index <= PartsAmount; index++ {
+		part := NewMonitorIDsSupported(index)
 
-			// Check if the car supports the PID that checks if the next part of PIDs
-			// are supported
-			if !part.SupportsNextPart() {
-				break
+		if _, err := dev.RunOBDCommand(part); err != nil {
+			if index == 1 {
+				return nil, fmt.Errorf("failed to check supported monitor IDs: %w", err)
 			}
+
+			break
 		}
 
-		index++
+		result.parts = append(result.parts, part)
+
+		if !part.SupportsNextPart() {
+			break
+		}
 	}
 
 	return result, nil
@@ -357,34 +533,52 @@ func (dev *Device) CheckSupportedCommands() (*SupportedCommands, error) {
 
 // RunOBDCommand runs the given OBDCommand on the connected ELM327 device and
 // populates the OBDCommand with the parsed output from the device.
+//
+// If the processed value is outside of the range of the command a
+// ValueOutOfRangeError is returned, the command is still populated with the
+// value.
 func (dev *Device) RunOBDCommand(cmd OBDCommand) (OBDCommand, error) {
-	rawRes := dev.rawDevice.RunCommand(cmd.ToCommand())
-
-	if rawRes.Failed() {
-		return cmd, rawRes.GetError()
-	}
+	return dev.RunOBDCommandContext(context.Background(), cmd)
+}
 
-	if dev.outputDebug {
-		fmt.Println(rawRes.FormatOverview())
+// RunOBDCommandContext works like RunOBDCommand, but takes a context that is
+// given to the Tracer of the device (see SetTracer), so that the span of the
+// command becomes part of the trace of the caller.
+//
+// The context is checked before the command is sent, a command that has been
+// sent to the ELM327 device is always waited for.
+func (dev *Device) RunOBDCommandContext(ctx context.Context, cmd OBDCommand) (OBDCommand, error) {
+	if err := ctx.Err(); err != nil {
+		return cmd, err
 	}
 
-	result, err := parseOBDResponse(cmd, rawRes.GetOutputs())
+	if dev.tracer == nil {
+		_, err := dev.runOBDCommand(cmd)
 
-	if err != nil {
 		return cmd, err
-	} else {
-		if result == nil {
-			return cmd, nil
-		}
 	}
 
-	err = result.Validate(cmd)
+	span := dev.tracer.StartSpan(ctx, runOBDCommandSpanName)
+	rawRes, err := dev.runOBDCommand(cmd)
 
-	if err != nil {
-		return cmd, err
-	}
+	endCommandSpan(span, cmd, rawRes, err)
 
-	err = cmd.SetValue(result)
+	return cmd, err
+}
+
+// Run runs the given command on the device like RunOBDCommand, but gives back
+// the command as its own type, which avoids having to do a type assertion to
+// get to the processed value:
+//
+//	status, err := elmobd.Run(dev, elmobd.NewMonitorStatus())
+//
+//	if err != nil {
+//		return err
+//	}
+//
+//	fmt.Println(status.MilActive, status.DtcAmount)
+func Run[T OBDCommand](dev *Device, cmd T) (T, error) {
+	_, err := dev.RunOBDCommand(cmd)
 
 	return cmd, err
 }
@@ -406,11 +600,122 @@ func (dev *Device) RunManyOBDCommands(commands []OBDCommand) ([]OBDCommand, erro
 	return result, nil
 }
 
+// CommandResult represents the outcome of running one command, see
+// RunOBDCommandResult.
+//
+// Payload is the raw payload of the response after the mode and parameter
+// ID, nil when there was none. Time is when the command was started, and the
+// durations are zero when the device does not measure them.
+type CommandResult struct {
+	Command   OBDCommand
+	Err       error
+	Payload   []byte
+	Time      time.Time
+	WriteTime time.Duration
+	ReadTime  time.Duration
+	Duration  time.Duration
+}
+
+// Reading returns the processed value of the command as a Reading taken at
+// the time of the result.
+func (res CommandResult) Reading() Reading {
+	return NewReading(res.Command, res.Time)
+}
+
+// RunOBDCommandResult works like RunOBDCommandContext, but returns the
+// outcome of running the command as a CommandResult, which also holds the raw
+// payload, when the command was run and how long it took, such as for
+// logging without the debug output of the device.
+func (dev *Device) RunOBDCommandResult(ctx context.Context, cmd OBDCommand) CommandResult {
+	res := CommandResult{Command: cmd, Time: time.Now()}
+
+	if err := ctx.Err(); err != nil {
+		res.Err = err
+
+		return res
+	}
+
+	var span Span
+
+	if dev.tracer != nil {
+		span = dev.tracer.StartSpan(ctx, runOBDCommandSpanName)
+	}
+
+	result := &Result{}
+	rawRes, found, err := dev.runOBDCommandInto(cmd, result)
+
+	if span != nil {
+		endCommandSpan(span, cmd, rawRes, err)
+	}
+
+	res.Err = err
+	res.Duration = time.Since(res.Time)
+
+	if found {
+		res.Payload = result.Payload()
+	}
+
+	if timed, ok := rawRes.(TimedResult); ok {
+		res.WriteTime = timed.GetWriteTime()
+		res.ReadTime = timed.GetReadTime()
+	}
+
+	return res
+}
+
+// RunManyOBDCommandsContinue runs multiple commands in series like
+// RunManyOBDCommands, but continues with the next command when a command
+// fails, so that one unsupported PID does not throw away the whole snapshot.
+//
+// The results are in the same order as the given commands, each with the
+// error of running it, if any.
+func (dev *Device) RunManyOBDCommandsContinue(commands []OBDCommand) []CommandResult {
+	results := make([]CommandResult, 0, len(commands))
+
+	for _, cmd := range commands {
+		results = append(results, dev.RunOBDCommandResult(context.Background(), cmd))
+	}
+
+	return results
+}
+
+// SucceededCommands retrieves the commands of the given results that ran
+// without error, such as for handing them on to a logger.
+func SucceededCommands(results []CommandResult) []OBDCommand {
+	var commands []OBDCommand
+
+	for _, res := range results {
+		if res.Err == nil {
+			commands = append(commands, res.Command)
+		}
+	}
+
+	return commands
+}
+
+// Close closes the connection to the ELM327 device. The Device can not be
+// used after it has been closed.
+func (dev *Device) Close() error {
+	if closer, ok := dev.rawDevice.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Stats retrieves a snapshot of the counters kept about the commands run on
+// the device, such as how many commands failed and the latency of the
+// commands. Useful for long-running loggers reporting the health of the link.
+func (dev *Device) Stats() Stats {
+	return dev.stats.snapshot()
+}
+
 // SupportedCommands represents the lookup table for which commands
 // (PID 1 to PID 160) that are supported by the car connected to the ELM327
-// device.
+// device, along with the vehicle information of service 09.
 type SupportedCommands struct {
-	parts []*PartSupported
+	parts       []*PartSupported
+	vehicleInfo *PartSupported
 }
 
 // NewSupportedCommands creates a new PartSupported.
@@ -428,7 +733,7 @@ func NewSupportedCommands(partValues []uint32) (*SupportedCommands, error) {
 		index++
 	}
 
-	return &SupportedCommands{parts}, nil
+	return &SupportedCommands{parts: parts}, nil
 }
 
 // AddPart adds the given part to the slice of parts checked.
@@ -436,6 +741,23 @@ func (sc *SupportedCommands) AddPart(part *PartSupported) {
 	sc.parts = append(sc.parts, part)
 }
 
+// SetVehicleInfoPart sets the part checking which PIDs of service 09 are
+// supported, see NewVehicleInfoSupported.
+func (sc *SupportedCommands) SetVehicleInfoPart(part *PartSupported) {
+	sc.vehicleInfo = part
+}
+
+// SupportsVehicleInfo checks if the given PID of service 09 is supported, such
+// as VehicleInfoVIN. Returns false if the vehicle information has not been
+// checked.
+func (sc *SupportedCommands) SupportsVehicleInfo(pid OBDParameterID) bool {
+	if sc.vehicleInfo == nil {
+		return false
+	}
+
+	return sc.vehicleInfo.SupportsPID(pid)
+}
+
 // GetPart gets the part at the given index.
 func (sc *SupportedCommands) GetPart(index byte) (*PartSupported, error) {
 	partsAmount := len(sc.parts)
@@ -471,6 +793,10 @@ func (sc *SupportedCommands) IsSupported(cmd OBDCommand) bool {
 	}
 
 	pid := cmd.ParameterID()
+
+	if cmd.ModeID() == SERVICE_09_ID {
+		return sc.SupportsVehicleInfo(pid)
+	}
 	part, err := sc.GetPartByPID(pid)
 
 	if err != nil {
@@ -493,10 +819,159 @@ func (sc *SupportedCommands) FilterSupported(commands []OBDCommand) []OBDCommand
 	return result
 }
 
+// SupportedMonitorIDs represents the lookup table for which on-board monitor
+// IDs (MIDs) of service 06 are supported by the car connected to the ELM327
+// device, see Device.CheckSupportedMonitorIDs.
+type SupportedMonitorIDs struct {
+	parts []*PartSupported
+}
+
+// IsSupported checks if the given monitor ID is supported.
+func (sm *SupportedMonitorIDs) IsSupported(mid OBDParameterID) bool {
+	for _, part := range sm.parts {
+		if part.SupportsPID(mid) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IDs retrieves all supported monitor IDs in ascending order, leaving out the
+// MIDs that check the support of the next part.
+func (sm *SupportedMonitorIDs) IDs() []OBDParameterID {
+	var mids []OBDParameterID
+
+	for _, part := range sm.parts {
+		mids = append(mids, partPIDs(part)...)
+	}
+
+	return mids
+}
+
 /*==============================================================================
  * Internal
  */
 
+// runOBDCommand runs the given OBDCommand and populates it with the parsed
+// output, the raw result is returned as well so that it can be inspected by
+// the caller.
+func (dev *Device) runOBDCommand(cmd OBDCommand) (RawResult, error) {
+	result := resultPool.Get().(*Result)
+
+	defer resultPool.Put(result)
+
+	rawRes, _, err := dev.runOBDCommandInto(cmd, result)
+
+	return rawRes, err
+}
+
+// runOBDCommandInto works like runOBDCommand, but parses the response into
+// the given result. It returns false if there was no payload.
+func (dev *Device) runOBDCommandInto(cmd OBDCommand, result *Result) (RawResult, bool, error) {
+	rawRes := dev.runCommand(cmd.ToCommand())
+
+	if rawRes.Failed() {
+		return rawRes, false, rawRes.GetError()
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	found, err := parseOBDResponseInto(result, cmd, rawRes.GetOutputs())
+
+	if err != nil {
+		return rawRes, false, err
+	} else {
+		if !found {
+			return rawRes, false, nil
+		}
+	}
+
+	err = result.Validate(cmd)
+
+	if err != nil {
+		return rawRes, true, err
+	}
+
+	err = cmd.SetValue(result)
+
+	if err != nil {
+		return rawRes, true, err
+	}
+
+	return rawRes, true, dev.validateRanges(result, cmd)
+}
+
+// validateRanges checks the processed value of the given command against the
+// range of the command, and against the plausible range when the plausibility
+// check is turned on (see SetPlausibilityCheck).
+func (dev *Device) validateRanges(result *Result, cmd OBDCommand) error {
+	if err := result.ValidateRange(cmd); err != nil || !dev.plausible {
+		return err
+	}
+
+	return result.ValidatePlausibility(cmd)
+}
+
+// runCommand runs the given raw command on the underlying device, waiting for
+// the rate limit of the device if any, records the result in the statistics
+// of the device and updates the state of the device. A "?" answer is turned
+// into a failed result with an UnknownCommandError.
+func (dev *Device) runCommand(command string) RawResult {
+	defer dev.enterFlight()()
+
+	release := dev.limiter.acquire()
+
+	dev.state.begin()
+
+	start := time.Now()
+	rawRes := checkUnknownCommand(command, dev.rawDevice.RunCommand(command))
+
+	release()
+
+	dev.stats.record(command, rawRes, time.Since(start))
+
+	dev.state.end(rawRes.Failed())
+
+	return rawRes
+}
+
+// unknownCommandResult is a result the device answered "?" to, see
+// checkUnknownCommand.
+type unknownCommandResult struct {
+	RawResult
+	err error
+}
+
+func (res *unknownCommandResult) Failed() bool {
+	return true
+}
+
+func (res *unknownCommandResult) GetError() error {
+	return res.err
+}
+
+// checkUnknownCommand turns the result of the given command into a failed
+// result if the device answered "?".
+func checkUnknownCommand(command string, rawRes RawResult) RawResult {
+	if rawRes.Failed() || len(rawRes.GetOutputs()) == 0 {
+		return rawRes
+	}
+
+	for _, out := range rawRes.GetOutputs() {
+		if strings.TrimSpace(out) != "?" {
+			return rawRes
+		}
+	}
+
+	return &unknownCommandResult{
+		RawResult: rawRes,
+		err:       &UnknownCommandError{Input: command},
+	}
+}
+
 // parseOBDResponse parses the raw outputs produced from running the given
 // OBDCommand on the connected ELM327 device.
 //
@@ -511,36 +986,226 @@ func (sc *SupportedCommands) FilterSupported(commands []OBDCommand) []OBDCommand
 // lines containing "SEARCHING..." or "BUS INIT". The first line that passes
 // these checks is assumed to be the payload.
 //
-// This means that this function cannot handle multiline responses
-// (such as getting the VIN number, and multiple PID requests baked into one).
-// Handling these more advanced responses is something that is going to be
-// implemented, but right now has been de-prioritized.
+// On CAN, payloads longer than what fits in one frame are split into
+// several numbered lines preceded by the length of the payload, such as:
+//
+//   009
+//   0: 41 68 3F 50 51 52
+//   1: 53 54 55 00 00 00 00
+//
+// These lines are joined into one payload. Responses from multiple ECUs
+// and multiple PID requests baked into one can not be handled yet.
 func parseOBDResponse(cmd OBDCommand, outputs []string) (*Result, error) {
+	result := &Result{}
+	found, err := parseOBDResponseInto(result, cmd, outputs)
+
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// resultPool keeps the results used by runOBDCommand, so that polling does
+// not allocate a new result for each response. The commands only read the
+// value of a result while it is being set, so it is safe to reuse.
+var resultPool = sync.Pool{
+	New: func() interface{} {
+		return &Result{make([]byte, 0, 16)}
+	},
+}
+
+// parseOBDResponseInto works like parseOBDResponse, but parses the payload
+// into the given result, reusing its buffer. It returns false if there was no
+// payload.
+func parseOBDResponseInto(result *Result, cmd OBDCommand, outputs []string) (bool, error) {
 	payload := ""
 
-	for _, out := range outputs {
+	for i, out := range outputs {
 		if strings.HasPrefix(out, "UNABLE TO CONNECT") {
-			return nil, fmt.Errorf(
-				"'UNABLE TO CONNECT' received, is the ignition on?",
-			)
+			return false, ErrUnableToConnect
 		} else if strings.HasPrefix(out, "NO DATA") {
-			return nil, fmt.Errorf(
-				"'NO DATA' received, timeout from elm device?",
-			)
+			return false, ErrNoData
 		} else if strings.HasPrefix(out, "SEARCHING") {
 			continue
 		} else if strings.HasPrefix(out, "BUS INIT") {
 			continue
 		}
 
+		if isFrameLength(out) {
+			joined, err := joinFrames(out, outputs[i+1:])
+
+			if err != nil {
+				return false, err
+			}
+
+			payload = joined
+
+			break
+		}
+
 		payload = out
 
 		break
 	}
 
 	if payload == "" {
-		return nil, nil
+		return false, nil
+	}
+
+	return true, result.parse(payload)
+}
+
+// parse parses the given line of hex bytes into the result, reusing the
+// buffer of the result.
+func (res *Result) parse(rawLine string) error {
+	value, err := appendHex(res.value[:0], rawLine)
+
+	if err != nil {
+		return err
+	}
+
+	if len(value) < 3 {
+		return fmt.Errorf(
+			"Expected at least 3 OBD literals: %s", rawLine,
+		)
+	}
+
+	res.value = value
+
+	return nil
+}
+
+// appendHex parses a line of hex bytes and appends them to the given buffer,
+// without allocating when the buffer is large enough. The bytes are separated
+// by spaces, unless the device has been told to leave them out (ATS0), see
+// hexLiterals.
+func appendHex(dst []byte, line string) ([]byte, error) {
+	spaced := strings.IndexAny(strings.TrimSpace(line), " \t") >= 0
+
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+
+			continue
+		}
+
+		end := i
+
+		for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+			end++
+		}
+
+		literal := line[i:end]
+		i = end
+
+		if len(literal) == 1 {
+			digit, ok := hexDigit(literal[0])
+
+			if !ok {
+				return dst, fmt.Errorf("invalid hex byte %q in %q", literal, line)
+			}
+
+			dst = append(dst, digit)
+
+			continue
+		}
+
+		if len(literal)%2 != 0 || (spaced && len(literal) > 2) {
+			return dst, fmt.Errorf("invalid hex byte %q in %q", literal, line)
+		}
+
+		for j := 0; j < len(literal); j += 2 {
+			high, okHigh := hexDigit(literal[j])
+			low, okLow := hexDigit(literal[j+1])
+
+			if !okHigh || !okLow {
+				return dst, fmt.Errorf("invalid hex byte %q in %q", literal[j:j+2], line)
+			}
+
+			dst = append(dst, high<<4|low)
+		}
+	}
+
+	return dst, nil
+}
+
+// hexDigit returns the value of the given hex digit, the second return value
+// is false if it is not a hex digit.
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	}
+
+	return 0, false
+}
+
+// hexLiterals splits a line of hex bytes into its literals. The bytes are
+// separated by spaces, unless the device has been told to leave them out
+// (ATS0), then the line is split into pairs of digits.
+func hexLiterals(line string) []string {
+	literals := strings.Fields(line)
+
+	if len(literals) != 1 || len(literals[0]) <= 2 || len(literals[0])%2 != 0 {
+		return literals
+	}
+
+	joined := literals[0]
+	literals = nil
+
+	for i := 0; i < len(joined); i += 2 {
+		literals = append(literals, joined[i:i+2])
+	}
+
+	return literals
+}
+
+// isFrameLength checks if the given output line is the length preceding the
+// frames of a multi-frame response, which is 3 hex digits.
+func isFrameLength(out string) bool {
+	if len(out) != 3 {
+		return false
+	}
+
+	_, err := strconv.ParseUint(out, 16, 16)
+
+	return err == nil
+}
+
+// joinFrames joins the bytes of the numbered frames of a multi-frame
+// response into one space-separated line of the given hex length. The
+// padding of the last frame is dropped.
+func joinFrames(length string, frames []string) (string, error) {
+	amount, err := strconv.ParseUint(length, 16, 16)
+
+	if err != nil {
+		return "", err
+	}
+
+	var literals []string
+
+	for _, frame := range frames {
+		index := strings.Index(frame, ":")
+
+		if index < 0 {
+			break
+		}
+
+		literals = append(literals, hexLiterals(frame[index+1:])...)
+	}
+
+	if uint64(len(literals)) < amount {
+		return "", fmt.Errorf(
+			"Expected %d bytes in multi-frame response, got %d",
+			amount,
+			len(literals),
+		)
 	}
 
-	return NewResult(payload)
+	return strings.Join(literals[:amount], " "), nil
 }
@@ -1,7 +1,11 @@
 package elmobd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 )
 
 /*==============================================================================
@@ -13,6 +17,8 @@ var resultParseOBDResponse *Result
 func benchParseOBDResponse(cmd OBDCommand, input []string, b *testing.B) {
 	var r *Result
 
+	b.ReportAllocs()
+
 	for n := 0; n < b.N; n++ {
 		r, _ = parseOBDResponse(cmd, input)
 	}
@@ -36,6 +42,20 @@ func BenchmarkParseOBDResponse2(b *testing.B) {
 	)
 }
 
+func BenchmarkParseOBDResponseInto(b *testing.B) {
+	cmd := NewEngineRPM()
+	input := []string{"41 0C 1A F8"}
+	result := &Result{make([]byte, 0, 16)}
+
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		parseOBDResponseInto(result, cmd, input)
+	}
+
+	resultParseOBDResponse = result
+}
+
 /*==============================================================================
  * Tests
  */
@@ -66,6 +86,80 @@ func TestIsSupported(t *testing.T) {
 	assertEqual(t, sc.IsSupported(cmd2), true)
 }
 
+func TestSupportsVehicleInfo(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	sc, err := dev.CheckSupportedCommands()
+
+	assertSuccess(t, err)
+	assertEqual(t, sc.SupportsVehicleInfo(VehicleInfoVIN), true)
+	assertEqual(t, sc.SupportsVehicleInfo(VehicleInfoCVN), true)
+	assertEqual(t, sc.SupportsVehicleInfo(VehicleInfoECUName), true)
+	assertEqual(t, sc.SupportsVehicleInfo(VehicleInfoPerformanceTrackingCompression), false)
+
+	sc, err = NewSupportedCommands([]uint32{0x0})
+
+	assertSuccess(t, err)
+	assertEqual(t, sc.SupportsVehicleInfo(VehicleInfoVIN), false)
+}
+
+func TestCheckSupportedCommandsAllParts(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	// Every part supports the next one, part 7 supports PID 0xC3 and part 8
+	// PID 0xFF
+	for pid := byte(0x00); pid < 0xE0; pid += PartRange {
+		raw.SetPIDValue(pid, 0x00, 0x00, 0x00, 0x01)
+	}
+
+	raw.SetPIDValue(0xC0, 0x20, 0x00, 0x00, 0x01)
+	raw.SetPIDValue(0xE0, 0x00, 0x00, 0x00, 0x03)
+
+	sc, err := dev.CheckSupportedCommands()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(sc.parts), PartsAmount)
+	assertEqual(t, sc.parts[7].SupportsPID(0xFF), true)
+	assertEqual(t, sc.parts[7].SupportsNextPart(), false)
+	assertEqual(t, fmt.Sprint(sc.SupportedPIDs()), "[195 255]")
+
+	part, err := sc.GetPartByPID(0xFF)
+
+	assertSuccess(t, err)
+	assertEqual(t, part.Index(), byte(8))
+}
+
+func TestCheckSupportedCommandsFailingPart(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	raw.SetPIDValue(0x00, 0x00, 0x00, 0x00, 0x01)
+	raw.SetPIDValue(0x20, 0x00, 0x00, 0x00, 0x01)
+	raw.setResponse("0140", "NO DATA")
+
+	sc, err := dev.CheckSupportedCommands()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(sc.parts), 2)
+
+	raw.setResponse("0100", "UNABLE TO CONNECT")
+
+	_, err = dev.CheckSupportedCommands()
+
+	assert(t, errors.Is(err, ErrUnableToConnect), "Expected ErrUnableToConnect")
+}
+
+func TestCheckSupportedMonitorIDs(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	sm, err := dev.CheckSupportedMonitorIDs()
+
+	assertSuccess(t, err)
+	assertEqual(t, sm.IsSupported(0x01), true)
+	assertEqual(t, sm.IsSupported(0x03), false)
+	assertEqual(t, sm.IsSupported(0x21), true)
+	assertEqual(t, fmt.Sprint(sm.IDs()), "[1 2 33]")
+}
+
 func TestGetPart(t *testing.T) {
 	sc, err := NewSupportedCommands([]uint32{0x0, 0x0, 0x0, 0x0})
 
@@ -397,3 +491,262 @@ func TestParseOBDResponse(t *testing.T) {
 		assertOBDParseSuccess(t, curr.command, curr.outputs)
 	}
 }
+
+func TestParseOBDResponseMultiFrame(t *testing.T) {
+	outputs := []string{
+		"009",
+		"0: 41 68 3F 50 51 52",
+		"1: 53 54 55 00 00 00 00",
+	}
+
+	result, err := parseOBDResponse(NewIntakeAirTemperatureSensors(), outputs)
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprintf("% X", result.value), "41 68 3F 50 51 52 53 54 55")
+
+	_, err = parseOBDResponse(NewIntakeAirTemperatureSensors(), outputs[:2])
+
+	assert(t, err != nil, "missing frames fail")
+}
+
+func TestRunTyped(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	status, err := Run(dev, NewMonitorStatus())
+
+	assertSuccess(t, err)
+	assertEqual(t, status.MilActive, true)
+	assertEqual(t, status.DtcAmount, byte(127))
+
+	speed, err := Run(dev, NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, speed.Value, uint32(75))
+}
+
+func TestRunManyOBDCommandsContinue(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	commands := []OBDCommand{
+		NewVehicleSpeed(),
+		NewTimeWithMILOn(),
+		NewEngineRPM(),
+	}
+
+	_, err := dev.RunManyOBDCommands(commands)
+
+	assert(t, err != nil, "Expected unsupported command to fail")
+
+	results := dev.RunManyOBDCommandsContinue(commands)
+
+	assertEqual(t, len(results), 3)
+	assertSuccess(t, results[0].Err)
+	assert(t, results[1].Err != nil, "Expected unsupported command to fail")
+	assertSuccess(t, results[2].Err)
+	assertEqual(t, results[2].Command.ValueAsLit(), "192.000000")
+
+	succeeded := SucceededCommands(results)
+
+	assertEqual(t, len(succeeded), 2)
+	assertEqual(t, succeeded[1].Key(), "engine_rpm")
+}
+
+func TestValidateRangeBoundaries(t *testing.T) {
+	commands := append(
+		GetSensorCommands(),
+		NewFuel(),
+		NewDistSinceDTCClear(),
+		NewOdometer(),
+		NewTransmissionActualGear(),
+		NewControlModuleVoltage(),
+		NewAmbientTemperature(),
+		NewEngineOilTemperature(),
+		NewAbsoluteBarometricPressure(),
+	)
+
+	for _, cmd := range commands {
+		for _, literal := range []string{"00", "FF"} {
+			rawLine := fmt.Sprintf("41 %02X", cmd.ParameterID())
+
+			for i := byte(0); i < cmd.DataWidth(); i++ {
+				rawLine += " " + literal
+			}
+
+			result, err := NewResult(rawLine)
+			assertSuccess(t, err)
+
+			assertSuccess(t, cmd.SetValue(result))
+			assertSuccess(t, result.ValidateRange(cmd))
+
+			_, implausible := GetPlausibleRange(cmd)
+
+			if literal == "FF" && implausible {
+				assert(
+					t,
+					errors.Is(result.ValidatePlausibility(cmd), ErrValueOutOfRange),
+					fmt.Sprintf("FF payload of %s is implausible", cmd.Key()),
+				)
+			} else {
+				assertSuccess(t, result.ValidatePlausibility(cmd))
+			}
+		}
+	}
+}
+
+func TestValidateRangeOutOfRange(t *testing.T) {
+	result, err := NewResult("41 0C FF FF")
+	assertSuccess(t, err)
+
+	cmd := NewEngineRPM()
+
+	assertSuccess(t, cmd.SetValue(result))
+	assertSuccess(t, result.ValidateRange(cmd))
+
+	err = result.ValidatePlausibility(cmd)
+
+	assert(t, errors.Is(err, ErrValueOutOfRange), "error is ErrValueOutOfRange")
+
+	var rangeErr *ValueOutOfRangeError
+
+	assert(t, errors.As(err, &rangeErr), "error is a ValueOutOfRangeError")
+	assertEqual(t, rangeErr.Key, "engine_rpm")
+	assertEqual(t, rangeErr.Value, 16383.75)
+	assertEqual(t, fmt.Sprintf("% X", rangeErr.Payload), "FF FF")
+}
+
+func TestRunOBDCommandOutOfRange(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	raw.SetPIDValue(0x05, 0xFF)
+
+	_, err := dev.RunOBDCommand(NewCoolantTemperature())
+
+	assertSuccess(t, err)
+
+	dev.SetPlausibilityCheck(true)
+
+	_, err = dev.RunOBDCommand(NewCoolantTemperature())
+
+	assert(t, errors.Is(err, ErrValueOutOfRange), "215 °C coolant is implausible")
+
+	_, err = NewValueCache(dev, time.Second).RunOBDCommand(NewCoolantTemperature())
+
+	assert(t, errors.Is(err, ErrValueOutOfRange), "Expected the cache to check plausibility")
+
+	raw.SetPIDValue(0x05, 0x82)
+
+	_, err = dev.RunOBDCommand(NewCoolantTemperature())
+
+	assertSuccess(t, err)
+}
+
+func TestUnknownCommand(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	err := dev.runSetting("ATAT9")
+
+	assert(t, errors.Is(err, ErrUnknownCommand), "error is ErrUnknownCommand")
+
+	var unknownErr *UnknownCommandError
+
+	assert(t, errors.As(err, &unknownErr), "error is an UnknownCommandError")
+	assertEqual(t, unknownErr.Input, "ATAT9")
+	assertEqual(t, dev.State(), DeviceError)
+	assertEqual(t, dev.Stats().CommandsFailed, uint64(1))
+
+	assertSuccess(t, dev.runSetting("ATAT2"))
+}
+
+func TestParseOBDResponseIntoAllocs(t *testing.T) {
+	cmd := NewEngineRPM()
+	result := &Result{make([]byte, 0, 16)}
+	inputs := [][]string{
+		{"41 0C 1A F8"},
+		{"SEARCHING...", "410C1AF8"},
+	}
+
+	for _, input := range inputs {
+		allocs := testing.AllocsPerRun(100, func() {
+			found, err := parseOBDResponseInto(result, cmd, input)
+
+			if !found || err != nil {
+				t.Fatalf("failed to parse %q: %v", input, err)
+			}
+
+			cmd.SetValue(result)
+		})
+
+		assertEqual(t, allocs, float64(0))
+		assertEqual(t, cmd.Value, float32(1726))
+	}
+}
+
+// calibrationID is a command with a variable amount of bytes, implemented
+// like a command outside of the package would.
+type calibrationID struct {
+	Value string
+}
+
+func (cmd *calibrationID) ModeID() byte                { return SERVICE_09_ID }
+func (cmd *calibrationID) ParameterID() OBDParameterID { return 0x04 }
+func (cmd *calibrationID) DataWidth() byte             { return 16 }
+func (cmd *calibrationID) DataWidthRange() (int, int)  { return 1, 16 }
+func (cmd *calibrationID) Key() string                 { return "calibration_id" }
+func (cmd *calibrationID) ValueAsLit() string          { return cmd.Value }
+func (cmd *calibrationID) ToCommand() string           { return "0904" }
+
+func (cmd *calibrationID) SetValue(result *Result) error {
+	cmd.Value = string(result.Payload())
+
+	return nil
+}
+
+func TestVariableWidthCommand(t *testing.T) {
+	raw := &scriptedDevice{outputs: map[string][]string{
+		"0904": {"49 04 41 42 43"},
+	}}
+	dev := &Device{rawDevice: raw}
+	cmd := &calibrationID{}
+
+	_, err := dev.RunOBDCommand(cmd)
+
+	assertSuccess(t, err)
+	assertEqual(t, cmd.Value, "ABC")
+
+	raw.outputs["0904"] = []string{"49 04"}
+
+	_, err = dev.RunOBDCommand(cmd)
+
+	assert(t, err != nil, "Expected empty payload to fail")
+
+	result, err := NewResult("41 0D 4B 00")
+
+	assertSuccess(t, err)
+	assert(t, result.Validate(NewVehicleSpeed()) != nil, "Expected fixed width to be exact")
+}
+
+func TestRunOBDCommandResult(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	res := dev.RunOBDCommandResult(context.Background(), NewVehicleSpeed())
+
+	assertSuccess(t, res.Err)
+	assertEqual(t, fmt.Sprintf("% X", res.Payload), "4B")
+	assert(t, !res.Time.IsZero(), "Expected result to have a timestamp")
+	assertEqual(t, res.Reading().Key, "vehicle_speed")
+	assertEqual(t, fmt.Sprint(res.Reading().Value), "75")
+	assertEqual(t, res.Reading().Time, res.Time)
+
+	res = dev.RunOBDCommandResult(context.Background(), NewTimeWithMILOn())
+
+	assert(t, res.Err != nil, "Expected unsupported command to fail")
+	assert(t, res.Payload == nil, "Expected failed command to have no payload")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	res = dev.RunOBDCommandResult(ctx, NewVehicleSpeed())
+
+	assert(t, errors.Is(res.Err, context.Canceled), "Expected cancelled context to fail")
+}
@@ -0,0 +1,128 @@
+package elmobd
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// TroubleCode represents a diagnostic trouble code (DTC) as the 2 bytes
+// received from the car, see String for its literal representation.
+type TroubleCode uint16
+
+// String returns the literal representation of the code, such as "P0133".
+//
+// The letter tells the system of the car: P for powertrain, C for chassis, B
+// for body and U for network.
+func (code TroubleCode) String() string {
+	return fmt.Sprintf(
+		"%c%d%03X",
+		"PCBU"[code>>14],
+		uint16(code>>12)&0x03,
+		uint16(code)&0x0FFF,
+	)
+}
+
+// MarshalText encodes the code as its literal representation, so that it is
+// readable when exporting.
+func (code TroubleCode) MarshalText() ([]byte, error) {
+	return []byte(code.String()), nil
+}
+
+// GetTroubleCodes retrieves the stored (confirmed) trouble codes of the car
+// by running service 03. The codes of all ECUs answering are returned, and an
+// empty slice when there are no codes.
+func (dev *Device) GetTroubleCodes() ([]TroubleCode, error) {
+	rawRes := dev.runCommand(fmt.Sprintf("%02X", SERVICE_03_ID))
+
+	if rawRes.Failed() {
+		return nil, rawRes.GetError()
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	return parseTroubleCodes(SERVICE_03_ID, rawRes.GetOutputs())
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// parseTroubleCodes parses the response of a service reading trouble codes,
+// which is one line per ECU, or per 3 codes on protocols other than CAN.
+//
+// On CAN the service byte is followed by the amount of codes, such as:
+//
+//	43 02 01 33 02 34
+//
+// Other protocols always send 3 codes per line, padded with zeros:
+//
+//	43 01 33 02 34 00 00
+func parseTroubleCodes(mode byte, outputs []string) ([]TroubleCode, error) {
+	codes := []TroubleCode{}
+
+	for i := 0; i < len(outputs); i++ {
+		out := outputs[i]
+
+		if strings.HasPrefix(out, "NO DATA") {
+			// Some cars do not answer at all when there are no codes
+			continue
+		} else if strings.HasPrefix(out, "UNABLE TO CONNECT") {
+			return nil, ErrUnableToConnect
+		} else if strings.HasPrefix(out, "SEARCHING") || strings.HasPrefix(out, "BUS INIT") {
+			continue
+		}
+
+		if isFrameLength(out) {
+			joined, err := joinFrames(out, outputs[i+1:])
+
+			if err != nil {
+				return nil, err
+			}
+
+			out = joined
+
+			// Skip the frames that were joined
+			for i+1 < len(outputs) && strings.Contains(outputs[i+1], ":") {
+				i++
+			}
+		}
+
+		payload, err := parseHexLine(out)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(payload) == 0 || payload[0] != mode+0x40 {
+			return nil, fmt.Errorf("unexpected trouble code response: %q", out)
+		}
+
+		payload = payload[1:]
+
+		// CAN responses have an odd length because of the amount of codes
+		if len(payload)%2 == 1 {
+			payload = payload[1:]
+		}
+
+		for j := 0; j+1 < len(payload); j += 2 {
+			code := TroubleCode(payload[j])<<8 | TroubleCode(payload[j+1])
+
+			if code != 0 {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	return codes, nil
+}
+
+// parseHexLine parses a line of space separated hex bytes.
+func parseHexLine(line string) ([]byte, error) {
+	return appendHex(nil, line)
+}
@@ -0,0 +1,73 @@
+package elmobd
+
+import (
+	"fmt"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestTroubleCodeString(t *testing.T) {
+	type scenario struct {
+		code TroubleCode
+		lit  string
+	}
+
+	scenarios := []scenario{
+		{0x0133, "P0133"},
+		{0x1234, "P1234"},
+		{0x4123, "C0123"},
+		{0x9ABC, "B1ABC"},
+		{0xC001, "U0001"},
+	}
+
+	for _, scen := range scenarios {
+		assertEqual(t, scen.code.String(), scen.lit)
+	}
+}
+
+func TestParseTroubleCodes(t *testing.T) {
+	type scenario struct {
+		outputs []string
+		codes   string
+	}
+
+	scenarios := []scenario{
+		{[]string{"43 00"}, "[]"},
+		{[]string{"43 00 00 00 00 00 00"}, "[]"},
+		{[]string{"NO DATA"}, "[]"},
+		{[]string{"SEARCHING...", "43 02 01 33 02 34"}, "[P0133 P0234]"},
+		{[]string{"43 01 33 02 34 C0 01", "43 41 23 00 00 00 00"}, "[P0133 P0234 U0001 C0123]"},
+		{[]string{"43 01 01 33", "43 01 02 34"}, "[P0133 P0234]"},
+		{
+			[]string{"00A", "0: 43 04 01 33 02 34", "1: C0 01 41 23 00 00 00"},
+			"[P0133 P0234 U0001 C0123]",
+		},
+	}
+
+	for _, scen := range scenarios {
+		codes, err := parseTroubleCodes(SERVICE_03_ID, scen.outputs)
+
+		assertSuccess(t, err)
+		assertEqual(t, fmt.Sprint(codes), scen.codes)
+	}
+
+	_, err := parseTroubleCodes(SERVICE_03_ID, []string{"41 0D 4B"})
+
+	assert(t, err != nil, "Expected response of another service to fail")
+
+	_, err = parseTroubleCodes(SERVICE_03_ID, []string{"UNABLE TO CONNECT"})
+
+	assert(t, err != nil, "Expected UNABLE TO CONNECT to fail")
+}
+
+func TestDeviceGetTroubleCodes(t *testing.T) {
+	dev := Device{rawDevice: &MockDevice{}}
+
+	codes, err := dev.GetTroubleCodes()
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprint(codes), "[P0133]")
+}
@@ -0,0 +1,52 @@
+package elmobd
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// ErrNoData is returned when the car did not answer a command in time, which
+// the ELM327 device reports as "NO DATA". The car usually does not support
+// the command, retrying it rarely helps.
+var ErrNoData = errors.New("'NO DATA' received, timeout from elm device?")
+
+// ErrUnableToConnect is returned when the ELM327 device could not connect to
+// the car, which it reports as "UNABLE TO CONNECT". The ignition is usually
+// off, retrying once it is on helps.
+var ErrUnableToConnect = errors.New("'UNABLE TO CONNECT' received, is the ignition on?")
+
+// TransportError represents a failure of the connection to the ELM327 device
+// itself, rather than of the car, such as the USB port vanishing or a read
+// timing out. The error of the serial port or network connection is wrapped,
+// so that errors.Is and errors.As reach it, see also IsTimeoutError and
+// IsDisconnectError.
+//
+// A timeout can be recovered from by retrying or resetting the device (see
+// EnableWatchdog), while a disconnected device has to be reopened.
+type TransportError struct {
+	// Op is the operation that failed, "open", "write" or "read".
+	Op  string
+	Err error
+}
+
+// Error describes the failed operation.
+func (err *TransportError) Error() string {
+	return fmt.Sprintf("failed to %s device: %v", err.Op, err.Err)
+}
+
+// Unwrap returns the error of the connection.
+func (err *TransportError) Unwrap() error {
+	return err.Err
+}
+
+// IsTransportError returns true if the given error is caused by the
+// connection to the ELM327 device, see TransportError.
+func IsTransportError(err error) bool {
+	var transportErr *TransportError
+
+	return errors.As(err, &transportErr)
+}
@@ -0,0 +1,76 @@
+package elmobd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// brokenConn fails all reads and writes with the given error.
+type brokenConn struct {
+	fakeConn
+	err error
+}
+
+func (conn *brokenConn) Read(p []byte) (int, error) {
+	return 0, conn.err
+}
+
+func (conn *brokenConn) Write(p []byte) (int, error) {
+	return 0, conn.err
+}
+
+func TestTransportError(t *testing.T) {
+	dev := &RealDevice{conn: &brokenConn{err: syscall.EIO}}
+	res := dev.RunCommand("010D1")
+
+	assert(t, IsTransportError(res.GetError()), "Expected a transport error")
+	assert(t, IsDisconnectError(res.GetError()), "Expected a disconnect error")
+	assert(t, !IsTimeoutError(res.GetError()), "Expected no timeout error")
+
+	var transportErr *TransportError
+
+	assert(t, errors.As(res.GetError(), &transportErr), "Expected a TransportError")
+	assertEqual(t, transportErr.Op, "write")
+
+	conn := newFakeConn("", nil)
+	dev = &RealDevice{conn: conn}
+	res = dev.RunCommand("010D1")
+
+	assert(t, IsTransportError(res.GetError()), "Expected a transport error")
+	assert(t, IsTimeoutError(res.GetError()), "Expected a timeout error")
+	assert(t, errors.Is(res.GetError(), io.EOF), "Expected the error of the port")
+
+	dev = &RealDevice{
+		conn: conn,
+		open: func() (Conn, error) {
+			return nil, os.ErrNotExist
+		},
+	}
+
+	err := dev.Reopen()
+
+	assert(t, IsTransportError(err), "Expected a transport error")
+	assert(t, IsDisconnectError(err), "Expected a disconnect error")
+}
+
+func TestCarErrors(t *testing.T) {
+	_, err := parseOBDResponse(NewVehicleSpeed(), []string{"SEARCHING...", "NO DATA"})
+
+	assert(t, errors.Is(err, ErrNoData), "Expected ErrNoData")
+	assert(t, !IsTransportError(err), "Expected no transport error")
+
+	_, err = parseOBDResponse(NewVehicleSpeed(), []string{"UNABLE TO CONNECT"})
+
+	assert(t, errors.Is(err, ErrUnableToConnect), "Expected ErrUnableToConnect")
+
+	_, err = parseTroubleCodes(SERVICE_03_ID, []string{"UNABLE TO CONNECT"})
+
+	assert(t, errors.Is(err, ErrUnableToConnect), "Expected ErrUnableToConnect")
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/rzetterberg/elmobd"
+	_ "github.com/rzetterberg/elmobd/transport/net"
+	_ "github.com/rzetterberg/elmobd/transport/serial"
 )
 
 func main() {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/rzetterberg/elmobd"
+	_ "github.com/rzetterberg/elmobd/transport/net"
+	_ "github.com/rzetterberg/elmobd/transport/serial"
 )
 
 func main() {
@@ -28,14 +30,12 @@ func main() {
 		return
 	}
 
-	cmd, err := dev.RunOBDCommand(elmobd.NewMonitorStatus())
+	status, err := elmobd.Run(dev, elmobd.NewMonitorStatus())
 
 	if err != nil {
 		fmt.Println("Failed to get monitor status", err)
 		return
 	}
 
-	status := cmd.(*elmobd.MonitorStatus)
-
 	fmt.Printf("MIL is on: %t, DTCamount: %d\n", status.MilActive, status.DtcAmount)
 }
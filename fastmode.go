@@ -0,0 +1,123 @@
+package elmobd
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// BaudRateSetter is implemented by raw devices that can switch the baud rate
+// of their connection, such as RealDevice for serial devices.
+type BaudRateSetter interface {
+	SetBaudRate(baud int) error
+}
+
+// FastModeStep represents one of the settings applied by Device.FastMode,
+// Err is set when the device did not accept it.
+type FastModeStep struct {
+	Command     string
+	Description string
+	Err         error
+}
+
+// FastMode applies the known optimizations for polling as many samples per
+// second as possible, verifying that the device accepts each of them:
+//
+//   - ATE0: turns off the echo of the commands
+//   - ATL0: turns off the linefeeds after each line
+//   - ATS0: turns off the spaces between the bytes of the responses
+//   - ATAT2: makes the adaptive timing aggressive, to wait as short as
+//     possible for the car to answer, left out for chips that do not support
+//     it (see QuirkNoAdaptiveTiming)
+//   - ATBRD: switches to the given baud rate, when it is not zero and the raw
+//     device supports it (see BaudRateSetter)
+//
+// The commands already tell the device how many responses to wait for (see
+// OBDCommand.ToCommand), so that it answers as soon as the car has.
+//
+// All the steps are tried even when one of them fails, and the error of the
+// first failing step is returned. The settings are lost when the device is
+// reset, except when the watchdog resets it (see EnableWatchdog), which
+// applies them again.
+func (dev *Device) FastMode(baud int) ([]FastModeStep, error) {
+	steps := []FastModeStep{
+		{Command: "ATE0", Description: "echo off"},
+		{Command: "ATL0", Description: "linefeeds off"},
+		{Command: "ATS0", Description: "spaces off"},
+	}
+
+	if !dev.HasQuirk(QuirkNoAdaptiveTiming) {
+		steps = append(steps, FastModeStep{Command: "ATAT2", Description: "aggressive adaptive timing"})
+	}
+
+	for i := range steps {
+		steps[i].Err = dev.runSetting(steps[i].Command)
+	}
+
+	if baud != 0 {
+		step := FastModeStep{
+			Command:     "ATBRD",
+			Description: fmt.Sprintf("baud rate %d", baud),
+		}
+
+		raw := unwrapDevice(dev.rawDevice)
+
+		if setter, ok := raw.(BaudRateSetter); ok {
+			step.Err = setter.SetBaudRate(baud)
+		} else {
+			step.Err = fmt.Errorf("device does not support changing the baud rate: %T", raw)
+		}
+
+		steps = append(steps, step)
+	}
+
+	for _, step := range steps {
+		if step.Err != nil {
+			return steps, fmt.Errorf("failed to apply %s (%s): %w", step.Command, step.Description, step.Err)
+		}
+	}
+
+	return steps, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// runSetting runs the given AT command and checks that the device accepted
+// it by answering OK.
+func (dev *Device) runSetting(command string) error {
+	rawRes := dev.runCommand(command)
+
+	if rawRes.Failed() {
+		return rawRes.GetError()
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	if hasOutput(rawRes.GetOutputs(), "OK") {
+		if wd, ok := dev.rawDevice.(*watchdogDevice); ok {
+			wd.keepSetting(command)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("device did not accept %s: %q", command, rawRes.GetOutputs())
+}
+
+// hasOutput checks if one of the given outputs starts with the given answer.
+func hasOutput(outputs []string, answer string) bool {
+	for _, out := range outputs {
+		if strings.HasPrefix(out, answer) {
+			return true
+		}
+	}
+
+	return false
+}
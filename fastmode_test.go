@@ -0,0 +1,64 @@
+package elmobd
+
+import (
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestFastMode(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	steps, err := dev.FastMode(0)
+
+	assertSuccess(t, err)
+	assertEqual(t, len(steps), 4)
+	assertEqual(t, steps[2].Command, "ATS0")
+
+	steps, err = dev.FastMode(115200)
+
+	assert(t, err != nil, "Expected baud rate without serial port to fail")
+	assertEqual(t, len(steps), 5)
+	assertSuccess(t, steps[3].Err)
+	assert(t, steps[4].Err != nil, "Expected baud rate step to fail")
+}
+
+func TestFastModeStepRejected(t *testing.T) {
+	raw := &scriptedDevice{outputs: map[string][]string{
+		"ATAT2": {"?"},
+	}}
+	dev := &Device{rawDevice: raw}
+
+	steps, err := dev.FastMode(0)
+
+	assert(t, err != nil, "Expected rejected step to fail")
+	assertEqual(t, len(steps), 4)
+	assertSuccess(t, steps[0].Err)
+	assert(t, steps[3].Err != nil, "Expected ATAT2 to fail")
+}
+
+func TestParseOBDResponseWithoutSpaces(t *testing.T) {
+	result, err := parseOBDResponse(NewVehicleSpeed(), []string{"410D4B"})
+
+	assertSuccess(t, err)
+	assertEqual(t, len(result.value), 3)
+
+	cmd := assertOBDParseSuccess(
+		t,
+		NewIntakeAirTemperatureSensors(),
+		[]string{"009", "0:41683F505152", "1:53545500000000"},
+	).(*IntakeAirTemperatureSensors)
+
+	assertEqual(t, cmd.Bank1[0].Value, float64(0x50-40))
+
+	codes, err := parseTroubleCodes(SERVICE_03_ID, []string{"4302013302 34"})
+
+	assert(t, err != nil, "Expected mixed spacing to fail")
+
+	codes, err = parseTroubleCodes(SERVICE_03_ID, []string{"430201330234"})
+
+	assertSuccess(t, err)
+	assertEqual(t, len(codes), 2)
+}
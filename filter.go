@@ -0,0 +1,238 @@
+package elmobd
+
+import (
+	"sort"
+	"sync"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Filter represents a smoothing filter for a series of values. Each value is
+// given to Apply, which returns the smoothed value.
+type Filter interface {
+	Apply(val float64) float64
+	Reset()
+}
+
+// MovingAverage is a Filter that smooths values by averaging the last N
+// values.
+type MovingAverage struct {
+	window []float64
+	next   int
+	sum    float64
+}
+
+// NewMovingAverage creates a new MovingAverage over the last n values.
+func NewMovingAverage(n int) *MovingAverage {
+	if n < 1 {
+		n = 1
+	}
+
+	return &MovingAverage{window: make([]float64, 0, n)}
+}
+
+// Apply adds the value to the window and returns the average of the window.
+func (filter *MovingAverage) Apply(val float64) float64 {
+	if len(filter.window) < cap(filter.window) {
+		filter.window = append(filter.window, val)
+	} else {
+		filter.sum -= filter.window[filter.next]
+		filter.window[filter.next] = val
+		filter.next = (filter.next + 1) % len(filter.window)
+	}
+
+	filter.sum += val
+
+	return filter.sum / float64(len(filter.window))
+}
+
+// Reset empties the window.
+func (filter *MovingAverage) Reset() {
+	filter.window = filter.window[:0]
+	filter.next = 0
+	filter.sum = 0
+}
+
+// ExponentialSmoothing is a Filter that smooths values using an exponential
+// moving average, where Alpha (0 to 1) is the weight of the newest value.
+// A low Alpha gives a smoother but slower result.
+type ExponentialSmoothing struct {
+	Alpha   float64
+	current float64
+	started bool
+}
+
+// NewExponentialSmoothing creates a new ExponentialSmoothing with the given
+// weight of the newest value.
+func NewExponentialSmoothing(alpha float64) *ExponentialSmoothing {
+	return &ExponentialSmoothing{Alpha: alpha}
+}
+
+// Apply mixes the value into the average and returns the average. The first
+// value is returned as it is.
+func (filter *ExponentialSmoothing) Apply(val float64) float64 {
+	if !filter.started {
+		filter.current = val
+		filter.started = true
+	} else {
+		filter.current = filter.Alpha*val + (1-filter.Alpha)*filter.current
+	}
+
+	return filter.current
+}
+
+// Reset forgets the average.
+func (filter *ExponentialSmoothing) Reset() {
+	filter.current = 0
+	filter.started = false
+}
+
+// MedianFilter is a Filter that returns the median of the last N values,
+// which removes single spikes completely instead of averaging them in.
+type MedianFilter struct {
+	window []float64
+	next   int
+	sorted []float64
+}
+
+// NewMedianFilter creates a new MedianFilter over the last n values.
+func NewMedianFilter(n int) *MedianFilter {
+	if n < 1 {
+		n = 1
+	}
+
+	return &MedianFilter{
+		window: make([]float64, 0, n),
+		sorted: make([]float64, 0, n),
+	}
+}
+
+// Apply adds the value to the window and returns the median of the window.
+// When the window has an even amount of values the mean of the two middle
+// values is returned.
+func (filter *MedianFilter) Apply(val float64) float64 {
+	if len(filter.window) < cap(filter.window) {
+		filter.window = append(filter.window, val)
+	} else {
+		filter.window[filter.next] = val
+		filter.next = (filter.next + 1) % len(filter.window)
+	}
+
+	filter.sorted = append(filter.sorted[:0], filter.window...)
+	sort.Float64s(filter.sorted)
+
+	mid := len(filter.sorted) / 2
+
+	if len(filter.sorted)%2 == 0 {
+		return (filter.sorted[mid-1] + filter.sorted[mid]) / 2
+	}
+
+	return filter.sorted[mid]
+}
+
+// Reset empties the window.
+func (filter *MedianFilter) Reset() {
+	filter.window = filter.window[:0]
+	filter.next = 0
+}
+
+// Smoother smooths the values of processed commands, keeping one filter per
+// command key so that the same Smoother can be used for all commands polled.
+//
+// Commands with floating point values (commands embedding FloatCommand) are
+// smoothed, and so are the floating point fields of the oxygen sensor
+// commands (O2SensorVoltage, O2SensorLambdaVoltage and O2SensorLambdaCurrent),
+// each field with its own filter. Other commands are passed through
+// untouched.
+//
+// A Smoother is safe to use from multiple goroutines.
+type Smoother struct {
+	mutex     sync.Mutex
+	newFilter func() Filter
+	filters   map[string]Filter
+}
+
+// NewSmoother creates a new Smoother that uses the given function to create
+// the filter of each command key, such as:
+//
+//	smoother := NewSmoother(func() Filter {
+//		return NewMovingAverage(5)
+//	})
+func NewSmoother(newFilter func() Filter) *Smoother {
+	return &Smoother{
+		newFilter: newFilter,
+		filters:   map[string]Filter{},
+	}
+}
+
+// Smooth runs the value of the given processed command through the filter of
+// its key and replaces the value of the command with the smoothed value.
+func (smoother *Smoother) Smooth(cmd OBDCommand) OBDCommand {
+	smoother.mutex.Lock()
+	defer smoother.mutex.Unlock()
+
+	switch typedCmd := cmd.(type) {
+	case floatCommand:
+		filter := smoother.filter(cmd.Key())
+
+		typedCmd.SetFloat64(filter.Apply(typedCmd.Float64()))
+	case floatFieldsCommand:
+		for name, field := range typedCmd.floatFields() {
+			filter := smoother.filter(cmd.Key() + "." + name)
+
+			*field = filter.Apply(*field)
+		}
+	}
+
+	return cmd
+}
+
+// Wrap returns a callback that smooths each processed command before giving
+// it to the given callback.
+func (smoother *Smoother) Wrap(callback func(OBDCommand)) func(OBDCommand) {
+	return func(cmd OBDCommand) {
+		callback(smoother.Smooth(cmd))
+	}
+}
+
+// Reset resets the filters of all command keys.
+func (smoother *Smoother) Reset() {
+	smoother.mutex.Lock()
+	defer smoother.mutex.Unlock()
+
+	for _, filter := range smoother.filters {
+		filter.Reset()
+	}
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// floatCommand is implemented by all commands embedding FloatCommand.
+type floatCommand interface {
+	Float64() float64
+	SetFloat64(val float64)
+}
+
+// floatFieldsCommand is implemented by commands with several floating point
+// values, such as O2SensorVoltage.
+type floatFieldsCommand interface {
+	// floatFields returns the fields holding valid values by name.
+	floatFields() map[string]*float64
+}
+
+// filter returns the filter of the given key, creating it when missing. The
+// mutex of the smoother must be held.
+func (smoother *Smoother) filter(key string) Filter {
+	filter, ok := smoother.filters[key]
+
+	if !ok {
+		filter = smoother.newFilter()
+		smoother.filters[key] = filter
+	}
+
+	return filter
+}
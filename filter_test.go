@@ -0,0 +1,137 @@
+package elmobd
+
+import (
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func applyAll(filter Filter, values []float64) []float64 {
+	var result []float64
+
+	for _, val := range values {
+		result = append(result, filter.Apply(val))
+	}
+
+	return result
+}
+
+func assertFloatsEqual(t *testing.T, a []float64, b []float64) {
+	assertEqual(t, len(a), len(b))
+
+	for i := range a {
+		assertAlmostEqual(t, a[i], b[i])
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	filter := NewMovingAverage(3)
+
+	assertFloatsEqual(
+		t,
+		applyAll(filter, []float64{3, 6, 9, 12, 0}),
+		[]float64{3, 4.5, 6, 9, 7},
+	)
+
+	filter.Reset()
+
+	assertFloatsEqual(t, applyAll(filter, []float64{10}), []float64{10})
+}
+
+func TestExponentialSmoothing(t *testing.T) {
+	filter := NewExponentialSmoothing(0.5)
+
+	assertFloatsEqual(
+		t,
+		applyAll(filter, []float64{10, 20, 20, 0}),
+		[]float64{10, 15, 17.5, 8.75},
+	)
+}
+
+func TestMedianFilter(t *testing.T) {
+	filter := NewMedianFilter(3)
+
+	assertFloatsEqual(
+		t,
+		applyAll(filter, []float64{5, 1, 100, 6, 7}),
+		[]float64{5, 3, 5, 6, 7},
+	)
+}
+
+func TestSmootherPerKey(t *testing.T) {
+	smoother := NewSmoother(func() Filter {
+		return NewMovingAverage(2)
+	})
+
+	var received []OBDCommand
+
+	callback := smoother.Wrap(func(cmd OBDCommand) {
+		received = append(received, cmd)
+	})
+
+	maf := NewMafAirFlowRate()
+	rpm := NewEngineRPM()
+
+	maf.SetFloat64(10)
+	callback(maf)
+
+	rpm.SetFloat64(1000)
+	callback(rpm)
+
+	maf.SetFloat64(20)
+	callback(maf)
+
+	assertEqual(t, maf.Float64(), 15.0)
+	assertEqual(t, rpm.Float64(), 1000.0)
+	assertEqual(t, len(received), 3)
+
+	// Commands without floating point values are not smoothed
+	speed := NewVehicleSpeed()
+	speed.Value = 80
+
+	assertEqual(t, smoother.Smooth(speed).(*VehicleSpeed).Value, uint32(80))
+}
+
+func TestSmootherOxygenSensor(t *testing.T) {
+	smoother := NewSmoother(func() Filter {
+		return NewMovingAverage(2)
+	})
+
+	o2Sensor := NewO2SensorVoltage(1, 1)
+
+	o2Sensor.Voltage = 0.2
+	o2Sensor.ShortTermFuelTrim = 4
+	o2Sensor.UsedForTrim = true
+	smoother.Smooth(o2Sensor)
+
+	o2Sensor.Voltage = 0.8
+	o2Sensor.ShortTermFuelTrim = -2
+	smoother.Smooth(o2Sensor)
+
+	assertAlmostEqual(t, o2Sensor.Voltage, 0.5)
+	assertAlmostEqual(t, o2Sensor.ShortTermFuelTrim, 1)
+
+	// The fuel trim of a sensor not used for the trim is not smoothed
+	o2Sensor.Voltage = 0.8
+	o2Sensor.ShortTermFuelTrim = 0
+	o2Sensor.UsedForTrim = false
+	smoother.Smooth(o2Sensor)
+
+	assertAlmostEqual(t, o2Sensor.Voltage, 0.8)
+	assertEqual(t, o2Sensor.ShortTermFuelTrim, 0.0)
+
+	wideband := NewO2SensorLambdaCurrent(1, 1)
+
+	wideband.Lambda = 1
+	wideband.Current = 0.5
+	smoother.Smooth(wideband)
+
+	wideband.Lambda = 0.9
+	wideband.Current = -0.5
+	smoother.Smooth(wideband)
+
+	assertAlmostEqual(t, wideband.Lambda, 0.95)
+	assertAlmostEqual(t, wideband.Current, 0)
+}
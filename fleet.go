@@ -0,0 +1,248 @@
+package elmobd
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// DeviceReading represents a reading polled by a Manager, tagged with the ID
+// of the device it was read from. When the command failed Err is set and
+// Reading only contains the key of the command.
+type DeviceReading struct {
+	DeviceID string
+	Reading  Reading
+	Err      error
+}
+
+// managerReadingsBuffer is the size of the buffer of the channel returned by
+// Manager.Readings.
+const managerReadingsBuffer = 64
+
+// Manager owns several devices, such as adapters connected to different
+// vehicles or buses, and polls them concurrently. The readings of all devices
+// are multiplexed into one channel, see Readings.
+//
+// Each device is polled in its own goroutine, one command after the other,
+// so a slow or failing device does not hold back the other devices.
+//
+// A Manager is safe to use from multiple goroutines.
+type Manager struct {
+	mutex    sync.Mutex
+	devices  map[string]*managedDevice
+	readings chan DeviceReading
+	closed   bool
+	now      func() time.Time
+}
+
+// NewManager creates a new Manager without any devices.
+func NewManager() *Manager {
+	return &Manager{
+		devices:  map[string]*managedDevice{},
+		readings: make(chan DeviceReading, managerReadingsBuffer),
+		now:      time.Now,
+	}
+}
+
+// Add adds the given device with the given ID and starts polling the
+// commands with the given keys (see GetCommandKeys), waiting the given
+// interval between each round of polling.
+//
+// The Manager takes ownership of the device, which is closed when it is
+// removed or when the Manager is closed.
+func (man *Manager) Add(id string, dev *Device, keys []string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("polling interval must be positive, got %s", interval)
+	}
+
+	var commands []OBDCommand
+
+	for _, key := range keys {
+		cmd, ok := NewCommandByKey(key)
+
+		if !ok {
+			return fmt.Errorf("unknown command key: %q", key)
+		}
+
+		commands = append(commands, cmd)
+	}
+
+	man.mutex.Lock()
+	defer man.mutex.Unlock()
+
+	if man.closed {
+		return fmt.Errorf("manager is closed")
+	}
+
+	if _, ok := man.devices[id]; ok {
+		return fmt.Errorf("device already added: %q", id)
+	}
+
+	managed := &managedDevice{
+		id:       id,
+		dev:      dev,
+		commands: commands,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	man.devices[id] = managed
+
+	go man.poll(managed)
+
+	return nil
+}
+
+// Remove stops polling the device with the given ID and closes it.
+func (man *Manager) Remove(id string) error {
+	man.mutex.Lock()
+
+	managed, ok := man.devices[id]
+
+	if ok {
+		delete(man.devices, id)
+	}
+
+	man.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("unknown device: %q", id)
+	}
+
+	return managed.shutdown()
+}
+
+// Device returns the device with the given ID, the second return value is
+// false if there is no such device.
+func (man *Manager) Device(id string) (*Device, bool) {
+	man.mutex.Lock()
+	defer man.mutex.Unlock()
+
+	managed, ok := man.devices[id]
+
+	if !ok {
+		return nil, false
+	}
+
+	return managed.dev, true
+}
+
+// IDs returns the IDs of the devices, sorted alphabetically.
+func (man *Manager) IDs() []string {
+	man.mutex.Lock()
+	defer man.mutex.Unlock()
+
+	ids := make([]string, 0, len(man.devices))
+
+	for id := range man.devices {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Readings returns the channel receiving the readings of all devices. The
+// channel is closed when the Manager is closed.
+//
+// Polling of a device blocks while the channel is full, so the channel has to
+// be drained for the devices to be polled.
+func (man *Manager) Readings() <-chan DeviceReading {
+	return man.readings
+}
+
+// Close stops polling and closes all devices, then closes the channel
+// returned by Readings. The first error closing a device is returned.
+func (man *Manager) Close() error {
+	man.mutex.Lock()
+
+	if man.closed {
+		man.mutex.Unlock()
+
+		return nil
+	}
+
+	man.closed = true
+	devices := man.devices
+	man.devices = map[string]*managedDevice{}
+
+	man.mutex.Unlock()
+
+	var firstErr error
+
+	for _, managed := range devices {
+		if err := managed.shutdown(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	close(man.readings)
+
+	return firstErr
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// managedDevice keeps track of the polling goroutine of a device.
+type managedDevice struct {
+	id       string
+	dev      *Device
+	commands []OBDCommand
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// shutdown stops the polling goroutine, waits for it to finish and closes the
+// device.
+func (managed *managedDevice) shutdown() error {
+	close(managed.stop)
+	<-managed.done
+
+	return managed.dev.Close()
+}
+
+func (man *Manager) poll(managed *managedDevice) {
+	defer close(managed.done)
+
+	ticker := time.NewTicker(managed.interval)
+
+	defer ticker.Stop()
+
+	for {
+		for _, cmd := range managed.commands {
+			_, err := managed.dev.RunOBDCommand(cmd)
+
+			result := DeviceReading{
+				DeviceID: managed.id,
+				Reading:  Reading{Key: cmd.Key(), Time: man.now()},
+				Err:      err,
+			}
+
+			if err == nil {
+				result.Reading = NewReading(cmd, result.Reading.Time)
+			}
+
+			select {
+			case man.readings <- result:
+			case <-managed.stop:
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-managed.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package elmobd
+
+import (
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestManagerMultiplexesReadings(t *testing.T) {
+	man := NewManager()
+
+	for _, id := range []string{"truck-1", "truck-2"} {
+		dev := &Device{rawDevice: &MockDevice{}}
+		err := man.Add(id, dev, []string{"engine_rpm", "vehicle_speed"}, time.Millisecond)
+
+		assertSuccess(t, err)
+	}
+
+	assertEqual(t, len(man.IDs()), 2)
+	assertEqual(t, man.IDs()[0], "truck-1")
+
+	seen := map[string]int{}
+
+	for len(seen) < 4 {
+		res := <-man.Readings()
+
+		assertSuccess(t, res.Err)
+
+		seen[res.DeviceID+"/"+res.Reading.Key]++
+	}
+
+	assertEqual(t, seen["truck-2/engine_rpm"] > 0, true)
+	assertEqual(t, seen["truck-1/vehicle_speed"] > 0, true)
+
+	assertSuccess(t, man.Close())
+
+	for range man.Readings() {
+	}
+
+	assertEqual(t, len(man.IDs()), 0)
+}
+
+func TestManagerAddRemove(t *testing.T) {
+	man := NewManager()
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	defer man.Close()
+
+	assert(t, man.Add("car", dev, []string{"no_such_command"}, time.Second) != nil, "Expected unknown key to fail")
+	assert(t, man.Add("car", dev, []string{"engine_rpm"}, 0) != nil, "Expected zero interval to fail")
+	assertSuccess(t, man.Add("car", dev, []string{"engine_rpm"}, time.Second))
+	assert(t, man.Add("car", dev, []string{"engine_rpm"}, time.Second) != nil, "Expected duplicate ID to fail")
+
+	found, ok := man.Device("car")
+
+	assertEqual(t, ok, true)
+	assert(t, found == dev, "Expected the added device")
+
+	<-man.Readings()
+
+	assertSuccess(t, man.Remove("car"))
+	assert(t, man.Remove("car") != nil, "Expected removing twice to fail")
+
+	_, ok = man.Device("car")
+
+	assertEqual(t, ok, false)
+}
@@ -0,0 +1,256 @@
+package elmobd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// FreezeFrame represents the values of the sensors that the car stored when
+// a trouble code was set (service 02).
+type FreezeFrame struct {
+	// Frame is the number of the freeze frame, most cars only store frame 0.
+	Frame byte `json:"frame"`
+	// TroubleCode is the code that caused the frame to be stored.
+	TroubleCode TroubleCode `json:"trouble_code"`
+	// Readings are the stored values of the sensors, timestamped with the
+	// time the frame was read.
+	Readings []Reading `json:"readings"`
+}
+
+// DiagnosticEntry represents a trouble code along with its freeze frame, if
+// the car stored one for it.
+type DiagnosticEntry struct {
+	TroubleCode TroubleCode  `json:"trouble_code"`
+	FreezeFrame *FreezeFrame `json:"freeze_frame,omitempty"`
+}
+
+// DiagnosticReport represents the trouble codes of the car with their freeze
+// frames, see GetDiagnosticReport.
+type DiagnosticReport struct {
+	Schema  int               `json:"schema"`
+	Time    time.Time         `json:"timestamp"`
+	Entries []DiagnosticEntry `json:"trouble_codes"`
+}
+
+// GetDiagnosticReport reads the stored trouble codes (service 03) and the
+// freeze frames (service 02) of the car, bundling each code with the frame
+// stored for it. This is what scan tools do when pulling the diagnostics.
+//
+// Of each freeze frame the sensors with a command defined in this library are
+// read, the sensors the frame does not support or that can not be read are
+// left out. Codes of freeze frames that are not among the stored trouble codes
+// (such as pending codes) are added to the report as well.
+func (dev *Device) GetDiagnosticReport() (*DiagnosticReport, error) {
+	codes, err := dev.GetTroubleCodes()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trouble codes: %w", err)
+	}
+
+	now := time.Now()
+	frames := map[TroubleCode]*FreezeFrame{}
+	report := &DiagnosticReport{
+		Schema:  SchemaVersion,
+		Time:    now,
+		Entries: []DiagnosticEntry{},
+	}
+
+	for frame := byte(0); frame < maxFreezeFrames; frame++ {
+		freezeFrame, err := dev.readFreezeFrame(frame, now)
+
+		if errors.Is(err, errNoFreezeFrame) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read freeze frame %d: %w", frame, err)
+		}
+
+		if _, ok := frames[freezeFrame.TroubleCode]; !ok {
+			frames[freezeFrame.TroubleCode] = freezeFrame
+		}
+	}
+
+	for _, code := range codes {
+		report.Entries = append(report.Entries, DiagnosticEntry{code, frames[code]})
+		delete(frames, code)
+	}
+
+	for frame := byte(0); frame < maxFreezeFrames; frame++ {
+		for code, freezeFrame := range frames {
+			if freezeFrame.Frame == frame {
+				report.Entries = append(report.Entries, DiagnosticEntry{code, freezeFrame})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// maxFreezeFrames is the amount of freeze frames that are read at most.
+const maxFreezeFrames = 8
+
+// errNoFreezeFrame is returned by readFreezeFrame when the car has not stored
+// the frame.
+var errNoFreezeFrame = errors.New("no freeze frame")
+
+// readFreezeFrame reads the code and the sensors of the given freeze frame.
+func (dev *Device) readFreezeFrame(frame byte, at time.Time) (*FreezeFrame, error) {
+	payload, err := dev.runFreezeFrameCommand(0x02, frame)
+
+	if errors.Is(err, ErrNoData) {
+		return nil, errNoFreezeFrame
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) < 2 {
+		return nil, fmt.Errorf("expected 2 bytes of trouble code, found %d", len(payload))
+	}
+
+	code := TroubleCode(payload[0])<<8 | TroubleCode(payload[1])
+
+	if code == 0 {
+		return nil, errNoFreezeFrame
+	}
+
+	freezeFrame := &FreezeFrame{
+		Frame:       frame,
+		TroubleCode: code,
+		Readings:    []Reading{},
+	}
+
+	commands := freezeFrameCommands()
+
+	for index := byte(1); index <= PartsAmount; index++ {
+		part := NewPartSupported(index)
+		payload, err := dev.runFreezeFrameCommand(byte(part.ParameterID()), frame)
+
+		if err != nil {
+			// Frames without support PIDs only have the code
+			break
+		}
+
+		if err := part.SetValue(&Result{freezeFrameValue(part, payload)}); err != nil {
+			break
+		}
+
+		for _, pid := range partPIDs(part) {
+			cmd, ok := commands[pid]
+
+			if pid == 0x02 || !ok {
+				continue
+			}
+
+			if reading, err := dev.readFreezeFrameValue(cmd, frame, at); err == nil {
+				freezeFrame.Readings = append(freezeFrame.Readings, reading)
+			}
+		}
+
+		if !part.SupportsNextPart() {
+			break
+		}
+	}
+
+	return freezeFrame, nil
+}
+
+// readFreezeFrameValue reads the value of the given service 01 command from
+// the given freeze frame.
+func (dev *Device) readFreezeFrameValue(cmd OBDCommand, frame byte, at time.Time) (Reading, error) {
+	payload, err := dev.runFreezeFrameCommand(byte(cmd.ParameterID()), frame)
+
+	if err != nil {
+		return Reading{}, err
+	}
+
+	result := &Result{freezeFrameValue(cmd, payload)}
+
+	if err := result.Validate(cmd); err != nil {
+		return Reading{}, err
+	}
+
+	if err := cmd.SetValue(result); err != nil {
+		return Reading{}, err
+	}
+
+	if err := dev.validateRanges(result, cmd); err != nil {
+		return Reading{}, err
+	}
+
+	return NewReading(cmd, at), nil
+}
+
+// runFreezeFrameCommand reads the given PID from the given freeze frame, and
+// returns the data of the response.
+func (dev *Device) runFreezeFrameCommand(pid byte, frame byte) ([]byte, error) {
+	rawRes := dev.runCommand(fmt.Sprintf("%02X%02X%02X", SERVICE_02_ID, pid, frame))
+
+	if rawRes.Failed() {
+		return nil, rawRes.GetError()
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	result, err := parseOBDResponse(nil, rawRes.GetOutputs())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, ErrNoData
+	}
+
+	value := result.value
+
+	// Cars not supporting service 02 may answer with a negative response
+	if len(value) > 0 && value[0] == 0x7F {
+		return nil, ErrNoData
+	}
+
+	// The response echoes the service, the PID and the frame
+	if len(value) < 3 || value[0] != SERVICE_02_ID+0x40 || value[1] != pid || value[2] != frame {
+		return nil, fmt.Errorf("unexpected freeze frame response: % X", value)
+	}
+
+	return value[3:], nil
+}
+
+// freezeFrameValue builds the value of the service 01 response of the given
+// command from the given freeze frame data, so that the command can parse
+// it.
+func freezeFrameValue(cmd OBDCommand, payload []byte) []byte {
+	return append([]byte{SERVICE_01_ID + 0x40, byte(cmd.ParameterID())}, payload...)
+}
+
+// freezeFrameCommands returns the service 01 commands defined in this
+// library, by PID.
+func freezeFrameCommands() map[OBDParameterID]OBDCommand {
+	commands := map[OBDParameterID]OBDCommand{}
+
+	for _, key := range GetCommandKeys() {
+		cmd, _ := NewCommandByKey(key)
+
+		if _, ok := commands[cmd.ParameterID()]; ok || cmd.ModeID() != SERVICE_01_ID {
+			continue
+		}
+
+		commands[cmd.ParameterID()] = cmd
+	}
+
+	return commands
+}
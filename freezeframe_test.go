@@ -0,0 +1,84 @@
+package elmobd
+
+import (
+	"fmt"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestGetDiagnosticReport(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	report, err := dev.GetDiagnosticReport()
+
+	assertSuccess(t, err)
+	assertEqual(t, report.Schema, SchemaVersion)
+	assertEqual(t, len(report.Entries), 1)
+
+	entry := report.Entries[0]
+
+	assertEqual(t, entry.TroubleCode.String(), "P0133")
+	assert(t, entry.FreezeFrame != nil, "Expected a freeze frame for P0133")
+	assertEqual(t, entry.FreezeFrame.Frame, byte(0))
+	assertEqual(t, entry.FreezeFrame.TroubleCode.String(), "P0133")
+
+	values := map[string]interface{}{}
+
+	for _, reading := range entry.FreezeFrame.Readings {
+		values[reading.Key] = reading.Value
+		assertEqual(t, reading.Time, report.Time)
+	}
+
+	assertEqual(t, fmt.Sprint(values), "map[coolant_temperature:39 engine_rpm:192 vehicle_speed:75]")
+}
+
+func TestGetDiagnosticReportWithoutFreezeFrame(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	raw.SetTroubleCodes(0x0133, 0x0300)
+	raw.setResponse("020200", "NO DATA")
+
+	report, err := dev.GetDiagnosticReport()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(report.Entries), 2)
+	assertEqual(t, report.Entries[0].TroubleCode.String(), "P0133")
+	assert(t, report.Entries[0].FreezeFrame == nil, "Expected no freeze frame")
+	assertEqual(t, report.Entries[1].TroubleCode.String(), "P0300")
+	assert(t, report.Entries[1].FreezeFrame == nil, "Expected no freeze frame")
+}
+
+func TestGetDiagnosticReportPendingCode(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	raw.SetTroubleCodes()
+	raw.setResponse("020200", "42 02 00 03 00")
+	raw.setResponse("020000", "7F 02 12")
+
+	report, err := dev.GetDiagnosticReport()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(report.Entries), 1)
+	assertEqual(t, report.Entries[0].TroubleCode.String(), "P0300")
+	assertEqual(t, len(report.Entries[0].FreezeFrame.Readings), 0)
+}
+
+func TestGetDiagnosticReportAfterClear(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	_, err := dev.ClearDTCs(func(codes []TroubleCode) bool {
+		return true
+	})
+
+	assertSuccess(t, err)
+
+	report, err := dev.GetDiagnosticReport()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(report.Entries), 0)
+}
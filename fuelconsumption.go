@@ -0,0 +1,166 @@
+package elmobd
+
+import (
+	"fmt"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// FuelProperties represents the properties of a fuel needed to calculate the
+// fuel consumption from the air flow of the engine.
+type FuelProperties struct {
+	// StoichiometricAFR is the mass of air needed to burn one mass unit of fuel
+	// completely.
+	StoichiometricAFR float64
+	// Density is the density of the fuel in grams per liter.
+	Density float64
+}
+
+// Properties of common fuels.
+var (
+	Gasoline = FuelProperties{StoichiometricAFR: 14.7, Density: 745}
+	Diesel   = FuelProperties{StoichiometricAFR: 14.5, Density: 832}
+	E85      = FuelProperties{StoichiometricAFR: 9.8, Density: 781}
+)
+
+// FuelConsumption represents the instantaneous fuel consumption of the
+// vehicle.
+//
+// LitersPer100Km is zero when the vehicle is standing still, since the
+// consumption per distance is infinite then.
+type FuelConsumption struct {
+	GramsPerSecond float64
+	LitersPerHour  float64
+	LitersPer100Km float64
+	// Source is the key of the command the fuel flow was calculated from.
+	Source string
+}
+
+// CalculateFuelConsumption calculates the fuel consumption from the mass air
+// flow (g/s), the vehicle speed (km/h) and the equivalence ratio (lambda) of
+// the engine, using the given fuel properties.
+//
+// The fuel flow is the air flow divided by the air-fuel ratio, which is the
+// stoichiometric air-fuel ratio of the fuel times lambda.
+func CalculateFuelConsumption(maf float64, speed float64, lambda float64, fuel FuelProperties) FuelConsumption {
+	if lambda <= 0 {
+		lambda = 1
+	}
+
+	gramsPerSecond := maf / (fuel.StoichiometricAFR * lambda)
+
+	result := fuelConsumptionFromFlow(gramsPerSecond, speed, fuel)
+	result.Source = NewMafAirFlowRate().Key()
+
+	return result
+}
+
+// GetFuelConsumption reads the commands needed to calculate the instantaneous
+// fuel consumption of the vehicle from the device.
+//
+// The fuel flow is read directly from the engine fuel mass rate (PID 0x9D)
+// or the engine fuel rate (PID 0x5E) when the car supports them, otherwise it
+// is calculated from the mass air flow rate (PID 0x10) and the commanded
+// equivalence ratio (PID 0x44). The Source of the result tells which of them
+// was used.
+//
+// The given supported commands are used to check that the needed PIDs are
+// available before reading them, pass nil to skip the check. When the
+// supported commands are not given, the mass air flow rate is used and a
+// stoichiometric mixture is assumed.
+func (dev *Device) GetFuelConsumption(supported *SupportedCommands, fuel FuelProperties) (FuelConsumption, error) {
+	isSupported := func(cmd OBDCommand) bool {
+		return supported == nil || supported.IsSupported(cmd)
+	}
+
+	speed := NewVehicleSpeed()
+	maf := NewMafAirFlowRate()
+
+	if !isSupported(speed) {
+		return FuelConsumption{}, fmt.Errorf("vehicle speed is not supported")
+	}
+
+	if supported != nil && supported.IsSupported(NewEngineFuelMassRate()) {
+		if _, err := dev.RunOBDCommand(speed); err != nil {
+			return FuelConsumption{}, err
+		}
+
+		rate, err := Run(dev, NewEngineFuelMassRate())
+
+		if err != nil {
+			return FuelConsumption{}, err
+		}
+
+		result := fuelConsumptionFromFlow(rate.Engine, float64(speed.Value), fuel)
+		result.Source = rate.Key()
+
+		return result, nil
+	}
+
+	if supported != nil && supported.IsSupported(NewEngineFuelRate()) {
+		if _, err := dev.RunOBDCommand(speed); err != nil {
+			return FuelConsumption{}, err
+		}
+
+		rate, err := Run(dev, NewEngineFuelRate())
+
+		if err != nil {
+			return FuelConsumption{}, err
+		}
+
+		gramsPerSecond := rate.Float64() * fuel.Density / 3600
+
+		result := fuelConsumptionFromFlow(gramsPerSecond, float64(speed.Value), fuel)
+		result.Source = rate.Key()
+
+		return result, nil
+	}
+
+	if !isSupported(maf) {
+		return FuelConsumption{}, fmt.Errorf("mass air flow rate is not supported")
+	}
+
+	if _, err := dev.RunOBDCommand(speed); err != nil {
+		return FuelConsumption{}, err
+	}
+
+	if _, err := dev.RunOBDCommand(maf); err != nil {
+		return FuelConsumption{}, err
+	}
+
+	lambda := 1.0
+
+	if supported != nil && supported.IsSupported(NewCommandedEquivalenceRatio()) {
+		ratio, err := Run(dev, NewCommandedEquivalenceRatio())
+
+		if err != nil {
+			return FuelConsumption{}, err
+		}
+
+		lambda = ratio.Float64()
+	}
+
+	return CalculateFuelConsumption(maf.Float64(), float64(speed.Value), lambda, fuel), nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// fuelConsumptionFromFlow converts the fuel flow (g/s) into the other units.
+func fuelConsumptionFromFlow(gramsPerSecond float64, speed float64, fuel FuelProperties) FuelConsumption {
+	litersPerHour := gramsPerSecond * 3600 / fuel.Density
+	litersPer100Km := 0.0
+
+	if speed > 0 {
+		litersPer100Km = litersPerHour / speed * 100
+	}
+
+	return FuelConsumption{
+		GramsPerSecond: gramsPerSecond,
+		LitersPerHour:  litersPerHour,
+		LitersPer100Km: litersPer100Km,
+	}
+}
@@ -0,0 +1,106 @@
+package elmobd
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func assertAlmostEqual(t *testing.T, a float64, b float64) {
+	assert(
+		t,
+		math.Abs(a-b) < 1e-6,
+		fmt.Sprintf("'%v ~= %v'", a, b),
+	)
+}
+
+func TestCalculateFuelConsumption(t *testing.T) {
+	// 14.7 g/s of air at stoichiometric mixture burns 1 g/s of gasoline
+	result := CalculateFuelConsumption(14.7, 100, 1, Gasoline)
+
+	assertAlmostEqual(t, result.GramsPerSecond, 1)
+	assertAlmostEqual(t, result.LitersPerHour, 3600.0/745)
+	assertAlmostEqual(t, result.LitersPer100Km, 3600.0/745)
+	assertEqual(t, result.Source, "maf_air_flow_rate")
+
+	// A rich mixture burns more fuel for the same air flow
+	rich := CalculateFuelConsumption(14.7, 100, 0.8, Gasoline)
+
+	assertAlmostEqual(t, rich.GramsPerSecond, 1.25)
+
+	standing := CalculateFuelConsumption(14.7, 0, 1, Gasoline)
+
+	assertEqual(t, standing.LitersPer100Km, 0.0)
+}
+
+func TestGetFuelConsumption(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	sc, err := NewSupportedCommands([]uint32{0x0})
+	assertSuccess(t, err)
+
+	_, err = dev.GetFuelConsumption(sc, Gasoline)
+
+	assert(t, err != nil, "unsupported PIDs fail")
+
+	result, err := dev.GetFuelConsumption(nil, Gasoline)
+	assertSuccess(t, err)
+
+	// 14.70 g/s of air at 75 km/h
+	assertAlmostEqual(t, result.GramsPerSecond, 1)
+	assertAlmostEqual(t, result.LitersPer100Km, 3600.0/745/75*100)
+}
+
+func TestGetFuelConsumptionWithLambda(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	// Vehicle speed, MAF and the commanded equivalence ratio are supported
+	sc, err := NewSupportedCommands([]uint32{0x00090001, 0x00000001, 0x10000000})
+	assertSuccess(t, err)
+
+	result, err := dev.GetFuelConsumption(sc, Gasoline)
+	assertSuccess(t, err)
+
+	lambda := float64(0x799A) * 2 / 65536
+
+	assertAlmostEqual(t, result.GramsPerSecond, 1/lambda)
+}
+
+func TestGetFuelConsumptionWithFuelRate(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	// Vehicle speed and the engine fuel rate are supported
+	sc, err := NewSupportedCommands([]uint32{0x00080001, 0x00000001, 0x00000004})
+	assertSuccess(t, err)
+
+	result, err := dev.GetFuelConsumption(sc, Diesel)
+	assertSuccess(t, err)
+
+	// 5 L/h at 75 km/h
+	assertEqual(t, result.Source, "engine_fuel_rate")
+	assertAlmostEqual(t, result.LitersPerHour, 5)
+	assertAlmostEqual(t, result.LitersPer100Km, 5.0/75*100)
+}
+
+func TestGetFuelConsumptionWithFuelMassRate(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	// Vehicle speed, the engine fuel rate and the engine fuel mass rate are
+	// supported
+	sc, err := NewSupportedCommands([]uint32{
+		0x00080001, 0x00000001, 0x00000005, 0x00000001, 0x00000008,
+	})
+	assertSuccess(t, err)
+
+	result, err := dev.GetFuelConsumption(sc, Diesel)
+	assertSuccess(t, err)
+
+	// 1 g/s at 75 km/h
+	assertEqual(t, result.Source, "engine_fuel_mass_rate")
+	assertAlmostEqual(t, result.GramsPerSecond, 1)
+	assertAlmostEqual(t, result.LitersPer100Km, 3600/Diesel.Density/75*100)
+}
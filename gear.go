@@ -0,0 +1,158 @@
+package elmobd
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// DefaultGearTolerance is the relative difference allowed between the
+// calculated gear ratio and the gear ratio of a gear when the Drivetrain has
+// no tolerance set.
+const DefaultGearTolerance = 0.1
+
+// Drivetrain represents the gearing of a vehicle, used to estimate the
+// current gear from the vehicle speed and the engine RPM.
+//
+// The ratios can usually be found in the specifications of the gearbox of the
+// vehicle, the wheel circumference can be measured or calculated from the
+// tire size.
+type Drivetrain struct {
+	// GearRatios are the ratios of the gears, starting with the first gear.
+	GearRatios []float64
+	// FinalDrive is the ratio of the final drive (differential).
+	FinalDrive float64
+	// WheelCircumference is the rolling circumference of the driven wheels in
+	// meters.
+	WheelCircumference float64
+	// Tolerance is the relative difference allowed between the calculated
+	// ratio and the ratio of a gear, DefaultGearTolerance is used when zero.
+	Tolerance float64
+}
+
+// GearForRatio finds the gear with the gear ratio closest to the given ratio.
+// Gear 0 is returned when no gear is within the tolerance, such as when the
+// clutch is pressed or the gearbox is in neutral.
+func (dt Drivetrain) GearForRatio(ratio float64) int {
+	tolerance := dt.Tolerance
+
+	if tolerance <= 0 {
+		tolerance = DefaultGearTolerance
+	}
+
+	gear := 0
+	bestDiff := math.Inf(1)
+
+	for i, gearRatio := range dt.GearRatios {
+		diff := math.Abs(ratio-gearRatio) / gearRatio
+
+		if diff <= tolerance && diff < bestDiff {
+			gear = i + 1
+			bestDiff = diff
+		}
+	}
+
+	return gear
+}
+
+// EstimateGear estimates the current gear from the given vehicle speed (km/h)
+// and engine RPM. Gear 0 is returned when the vehicle is standing still or no
+// gear matches.
+func (dt Drivetrain) EstimateGear(speed float64, rpm float64) int {
+	if speed <= 0 || rpm <= 0 || dt.FinalDrive <= 0 || dt.WheelCircumference <= 0 {
+		return 0
+	}
+
+	wheelRPM := speed * 1000 / 60 / dt.WheelCircumference
+	ratio := rpm / wheelRPM / dt.FinalDrive
+
+	return dt.GearForRatio(ratio)
+}
+
+// GetGear reads the vehicle speed and engine RPM from the device and
+// estimates the current gear using the given drivetrain.
+func (dev *Device) GetGear(dt Drivetrain) (int, error) {
+	if len(dt.GearRatios) == 0 {
+		return 0, fmt.Errorf("drivetrain has no gear ratios")
+	}
+
+	speed := NewVehicleSpeed()
+	rpm := NewEngineRPM()
+
+	if _, err := dev.RunOBDCommand(speed); err != nil {
+		return 0, err
+	}
+
+	if _, err := dev.RunOBDCommand(rpm); err != nil {
+		return 0, err
+	}
+
+	return dt.EstimateGear(float64(speed.Value), rpm.Float64()), nil
+}
+
+// GearEstimator keeps track of the latest vehicle speed and engine RPM seen
+// and estimates the gear from them, for use with a loop or callback that
+// polls the device continuously.
+//
+// A GearEstimator is safe to use from multiple goroutines.
+type GearEstimator struct {
+	mutex    sync.Mutex
+	dt       Drivetrain
+	speed    float64
+	rpm      float64
+	gear     int
+	onChange func(gear int)
+}
+
+// NewGearEstimator creates a new GearEstimator using the given drivetrain.
+// The given callback is called each time the estimated gear changes, it can be
+// nil.
+func NewGearEstimator(dt Drivetrain, onChange func(gear int)) *GearEstimator {
+	return &GearEstimator{dt: dt, onChange: onChange}
+}
+
+// Observe updates the estimate with the given processed command. Commands
+// other than VehicleSpeed, EngineRPM and TransmissionActualGear are ignored.
+// When the car reports the actual gear ratio, it is used directly instead of
+// calculating the ratio from the speed and RPM.
+//
+// The current estimate is returned.
+func (est *GearEstimator) Observe(cmd OBDCommand) int {
+	est.mutex.Lock()
+
+	gear := est.gear
+
+	switch val := cmd.(type) {
+	case *VehicleSpeed:
+		est.speed = float64(val.Value)
+		gear = est.dt.EstimateGear(est.speed, est.rpm)
+	case *EngineRPM:
+		est.rpm = val.Float64()
+		gear = est.dt.EstimateGear(est.speed, est.rpm)
+	case *TransmissionActualGear:
+		gear = est.dt.GearForRatio(val.Float64())
+	}
+
+	changed := gear != est.gear
+	est.gear = gear
+
+	est.mutex.Unlock()
+
+	if changed && est.onChange != nil {
+		est.onChange(gear)
+	}
+
+	return gear
+}
+
+// Gear retrieves the current estimate.
+func (est *GearEstimator) Gear() int {
+	est.mutex.Lock()
+	defer est.mutex.Unlock()
+
+	return est.gear
+}
@@ -0,0 +1,75 @@
+package elmobd
+
+import (
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+var testDrivetrain = Drivetrain{
+	GearRatios:         []float64{3.5, 2.0, 1.3, 1.0, 0.8},
+	FinalDrive:         4.0,
+	WheelCircumference: 2.0,
+}
+
+func TestDrivetrainEstimateGear(t *testing.T) {
+	type scenario struct {
+		speed float64
+		rpm   float64
+		gear  int
+	}
+
+	// At 60 km/h the wheels spin 500 RPM, which is 2000 RPM at the gearbox
+	scenarios := []scenario{
+		{60, 7000, 1},
+		{60, 4000, 2},
+		{60, 2600, 3},
+		{60, 2000, 4},
+		{60, 1650, 5},
+		{60, 800, 0},
+		{0, 800, 0},
+	}
+
+	for _, scen := range scenarios {
+		assertEqual(t, testDrivetrain.EstimateGear(scen.speed, scen.rpm), scen.gear)
+	}
+}
+
+func TestGearEstimatorObserve(t *testing.T) {
+	var changes []int
+
+	est := NewGearEstimator(testDrivetrain, func(gear int) {
+		changes = append(changes, gear)
+	})
+
+	speed := NewVehicleSpeed()
+	speed.Value = 60
+
+	rpm := NewEngineRPM()
+	rpm.Value = 4000
+
+	est.Observe(speed)
+	assertEqual(t, est.Observe(rpm), 2)
+
+	rpm.Value = 2000
+	assertEqual(t, est.Observe(rpm), 4)
+
+	ratio := NewTransmissionActualGear()
+	ratio.Value = 1.3
+	assertEqual(t, est.Observe(ratio), 3)
+
+	assertEqual(t, len(changes), 3)
+	assertEqual(t, est.Gear(), 3)
+}
+
+func TestDeviceGetGear(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	// The mock answers 75 km/h and 192 RPM, which is too low for any gear
+	gear, err := dev.GetGear(testDrivetrain)
+
+	assertSuccess(t, err)
+	assertEqual(t, gear, 0)
+}
@@ -1,6 +1,6 @@
 module github.com/rzetterberg/elmobd
 
-go 1.13
+go 1.18
 
 //replace github.com/rzetterberg/elmobd => github.com/samifruit514/elmobd master
 
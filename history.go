@@ -0,0 +1,192 @@
+package elmobd
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// History keeps the latest readings of each command key in memory, such as
+// for rendering sparklines of the values in a UI.
+//
+// Each key has its own ring buffer holding the given amount of readings,
+// which means that the oldest reading of a key is dropped when a new reading
+// is added to a full buffer.
+//
+// A History is safe to use from multiple goroutines.
+type History struct {
+	mutex sync.RWMutex
+	size  int
+	rings map[string]*readingRing
+	now   func() time.Time
+}
+
+// NewHistory creates a new History keeping the given amount of readings per
+// command key.
+func NewHistory(size int) *History {
+	if size < 1 {
+		size = 1
+	}
+
+	return &History{
+		size:  size,
+		rings: map[string]*readingRing{},
+		now:   time.Now,
+	}
+}
+
+// Record adds the value of the given processed command, timestamped with the
+// current time.
+func (hist *History) Record(cmd OBDCommand) {
+	hist.Add(NewReading(cmd, hist.now()))
+}
+
+// Add adds the given reading to the buffer of its key.
+func (hist *History) Add(reading Reading) {
+	hist.mutex.Lock()
+	defer hist.mutex.Unlock()
+
+	ring, ok := hist.rings[reading.Key]
+
+	if !ok {
+		ring = &readingRing{readings: make([]Reading, 0, hist.size)}
+		hist.rings[reading.Key] = ring
+	}
+
+	ring.add(reading)
+}
+
+// Keys returns the keys that have readings, sorted alphabetically.
+func (hist *History) Keys() []string {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	keys := make([]string, 0, len(hist.rings))
+
+	for key := range hist.rings {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Len returns the amount of readings kept of the given key.
+func (hist *History) Len(key string) int {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	if ring, ok := hist.rings[key]; ok {
+		return len(ring.readings)
+	}
+
+	return 0
+}
+
+// Each calls the given function with the readings of the given key, oldest
+// first, until the function returns false. The function is called with a copy
+// of the readings, so it may add readings to the History.
+func (hist *History) Each(key string, fn func(Reading) bool) {
+	for _, reading := range hist.Readings(key) {
+		if !fn(reading) {
+			return
+		}
+	}
+}
+
+// Readings returns a copy of the readings of the given key, oldest first.
+func (hist *History) Readings(key string) []Reading {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	ring, ok := hist.rings[key]
+
+	if !ok {
+		return nil
+	}
+
+	amount := len(ring.readings)
+	result := make([]Reading, 0, amount)
+
+	for i := 0; i < amount; i++ {
+		result = append(result, ring.readings[(ring.start+i)%amount])
+	}
+
+	return result
+}
+
+// Since returns the readings of the given key taken at or after the given
+// time, oldest first.
+func (hist *History) Since(key string, since time.Time) []Reading {
+	var result []Reading
+
+	hist.Each(key, func(reading Reading) bool {
+		if !reading.Time.Before(since) {
+			result = append(result, reading)
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// Latest returns the newest reading of the given key, the second return value
+// is false if there are no readings of the key.
+func (hist *History) Latest(key string) (Reading, bool) {
+	hist.mutex.RLock()
+	defer hist.mutex.RUnlock()
+
+	ring, ok := hist.rings[key]
+
+	if !ok || len(ring.readings) == 0 {
+		return Reading{}, false
+	}
+
+	last := (ring.start + len(ring.readings) - 1) % len(ring.readings)
+
+	return ring.readings[last], true
+}
+
+// Values returns the numeric values of the readings of the given key, oldest
+// first. Readings without a numeric value are left out.
+func (hist *History) Values(key string) []float64 {
+	var result []float64
+
+	hist.Each(key, func(reading Reading) bool {
+		if val, ok := reading.Float64(); ok {
+			result = append(result, val)
+		}
+
+		return true
+	})
+
+	return result
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// readingRing is a ring buffer of readings, where start is the index of the
+// oldest reading once the buffer is full.
+type readingRing struct {
+	readings []Reading
+	start    int
+}
+
+func (ring *readingRing) add(reading Reading) {
+	if len(ring.readings) < cap(ring.readings) {
+		ring.readings = append(ring.readings, reading)
+
+		return
+	}
+
+	ring.readings[ring.start] = reading
+	ring.start = (ring.start + 1) % len(ring.readings)
+}
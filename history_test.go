@@ -0,0 +1,69 @@
+package elmobd
+
+import (
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestHistoryRingBuffer(t *testing.T) {
+	hist := NewHistory(3)
+	start := time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC)
+	at := start
+
+	hist.now = func() time.Time {
+		at = at.Add(time.Second)
+
+		return at
+	}
+
+	speed := NewVehicleSpeed()
+
+	for _, val := range []uint32{10, 20, 30, 40, 50} {
+		speed.Value = val
+		hist.Record(speed)
+	}
+
+	hist.Record(NewEngineRPM())
+
+	assertEqual(t, len(hist.Keys()), 2)
+	assertEqual(t, hist.Keys()[0], "engine_rpm")
+	assertEqual(t, hist.Len("vehicle_speed"), 3)
+	assertFloatsEqual(t, hist.Values("vehicle_speed"), []float64{30, 40, 50})
+
+	latest, ok := hist.Latest("vehicle_speed")
+
+	assertEqual(t, ok, true)
+	assertEqual(t, latest.Value, uint32(50))
+
+	since := hist.Since("vehicle_speed", start.Add(4*time.Second))
+
+	assertEqual(t, len(since), 2)
+	assertEqual(t, since[0].Value, uint32(40))
+
+	_, ok = hist.Latest("fuel")
+
+	assertEqual(t, ok, false)
+	assertEqual(t, len(hist.Readings("fuel")), 0)
+}
+
+func TestHistoryEachAddsReadings(t *testing.T) {
+	hist := NewHistory(10)
+
+	hist.Add(Reading{Key: "vehicle_speed", Value: 36.0})
+	hist.Add(Reading{Key: "vehicle_speed", Value: 72.0})
+
+	// Deriving a value while iterating must not deadlock
+	hist.Each("vehicle_speed", func(reading Reading) bool {
+		kmh, _ := reading.Float64()
+
+		hist.Add(Reading{Key: "vehicle_speed_ms", Value: kmh / 3.6})
+
+		return true
+	})
+
+	assertFloatsEqual(t, hist.Values("vehicle_speed_ms"), []float64{10, 20})
+}
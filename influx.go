@@ -0,0 +1,335 @@
+package elmobd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// InfluxWriter writes processed commands as InfluxDB line protocol to an
+// io.Writer, such as a file, stdout or an InfluxHTTPEndpoint.
+//
+// Each command becomes its own measurement named after the key of the
+// command, with the value in the field "value" and the unit (if any) as the
+// tag "unit". The tags given when creating the writer, such as the VIN of the
+// car or the name of the device, are added to all lines:
+//
+//	engine_rpm,device=elm1,unit=rpm,vin=ABC123 value=2412.5 1662638400000000000
+//
+// Commands with a struct value, such as O2SensorVoltage, get one field per
+// struct field instead, named as in the JSON of the value:
+//
+//	o2_sensor_voltage_bank1_sensor1 short_term_fuel_trim=3.125,used_for_trim=true,voltage=0.45 1662638400000000000
+//
+// Commands with a value that is NaN or infinite are left out, since InfluxDB
+// rejects the whole batch when one of the lines contains such a value.
+type InfluxWriter struct {
+	writer io.Writer
+	tags   map[string]string
+	now    func() time.Time
+}
+
+// NewInfluxWriter creates a new InfluxWriter that writes to the given writer
+// adding the given tags to each line.
+func NewInfluxWriter(writer io.Writer, tags map[string]string) *InfluxWriter {
+	return &InfluxWriter{
+		writer: writer,
+		tags:   tags,
+		now:    time.Now,
+	}
+}
+
+// Write writes the value of the given processed command as a line,
+// timestamped with the current time.
+func (iw *InfluxWriter) Write(cmd OBDCommand) error {
+	return iw.WriteMany([]OBDCommand{cmd})
+}
+
+// WriteMany writes the values of all the given processed commands with one
+// call to the underlying writer, which means that they are sent as one batch
+// when writing to an InfluxHTTPEndpoint.
+func (iw *InfluxWriter) WriteMany(commands []OBDCommand) error {
+	var buffer bytes.Buffer
+
+	now := iw.now()
+
+	for _, cmd := range commands {
+		line := FormatInfluxLine(NewReading(cmd, now), iw.tags)
+
+		if line == "" {
+			continue
+		}
+
+		buffer.WriteString(line)
+		buffer.WriteByte('\n')
+	}
+
+	if buffer.Len() == 0 {
+		return nil
+	}
+
+	_, err := iw.writer.Write(buffer.Bytes())
+
+	return err
+}
+
+// FormatInfluxLine formats the given reading as one line of InfluxDB line
+// protocol, without the trailing newline. It returns an empty string when the
+// value is NaN or infinite, which InfluxDB can not store.
+func FormatInfluxLine(reading Reading, tags map[string]string) string {
+	if !isInfluxFinite(reading.Value) {
+		return ""
+	}
+
+	fields, err := formatInfluxFields(reading.Value)
+
+	if err != nil || fields == "" {
+		return ""
+	}
+
+	allTags := map[string]string{}
+
+	for key, val := range tags {
+		allTags[key] = val
+	}
+
+	if reading.Unit != "" {
+		allTags["unit"] = reading.Unit
+	}
+
+	keys := make([]string, 0, len(allTags))
+
+	for key := range allTags {
+		if allTags[key] != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var line strings.Builder
+
+	line.WriteString(influxMeasurementEscaper.Replace(reading.Key))
+
+	for _, key := range keys {
+		line.WriteByte(',')
+		line.WriteString(influxTagEscaper.Replace(key))
+		line.WriteByte('=')
+		line.WriteString(influxTagEscaper.Replace(allTags[key]))
+	}
+
+	line.WriteByte(' ')
+	line.WriteString(fields)
+	line.WriteByte(' ')
+	line.WriteString(strconv.FormatInt(reading.Time.UnixNano(), 10))
+
+	return line.String()
+}
+
+// InfluxHTTPEndpoint is an io.Writer that posts everything written to it to
+// the write endpoint of an InfluxDB server, such as:
+//
+//	http://localhost:8086/api/v2/write?org=home&bucket=car&precision=ns
+//
+// The Token is optional and is sent in the Authorization header when set.
+type InfluxHTTPEndpoint struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+// Write posts the given lines to the endpoint, it fails if the server does
+// not answer with a 2xx status.
+func (ep *InfluxHTTPEndpoint) Write(lines []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, ep.URL, bytes.NewReader(lines))
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to create influx request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	if ep.Token != "" {
+		req.Header.Set("Authorization", "Token "+ep.Token)
+	}
+
+	client := ep.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to post to influx: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+
+		return 0, fmt.Errorf(
+			"influx answered with status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(body)),
+		)
+	}
+
+	return len(lines), nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+var influxMeasurementEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	",", `\,`,
+	" ", `\ `,
+)
+
+var influxTagEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	",", `\,`,
+	"=", `\=`,
+	" ", `\ `,
+)
+
+var influxStringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+)
+
+// formatInfluxFields formats the given value as the fields of a line. Struct
+// and map values are flattened into one field per struct field, named after
+// the JSON of the value with nested names joined by "_", such as
+// "turbo_a_commanded_value". Other values are written as the field "value".
+func formatInfluxFields(value interface{}) (string, error) {
+	kind := reflect.ValueOf(value).Kind()
+
+	if kind != reflect.Struct && kind != reflect.Map {
+		return "value=" + formatInfluxField(value), nil
+	}
+
+	lit, err := json.Marshal(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	var decoded interface{}
+
+	if err := json.Unmarshal(lit, &decoded); err != nil {
+		return "", err
+	}
+
+	fields := map[string]string{}
+
+	if err := flattenInfluxFields("", decoded, fields); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(fields))
+
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for i, name := range names {
+		names[i] = influxTagEscaper.Replace(name) + "=" + fields[name]
+	}
+
+	return strings.Join(names, ","), nil
+}
+
+// flattenInfluxFields adds the given decoded JSON value to the given fields,
+// named after the given prefix. Numbers are always written as floats, so that
+// the type of a field does not change between readings, and arrays are
+// written as JSON strings.
+func flattenInfluxFields(prefix string, value interface{}, fields map[string]string) error {
+	if object, ok := value.(map[string]interface{}); ok {
+		for name, field := range object {
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+
+			if err := flattenInfluxFields(name, field, fields); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Values that are not encoded as JSON objects are a single field
+	if prefix == "" {
+		prefix = "value"
+	}
+
+	switch val := value.(type) {
+	case float64:
+		fields[prefix] = strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		fields[prefix] = strconv.FormatBool(val)
+	case string:
+		fields[prefix] = formatInfluxField(val)
+	case []interface{}:
+		lit, err := json.Marshal(val)
+
+		if err != nil {
+			return err
+		}
+
+		fields[prefix] = formatInfluxField(string(lit))
+	}
+
+	return nil
+}
+
+// formatInfluxField formats the given value as a field value, where integers
+// are suffixed with "i" and everything that is not a number is written as a
+// string.
+func formatInfluxField(value interface{}) string {
+	switch val := value.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(val) + "i"
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10) + "i"
+	default:
+		return `"` + influxStringEscaper.Replace(fmt.Sprint(val)) + `"`
+	}
+}
+
+// isInfluxFinite returns false if the given value is a float that is NaN or
+// infinite.
+func isInfluxFinite(value interface{}) bool {
+	switch val := value.(type) {
+	case float32:
+		return !math.IsNaN(float64(val)) && !math.IsInf(float64(val), 0)
+	case float64:
+		return !math.IsNaN(val) && !math.IsInf(val, 0)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,164 @@
+package elmobd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestFormatInfluxLine(t *testing.T) {
+	at := time.Unix(1662638400, 0)
+
+	type scenario struct {
+		reading Reading
+		tags    map[string]string
+		line    string
+	}
+
+	scenarios := []scenario{
+		{
+			Reading{Key: "engine_rpm", Value: float32(2412.5), Unit: "rpm", Time: at},
+			map[string]string{"vin": "ABC123", "device": "elm 1"},
+			`engine_rpm,device=elm\ 1,unit=rpm,vin=ABC123 value=2412.5 1662638400000000000`,
+		},
+		{
+			Reading{Key: "coolant_temperature", Value: -12, Unit: "°C", Time: at},
+			nil,
+			`coolant_temperature,unit=°C value=-12i 1662638400000000000`,
+		},
+		{
+			Reading{Key: "obd_standards", Value: uint32(7), Unit: "", Time: at},
+			map[string]string{"vin": ""},
+			`obd_standards value=7i 1662638400000000000`,
+		},
+		{
+			Reading{Key: "custom", Value: `say "hi"`, Unit: "", Time: at},
+			nil,
+			`custom value="say \"hi\"" 1662638400000000000`,
+		},
+		{
+			Reading{Key: `custom\path`, Value: `C:\obd`, Unit: "", Time: at},
+			map[string]string{"device": `COM\1`},
+			`custom\\path,device=COM\\1 value="C:\\obd" 1662638400000000000`,
+		},
+		{
+			Reading{Key: "engine_rpm", Value: math.NaN(), Unit: "rpm", Time: at},
+			nil,
+			"",
+		},
+		{
+			Reading{Key: "engine_rpm", Value: float32(math.Inf(-1)), Unit: "rpm", Time: at},
+			nil,
+			"",
+		},
+	}
+
+	for _, scen := range scenarios {
+		assertEqual(t, FormatInfluxLine(scen.reading, scen.tags), scen.line)
+	}
+}
+
+func TestFormatInfluxLineStruct(t *testing.T) {
+	at := time.Unix(1662638400, 0)
+
+	o2Sensor := NewO2SensorVoltage(1, 1)
+	o2Sensor.Voltage = 0.45
+	o2Sensor.ShortTermFuelTrim = 3.125
+	o2Sensor.UsedForTrim = true
+
+	assertEqual(
+		t,
+		FormatInfluxLine(NewReading(o2Sensor, at), map[string]string{"vin": "ABC123"}),
+		`o2_sensor_voltage_bank1_sensor1,vin=ABC123 short_term_fuel_trim=3.125,used_for_trim=true,voltage=0.45 1662638400000000000`,
+	)
+
+	torque := NewEnginePercentTorqueData()
+	torque.Idle = 15
+	torque.Points = [4]int{100, 95, 90, 65}
+
+	assertEqual(
+		t,
+		FormatInfluxLine(NewReading(torque, at), nil),
+		`engine_percent_torque_data idle=15,points="[100,95,90,65]" 1662638400000000000`,
+	)
+
+	boost := NewBoostPressureControl()
+	boost.TurboA.Commanded = SensorValue{true, 100}
+
+	line := FormatInfluxLine(NewReading(boost, at), nil)
+
+	assert(t, strings.Contains(line, ",turbo_a_commanded_supported=true,turbo_a_commanded_value=100,"), line)
+}
+
+func TestInfluxWriterHTTPEndpoint(t *testing.T) {
+	var received []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+
+		if r.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	endpoint := &InfluxHTTPEndpoint{URL: server.URL, Token: "secret"}
+	iw := NewInfluxWriter(endpoint, map[string]string{"vin": "ABC123"})
+
+	iw.now = func() time.Time {
+		return time.Unix(1662638400, 0)
+	}
+
+	speed := NewVehicleSpeed()
+	speed.Value = 80
+
+	rpm := NewEngineRPM()
+	rpm.Value = 2000
+
+	assertSuccess(t, iw.WriteMany([]OBDCommand{speed, rpm}))
+
+	expected := "vehicle_speed,unit=km/h,vin=ABC123 value=80i 1662638400000000000\n" +
+		"engine_rpm,unit=rpm,vin=ABC123 value=2000 1662638400000000000\n"
+
+	assertEqual(t, string(received), expected)
+
+	endpoint.Token = ""
+
+	assert(t, iw.Write(speed) != nil, "unauthorized write fails")
+
+	var buffer bytes.Buffer
+
+	assertSuccess(t, NewInfluxWriter(&buffer, nil).Write(speed))
+	assert(t, buffer.Len() > 0, "writing to buffer succeeds")
+}
+
+func TestInfluxWriterSkipsNonFinite(t *testing.T) {
+	var buffer bytes.Buffer
+
+	iw := NewInfluxWriter(&buffer, nil)
+
+	iw.now = func() time.Time {
+		return time.Unix(1662638400, 0)
+	}
+
+	speed := NewVehicleSpeed()
+	speed.Value = 80
+
+	rpm := NewEngineRPM()
+	rpm.SetFloat64(math.Inf(1))
+
+	assertSuccess(t, iw.WriteMany([]OBDCommand{speed, rpm}))
+	assertEqual(t, buffer.String(), "vehicle_speed,unit=km/h value=80i 1662638400000000000\n")
+}
@@ -0,0 +1,116 @@
+package elmobd
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// LenientParser is implemented by raw devices that can tolerate garbled
+// output from clones, such as RealDevice.
+type LenientParser interface {
+	SetLenient(lenient bool)
+}
+
+// SetLenient turns lenient parsing of the output of the device on or off, it
+// is off by default.
+//
+// Some clones answer with lowercase hex, interleave NUL characters in the
+// output, or echo the command with altered whitespace, which fails the
+// strict parsing. In lenient mode the output is normalized before it is
+// parsed:
+//
+//   - control characters other than carriage returns are stripped
+//   - lines of hex digits are converted to uppercase
+//   - the echo is compared to the command ignoring case and whitespace
+//
+// An error is returned if the raw device does not support it, such as the
+// mock device.
+func (dev *Device) SetLenient(lenient bool) error {
+	raw := unwrapDevice(dev.rawDevice)
+	parser, ok := raw.(LenientParser)
+
+	if !ok {
+		return fmt.Errorf("device does not support lenient parsing: %T", raw)
+	}
+
+	parser.SetLenient(lenient)
+
+	return nil
+}
+
+// SetLenient turns lenient parsing of the output on or off, see
+// Device.SetLenient.
+func (dev *RealDevice) SetLenient(lenient bool) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	dev.lenient = lenient
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// normalizeOutput strips the control characters other than carriage returns
+// from the given output, and converts the lines of hex digits to uppercase.
+func normalizeOutput(output string) string {
+	stripped := strings.Map(func(r rune) rune {
+		if r != '\r' && unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, output)
+
+	lines := strings.Split(stripped, "\r")
+
+	for i, line := range lines {
+		if isHexLine(line) {
+			lines[i] = strings.ToUpper(line)
+		}
+	}
+
+	return strings.Join(lines, "\r")
+}
+
+// isHexLine checks if the given line only consists of hex digits, and the
+// spaces and colons separating them.
+func isHexLine(line string) bool {
+	digits := 0
+
+	for _, r := range line {
+		switch {
+		case r == ' ' || r == ':':
+		case unicode.Is(unicode.ASCII_Hex_Digit, r):
+			digits++
+		default:
+			return false
+		}
+	}
+
+	return digits > 0
+}
+
+// echoMatches checks if the given echo is the echo of the given command, in
+// lenient mode case and whitespace are ignored.
+func echoMatches(echo string, command string, lenient bool) bool {
+	if echo == command {
+		return true
+	}
+
+	if !lenient {
+		return false
+	}
+
+	return strings.EqualFold(stripSpaces(echo), stripSpaces(command))
+}
+
+// stripSpaces removes all whitespace from the given string.
+func stripSpaces(str string) string {
+	return strings.Join(strings.Fields(str), "")
+}
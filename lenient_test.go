@@ -0,0 +1,40 @@
+package elmobd
+
+import (
+	"fmt"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestRealDeviceLenient(t *testing.T) {
+	conn := newFakeConn("", map[string]string{
+		"010D1\r\n": "01 0d1\r\x0041 0d 4b\r\r>\x00",
+	})
+	dev := &RealDevice{conn: conn}
+
+	res := dev.RunCommand("010D1")
+
+	assert(t, res.Failed(), "Expected garbled output to fail in strict mode")
+
+	assertSuccess(t, (&Device{rawDevice: dev}).SetLenient(true))
+
+	res = dev.RunCommand("010D1")
+
+	assertSuccess(t, res.GetError())
+	assertEqual(t, fmt.Sprint(res.GetOutputs()), "[41 0D 4B]")
+
+	conn.responses["010D1\r\n"] = "0110\r41 0D 4B\r\r>"
+	res = dev.RunCommand("010D1")
+
+	assert(t, res.Failed(), "Expected echo of another command to fail")
+}
+
+func TestNormalizeOutput(t *testing.T) {
+	assertEqual(t, normalizeOutput("SEARCHING...\r\n41 0c 1a f8\x00\r"), "SEARCHING...\r41 0C 1A F8\r")
+	assertEqual(t, normalizeOutput("0:49 02 01 31 g4\r"), "0:49 02 01 31 g4\r")
+	assertEqual(t, normalizeOutput("0:49 02 01 31 a4\r"), "0:49 02 01 31 A4\r")
+	assert(t, (&Device{rawDevice: &MockDevice{}}).SetLenient(true) != nil, "Expected mock device to fail")
+}
@@ -0,0 +1,225 @@
+package elmobd
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// DemoValues are the values of the car simulated by a DemoGenerator at a
+// point of the drive.
+type DemoValues struct {
+	// RPM is the engine speed.
+	RPM float64
+	// Speed is the vehicle speed in km/h.
+	Speed float64
+	// Coolant is the engine coolant temperature in °C.
+	Coolant float64
+	// Fuel is the fuel tank level in percent.
+	Fuel float64
+}
+
+// DemoGenerator simulates a drive for demos, alternating between stops, city
+// and highway driving. The engine speed follows the vehicle speed through the
+// gears, the coolant warms up over the first minutes and the fuel level slowly
+// drops.
+//
+// The drive is determined by the seed alone, so that a demo looks lively but
+// is the same each time it is run. It is safe to use from multiple
+// goroutines.
+type DemoGenerator struct {
+	mutex    sync.Mutex
+	rand     *rand.Rand
+	segments []demoSegment
+	ambient  float64
+	fuel     float64
+	phase    float64
+}
+
+// NewDemoGenerator creates a new DemoGenerator simulating the drive of the
+// given seed.
+func NewDemoGenerator(seed int64) *DemoGenerator {
+	gen := &DemoGenerator{rand: rand.New(rand.NewSource(seed))}
+
+	gen.ambient = 10 + gen.rand.Float64()*15
+	gen.fuel = 30 + gen.rand.Float64()*60
+	gen.phase = gen.rand.Float64() * 2 * math.Pi
+
+	return gen
+}
+
+// At returns the values of the car the given time into the drive.
+func (gen *DemoGenerator) At(elapsed time.Duration) DemoValues {
+	seconds := elapsed.Seconds()
+	speed := gen.speedAt(seconds)
+
+	return DemoValues{
+		RPM:     demoRPM(speed, seconds+gen.phase),
+		Speed:   speed,
+		Coolant: gen.ambient + (90-gen.ambient)*(1-math.Exp(-seconds/demoWarmUp)),
+		Fuel:    math.Max(0, gen.fuel-seconds*demoFuelRate),
+	}
+}
+
+// SetDemoGenerator makes the mock device answer the engine speed, vehicle
+// speed, coolant temperature and fuel level with the values of the given
+// generator, starting the drive now. The values set with SetPIDValue take
+// precedence.
+func (dev *MockDevice) SetDemoGenerator(gen *DemoGenerator) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	dev.demo = gen
+	dev.demoStart = time.Now()
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+const (
+	// demoWarmUp is the time constant of the coolant warming up, in seconds.
+	demoWarmUp = 240
+	// demoFuelRate is how fast the fuel level drops, in percent per second.
+	demoFuelRate = 0.002
+	// demoAcceleration is how fast the speed changes, in km/h per second.
+	demoAcceleration = 6
+)
+
+// demoGears are the engine speeds per km/h in each gear.
+var demoGears = []float64{120, 70, 48, 36, 29, 24}
+
+// demoSegment is a part of the drive, reaching the target speed from the
+// speed of the previous segment and keeping it until the end.
+type demoSegment struct {
+	start  float64
+	end    float64
+	from   float64
+	target float64
+}
+
+// speedAt returns the vehicle speed the given amount of seconds into the
+// drive, generating the segments of the drive up to then.
+func (gen *DemoGenerator) speedAt(seconds float64) float64 {
+	gen.mutex.Lock()
+	defer gen.mutex.Unlock()
+
+	for len(gen.segments) == 0 || gen.segments[len(gen.segments)-1].end <= seconds {
+		gen.segments = append(gen.segments, gen.nextSegment())
+	}
+
+	for _, seg := range gen.segments {
+		if seconds >= seg.end {
+			continue
+		}
+
+		ramp := seg.speedAt(seconds)
+
+		if ramp < 1 {
+			return 0
+		}
+
+		// Keeping a speed is never exact
+		return math.Max(0, ramp+1.5*math.Sin(seconds/7+gen.phase))
+	}
+
+	return 0
+}
+
+// speedAt returns the speed the given amount of seconds into the drive, while
+// accelerating or braking towards the target speed of the segment.
+func (seg demoSegment) speedAt(seconds float64) float64 {
+	ramp := seg.from + math.Copysign(
+		(seconds-seg.start)*demoAcceleration,
+		seg.target-seg.from,
+	)
+
+	if seg.target >= seg.from {
+		return math.Min(ramp, seg.target)
+	}
+
+	return math.Max(ramp, seg.target)
+}
+
+// nextSegment generates the segment following the last generated one.
+func (gen *DemoGenerator) nextSegment() demoSegment {
+	seg := demoSegment{}
+
+	if n := len(gen.segments); n > 0 {
+		prev := gen.segments[n-1]
+
+		seg.start = prev.end
+		seg.from = prev.speedAt(prev.end)
+	}
+
+	switch kind := gen.rand.Float64(); {
+	case kind < 0.2:
+		seg.target = 0
+		seg.end = seg.start + 10 + gen.rand.Float64()*20
+	case kind < 0.7:
+		seg.target = 30 + gen.rand.Float64()*30
+		seg.end = seg.start + 30 + gen.rand.Float64()*60
+	default:
+		seg.target = 80 + gen.rand.Float64()*40
+		seg.end = seg.start + 60 + gen.rand.Float64()*120
+	}
+
+	return seg
+}
+
+// demoRPM returns the engine speed at the given vehicle speed, shifting up as
+// soon as the engine speed stays above 1400 in the higher gear.
+func demoRPM(speed float64, seconds float64) float64 {
+	idle := 800 + 20*math.Sin(seconds*3)
+
+	if speed < 1 {
+		return idle
+	}
+
+	rpm := speed * demoGears[0]
+
+	for _, ratio := range demoGears[1:] {
+		if speed*ratio < 1400 {
+			break
+		}
+
+		rpm = speed * ratio
+	}
+
+	return math.Max(rpm, idle)
+}
+
+// demoOutputs returns the response to the given command with the values of
+// the demo generator, if it is one of the simulated values.
+func (dev *MockDevice) demoOutputs(command string) ([]string, bool) {
+	if dev.demo == nil || len(command) < 4 || command[:2] != "01" {
+		return nil, false
+	}
+
+	values := dev.demo.At(time.Since(dev.demoStart))
+
+	for name, value := range map[string]float64{
+		"rpm":     values.RPM,
+		"speed":   values.Speed,
+		"coolant": values.Coolant,
+		"fuel":    values.Fuel,
+	} {
+		param := mockParams[name]
+
+		if command[:4] != fmt.Sprintf("%02X%02X", SERVICE_01_ID, param.pid) {
+			continue
+		}
+
+		value = math.Min(math.Max(value, param.min), param.max)
+
+		return []string{formatMockResponse(SERVICE_01_ID+0x40, param.pid, param.encode(value))}, true
+	}
+
+	return nil, false
+}
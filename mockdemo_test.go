@@ -0,0 +1,69 @@
+package elmobd
+
+import (
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestDemoGenerator(t *testing.T) {
+	gen := NewDemoGenerator(42)
+	same := NewDemoGenerator(42)
+	other := NewDemoGenerator(43)
+
+	// Generating the drive out of order does not change it
+	same.At(time.Hour)
+
+	differs := false
+	prev := gen.At(0)
+
+	for elapsed := time.Second; elapsed < 30*time.Minute; elapsed += time.Second {
+		values := gen.At(elapsed)
+
+		assertEqual(t, values, same.At(elapsed))
+
+		if values != other.At(elapsed) {
+			differs = true
+		}
+
+		assert(t, values.Speed >= 0 && values.Speed < 125, "Expected plausible speed")
+		assert(t, values.RPM >= 750 && values.RPM < 4500, "Expected plausible engine speed")
+		assert(t, values.Coolant > prev.Coolant, "Expected coolant to warm up")
+		assert(t, values.Fuel <= prev.Fuel, "Expected fuel level to drop")
+		assert(t, values.Speed-prev.Speed < 10, "Expected smooth acceleration")
+
+		if values.Speed == 0 {
+			assert(t, values.RPM < 850, "Expected engine to idle when stopped")
+		}
+
+		prev = values
+	}
+
+	assert(t, differs, "Expected seeds to generate different drives")
+	assert(t, prev.Coolant > 85, "Expected coolant to be warm after 30 minutes")
+}
+
+func TestDemoScenario(t *testing.T) {
+	dev, err := NewDevice("test://demo?seed=7", false)
+
+	assertSuccess(t, err)
+
+	values := NewDemoGenerator(7).At(0)
+
+	coolant, err := Run(dev, NewCoolantTemperature())
+
+	assertSuccess(t, err)
+	assert(t, coolant.Value >= int(values.Coolant)-1, "Expected demo coolant temperature")
+	assert(t, coolant.Value <= int(values.Coolant)+1, "Expected demo coolant temperature")
+
+	_, err = Run(dev, NewEngineRPM())
+
+	assertSuccess(t, err)
+
+	_, err = NewDevice("test://demo?seed=many", false)
+
+	assert(t, err != nil, "Expected invalid seed to fail")
+}
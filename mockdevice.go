@@ -3,6 +3,7 @@ package elmobd
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -37,41 +38,168 @@ func (res *MockResult) GetOutputs() []string {
 	return res.outputs
 }
 
+// GetWriteTime returns how long it took to write the command
+func (res *MockResult) GetWriteTime() time.Duration {
+	return res.writeTime
+}
+
+// GetReadTime returns how long it took to read the outputs
+func (res *MockResult) GetReadTime() time.Duration {
+	return res.readTime
+}
+
 // FormatOverview formats a result as an overview of what command was run and
 // how long it took.
 func (res *MockResult) FormatOverview() string {
 	lines := []string{
 		"=======================================",
-		" Mocked command \"%s\"",
+		" Mocked command \"%s\" in %s",
+		" Spent %s writing",
+		" Spent %s reading",
 		"=======================================",
 	}
 
 	return fmt.Sprintf(
 		strings.Join(lines, "\n"),
 		res.input,
+		res.totalTime,
+		res.writeTime,
+		res.readTime,
 	)
 }
 
 // MockDevice represent a mocked serial connection
 type MockDevice struct {
+	mutex        sync.Mutex
+	responses    map[string][]string
+	writeLatency time.Duration
+	readLatency  time.Duration
+	demo         *DemoGenerator
+	demoStart    time.Time
 }
 
 // RunCommand mocks the given AT/OBD command by just returning a result for the
-// mocked outputs set earlier.
+// mocked outputs set earlier, after waiting for the latencies set with
+// SetLatency.
 func (dev *MockDevice) RunCommand(command string) RawResult {
+	dev.mutex.Lock()
+	writeLatency, readLatency := dev.writeLatency, dev.readLatency
+	dev.mutex.Unlock()
+
+	startTotal := time.Now()
+
+	time.Sleep(writeLatency)
+
+	startRead := time.Now()
+	outputs := dev.outputs(command)
+
+	if command == "04" && len(outputs) > 0 && outputs[0] == "44" {
+		dev.clearTroubleCodes()
+	}
+
+	time.Sleep(readLatency)
+
+	endTime := time.Now()
+
 	return &MockResult{
 		input:     command,
-		outputs:   mockOutputs(command),
-		writeTime: 0,
-		readTime:  0,
-		totalTime: 0,
+		outputs:   outputs,
+		writeTime: startRead.Sub(startTotal),
+		readTime:  endTime.Sub(startRead),
+		totalTime: endTime.Sub(startTotal),
 	}
 }
 
+// SetLatency makes the mock device simulate the time it takes to write a
+// command to the device and to read its response, which are 0 by default.
+func (dev *MockDevice) SetLatency(write time.Duration, read time.Duration) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	dev.writeLatency = write
+	dev.readLatency = read
+}
+
+// SetPIDValue makes the mock device answer the given PID of service 01 with
+// the given data bytes, such as 0x0D and 0x50 for a vehicle speed of 80 km/h.
+func (dev *MockDevice) SetPIDValue(pid byte, data ...byte) {
+	dev.setResponse(
+		fmt.Sprintf("%02X%02X", SERVICE_01_ID, pid),
+		formatMockResponse(SERVICE_01_ID+0x40, pid, data),
+	)
+}
+
+// SetATResponse makes the mock device answer the given AT command, such as
+// "ATI", with the given output lines.
+func (dev *MockDevice) SetATResponse(command string, outputs ...string) {
+	dev.setResponse(command, outputs...)
+}
+
+// SetTroubleCodes makes the mock device answer with the given stored trouble
+// codes, no codes are stored when none are given.
+func (dev *MockDevice) SetTroubleCodes(codes ...TroubleCode) {
+	parts := []string{
+		fmt.Sprintf("%02X", SERVICE_03_ID+0x40),
+		fmt.Sprintf("%02X", len(codes)),
+	}
+
+	for _, code := range codes {
+		parts = append(parts, fmt.Sprintf("%02X %02X", byte(code>>8), byte(code)))
+	}
+
+	dev.setResponse(fmt.Sprintf("%02X", SERVICE_03_ID), strings.Join(parts, " "))
+}
+
 /*==============================================================================
  * Internal
  */
 
+// setResponse makes the mock device answer the given command with the given
+// outputs. OBD commands are given without the number of responses, and match
+// the command with any number of responses.
+func (dev *MockDevice) setResponse(command string, outputs ...string) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	if dev.responses == nil {
+		dev.responses = map[string][]string{}
+	}
+
+	dev.responses[command] = outputs
+}
+
+// clearTroubleCodes simulates clearing the trouble codes (service 04), which
+// turns the MIL off and resets the monitors.
+func (dev *MockDevice) clearTroubleCodes() {
+	dev.SetTroubleCodes()
+	dev.SetPIDValue(0x01, 0x00, 0x07, 0xE5, 0x00)
+	dev.setResponse(fmt.Sprintf("%02X0200", SERVICE_02_ID), "NO DATA")
+}
+
+// outputs returns the outputs of the given command, the ones set with
+// setResponse or the default ones of the mock.
+func (dev *MockDevice) outputs(command string) []string {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	if outputs, ok := dev.responses[command]; ok {
+		return outputs
+	}
+
+	// OBD commands end with the number of responses, see ToCommand
+	if len(command) == 5 && !strings.HasPrefix(command, "AT") {
+		if outputs, ok := dev.responses[command[:4]]; ok {
+			return outputs
+		}
+	}
+
+	if outputs, ok := dev.demoOutputs(command); ok {
+		return outputs
+	}
+
+	return mockOutputs(command)
+}
+
 func mockMode1Outputs(subcmd string) []string {
 	if strings.HasPrefix(subcmd, "00") {
 		// PIDs supported part 1
@@ -114,6 +242,38 @@ func mockMode1Outputs(subcmd string) []string {
 		return []string{
 			"41 0C 03 00", // 192 rpm
 		}
+	} else if strings.HasPrefix(subcmd, "10") { // MAF air flow rate
+		return []string{
+			"41 10 05 BE", // 14.70 g/s
+		}
+	} else if strings.HasPrefix(subcmd, "12") { // Commanded secondary air status
+		return []string{
+			"41 12 04", // Outside atmosphere or off
+		}
+	} else if strings.HasPrefix(subcmd, "13") { // O2 sensors present
+		return []string{
+			"41 13 33", // Bank 1 sensor 1 and 2, bank 2 sensor 1 and 2
+		}
+	} else if strings.HasPrefix(subcmd, "14") { // O2 sensor voltage, bank 1 sensor 1
+		return []string{
+			"41 14 5A 84", // 0.45 V, 3.125%
+		}
+	} else if strings.HasPrefix(subcmd, "1C") { // OBD standards
+		return []string{
+			"41 1C 06", // EOBD (Europe)
+		}
+	} else if strings.HasPrefix(subcmd, "21") { // Distance traveled with MIL on
+		return []string{
+			"41 21 00 2A", // 42 km
+		}
+	} else if strings.HasPrefix(subcmd, "23") { // Fuel rail gauge pressure
+		return []string{
+			"41 23 27 10", // 100000 kPa
+		}
+	} else if strings.HasPrefix(subcmd, "24") { // O2 sensor lambda and voltage, bank 1 sensor 1
+		return []string{
+			"41 24 80 00 40 00", // Lambda 1.0, 2 V
+		}
 	} else if strings.HasPrefix(subcmd, "2F") { // Fuel tank level input
 		return []string{
 			"41 2F 6B", // 41.96%
@@ -122,22 +282,50 @@ func mockMode1Outputs(subcmd string) []string {
 		return []string{
 			"41 0D 4B", // 75 km/h
 		}
+	} else if strings.HasPrefix(subcmd, "30") { // Warm-ups since codes cleared
+		return []string{
+			"41 30 0C", // 12
+		}
 	} else if strings.HasPrefix(subcmd, "31") { // Distance traveled since codes cleared
 		return []string{
 			"41 31 02 0C", // 524 km
 		}
+	} else if strings.HasPrefix(subcmd, "34") { // O2 sensor lambda and current, bank 1 sensor 1
+		return []string{
+			"41 34 80 00 80 80", // Lambda 1.0, 0.5 mA
+		}
 	} else if strings.HasPrefix(subcmd, "42") { // Control Module Voltage
 		return []string{
 			"41 42 33 90", // 13.2 volts
 		}
+	} else if strings.HasPrefix(subcmd, "51") { // Fuel type
+		return []string{
+			"41 51 01", // Gasoline
+		}
+	} else if strings.HasPrefix(subcmd, "5E") { // Engine fuel rate
+		return []string{
+			"41 5E 00 64", // 5 L/h
+		}
 	} else if strings.HasPrefix(subcmd, "A6") { // Odometer
 		return []string{
 			"41 A6 00 06 68 a0", // 42,000.00 km
 		}
+	} else if strings.HasPrefix(subcmd, "44") { // Commanded equivalence ratio
+		return []string{
+			"41 44 79 9A", // 0.95
+		}
 	} else if strings.HasPrefix(subcmd, "46") { // Ambient Air Temperature
 		return []string{
 			"41 46 3A", // 18.0 C
 		}
+	} else if strings.HasPrefix(subcmd, "9D") { // Engine fuel rate
+		return []string{
+			"41 9D 00 32 00 3C", // Engine 1 g/s, vehicle 1.2 g/s
+		}
+	} else if strings.HasPrefix(subcmd, "9E") { // Engine exhaust flow rate
+		return []string{
+			"41 9E 01 F4", // 100 kg/h
+		}
 	} else if strings.HasPrefix(subcmd, "A4") { // Transmission Actual Gear
 		return []string{
 			"41 A4 27 10 00 00", // 10.0:1
@@ -147,15 +335,65 @@ func mockMode1Outputs(subcmd string) []string {
 	return []string{"NOT SUPPORTED"}
 }
 
+// mockMode2Outputs answers the freeze frame commands with the values of
+// service 01, only frame 0 is stored.
+func mockMode2Outputs(subcmd string) []string {
+	if len(subcmd) != 4 || subcmd[2:] != "00" {
+		return []string{"NO DATA"}
+	}
+
+	if subcmd[:2] == "00" {
+		// PIDs supported in the frame: 02, 05, 0C, 0D
+		return []string{"42 00 00 48 18 00 00"}
+	} else if subcmd[:2] == "02" {
+		// Code that caused the frame to be stored: P0133
+		return []string{"42 02 00 01 33"}
+	} else if subcmd[:2] != "05" && subcmd[:2] != "0C" && subcmd[:2] != "0D" {
+		return []string{"NO DATA"}
+	}
+
+	outputs := mockMode1Outputs(subcmd[:2])
+
+	return []string{"42 " + subcmd[:2] + " 00" + outputs[0][5:]}
+}
+
 func mockOutputs(cmd string) []string {
 	if cmd == "ATSP0" {
 		return []string{"OK"}
 	} else if cmd == "AT@1" {
 		return []string{"OBDII by elm329@gmail.com"}
+	} else if cmd == "ATI" {
+		return []string{"ELM327 v1.5"}
 	} else if cmd == "AT RV" {
 		return []string{"12.1234"}
+	} else if cmd == "ATIGN" {
+		return []string{"ON"}
+	} else if cmd == "ATLP" {
+		return []string{"OK"}
+	} else if cmd == "ATE0" || cmd == "ATL0" || cmd == "ATS0" || cmd == "ATAT2" {
+		return []string{"OK"}
 	} else if strings.HasPrefix(cmd, "01") {
 		return mockMode1Outputs(cmd[2:])
+	} else if strings.HasPrefix(cmd, "02") {
+		return mockMode2Outputs(cmd[2:])
+	} else if cmd == "03" {
+		// Stored trouble codes: P0133
+		return []string{"43 01 33 00 00 00 00"}
+	} else if cmd == "04" {
+		// Trouble codes cleared
+		return []string{"44"}
+	} else if strings.HasPrefix(cmd, "0600") {
+		// Monitor IDs supported part 1: 01, 02, 20
+		return []string{"46 00 C0 00 00 01"}
+	} else if strings.HasPrefix(cmd, "0620") {
+		// Monitor IDs supported part 2: 21
+		return []string{"46 20 80 00 00 00"}
+	} else if strings.HasPrefix(cmd, "0900") {
+		// Vehicle information supported: 02, 04, 06, 08, 0A
+		return []string{"49 00 55 40 00 00"}
+	} else if strings.HasPrefix(cmd, "AT") {
+		// The device answers "?" to the AT commands it does not know
+		return []string{"?"}
 	}
 
 	return []string{"NOT SUPPORTED"}
@@ -0,0 +1,91 @@
+package elmobd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestMockDeviceSetters(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	speed, err := Run(dev, NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, speed.Value, uint32(75))
+
+	raw.SetPIDValue(0x0D, 0x50)
+
+	speed, err = Run(dev, NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, speed.Value, uint32(80))
+
+	raw.SetATResponse("AT RV", "11.2V")
+
+	voltage, err := dev.GetVoltage()
+
+	assertSuccess(t, err)
+	assertEqual(t, voltage, float32(11.2))
+
+	codes, err := dev.GetTroubleCodes()
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprint(codes), "[P0133]")
+
+	raw.SetTroubleCodes(0x0133, 0xC100)
+
+	codes, err = dev.GetTroubleCodes()
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprint(codes), "[P0133 U0100]")
+
+	raw.SetTroubleCodes()
+
+	codes, err = dev.GetTroubleCodes()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(codes), 0)
+}
+
+func TestMockDeviceLatency(t *testing.T) {
+	raw := &MockDevice{}
+
+	raw.SetLatency(10*time.Millisecond, 20*time.Millisecond)
+
+	res := raw.RunCommand("010D1").(*MockResult)
+
+	assert(t, res.GetWriteTime() >= 10*time.Millisecond, "Expected simulated write latency")
+	assert(t, res.GetReadTime() >= 20*time.Millisecond, "Expected simulated read latency")
+	assert(
+		t,
+		strings.Contains(res.FormatOverview(), "Spent "+res.GetReadTime().String()+" reading"),
+		"Expected read time in overview",
+	)
+
+	raw, err := NewMockDevice("", url.Values{"read_latency": {"30ms"}})
+
+	assertSuccess(t, err)
+
+	dev := &Device{rawDevice: raw}
+	first := dev.RunOBDCommandAsync(NewVehicleSpeed())
+	second := dev.RunOBDCommandAsync(NewEngineRPM())
+
+	for _, results := range []<-chan CommandResult{first, second} {
+		result := <-results
+
+		assertSuccess(t, result.Err)
+		assert(t, result.ReadTime >= 30*time.Millisecond, "Expected simulated read latency")
+	}
+
+	_, err = NewMockDevice("", url.Values{"write_latency": {"soon"}})
+
+	assert(t, err != nil, "Expected invalid latency to fail")
+}
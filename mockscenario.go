@@ -0,0 +1,202 @@
+package elmobd
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// NewMockDevice creates a mock device simulating the given scenario, with the
+// given parameters overriding the values of the scenario. This is what
+// NewDevice does for addresses such as test://highway?rpm=3000, so that the
+// mock can be steered from the address alone.
+//
+// The scenarios are idle, city and highway, and demo which simulates a drive
+// with a DemoGenerator of the seed given with the seed parameter, such as
+// test://demo?seed=42. An empty scenario keeps the default values of the
+// mock. The parameters are:
+//
+//   - rpm: the engine speed, 0 to 16383
+//   - speed: the vehicle speed in km/h, 0 to 255
+//   - coolant: the engine coolant temperature in °C, -40 to 215
+//   - fuel: the fuel tank level in percent, 0 to 100
+//   - write_latency and read_latency: the latencies to simulate, such as
+//     50ms, see MockDevice.SetLatency
+func NewMockDevice(scenario string, params url.Values) (*MockDevice, error) {
+	values, ok := mockScenarios[scenario]
+
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown mock scenario %q, expected one of: %s",
+			scenario,
+			strings.Join(mockScenarioNames(), ", "),
+		)
+	}
+
+	dev := &MockDevice{}
+
+	for name, value := range values {
+		if err := dev.setParam(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if scenario == "demo" {
+		seed := int64(1)
+
+		if params.Has("seed") {
+			var err error
+
+			seed, err = strconv.ParseInt(params.Get("seed"), 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid mock parameter seed: %w", err)
+			}
+		}
+
+		dev.SetDemoGenerator(NewDemoGenerator(seed))
+	}
+
+	latencies := map[string]time.Duration{}
+
+	for name := range params {
+		if name == "seed" && scenario == "demo" {
+			continue
+		}
+
+		if name == "write_latency" || name == "read_latency" {
+			latency, err := time.ParseDuration(params.Get(name))
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid mock parameter %s: %w", name, err)
+			}
+
+			latencies[name] = latency
+
+			continue
+		}
+
+		value, err := strconv.ParseFloat(params.Get(name), 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid mock parameter %s: %w", name, err)
+		}
+
+		if err := dev.setParam(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	dev.SetLatency(latencies["write_latency"], latencies["read_latency"])
+
+	return dev, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// mockParam is a value of the car that the mock device can be told to
+// simulate, answering the PID with the value encoded by the encode function.
+type mockParam struct {
+	pid    byte
+	min    float64
+	max    float64
+	encode func(value float64) []byte
+}
+
+var mockParams = map[string]mockParam{
+	"rpm": {0x0C, 0, 16383, func(value float64) []byte {
+		raw := uint16(value * 4)
+
+		return []byte{byte(raw >> 8), byte(raw)}
+	}},
+	"speed": {0x0D, 0, 255, func(value float64) []byte {
+		return []byte{byte(value)}
+	}},
+	"coolant": {0x05, -40, 215, func(value float64) []byte {
+		return []byte{byte(value + 40)}
+	}},
+	"fuel": {0x2F, 0, 100, func(value float64) []byte {
+		return []byte{byte(value*255/100 + 0.5)}
+	}},
+}
+
+var mockScenarios = map[string]map[string]float64{
+	"":     {},
+	"demo": {},
+	"idle": {
+		"rpm":     800,
+		"speed":   0,
+		"coolant": 90,
+	},
+	"city": {
+		"rpm":     2000,
+		"speed":   50,
+		"coolant": 90,
+	},
+	"highway": {
+		"rpm":     2500,
+		"speed":   110,
+		"coolant": 95,
+	},
+}
+
+// mockScenarioNames returns the sorted names of the scenarios, leaving out
+// the default one.
+func mockScenarioNames() []string {
+	names := []string{}
+
+	for name := range mockScenarios {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// setParam makes the mock device answer with the given value of the given
+// parameter.
+func (dev *MockDevice) setParam(name string, value float64) error {
+	param, ok := mockParams[name]
+
+	if !ok {
+		return fmt.Errorf("unknown mock parameter: %s", name)
+	}
+
+	if value < param.min || value > param.max {
+		return fmt.Errorf(
+			"mock parameter %s out of range: %g (expected %g to %g)",
+			name,
+			value,
+			param.min,
+			param.max,
+		)
+	}
+
+	dev.SetPIDValue(param.pid, param.encode(value)...)
+
+	return nil
+}
+
+// formatMockResponse formats a response to a command of the given mode and
+// PID like the device does.
+func formatMockResponse(mode byte, pid byte, data []byte) string {
+	parts := []string{fmt.Sprintf("%02X", mode), fmt.Sprintf("%02X", pid)}
+
+	for _, b := range data {
+		parts = append(parts, fmt.Sprintf("%02X", b))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,69 @@
+package elmobd
+
+import (
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestMockScenario(t *testing.T) {
+	type scenario struct {
+		addr    string
+		rpm     float32
+		speed   uint32
+		coolant int
+	}
+
+	scenarios := []scenario{
+		{"test://", 192, 75, 39},
+		{"test:///dev/ttyUSB0", 192, 75, 39},
+		{"test://idle", 800, 0, 90},
+		{"test://highway?speed=130", 2500, 130, 95},
+		{"test://?rpm=2000&speed=80", 2000, 80, 39},
+	}
+
+	for _, scen := range scenarios {
+		dev, err := NewDevice(scen.addr, false)
+
+		assertSuccess(t, err)
+
+		rpm, err := Run(dev, NewEngineRPM())
+
+		assertSuccess(t, err)
+		assertEqual(t, rpm.Value, scen.rpm)
+
+		speed, err := Run(dev, NewVehicleSpeed())
+
+		assertSuccess(t, err)
+		assertEqual(t, speed.Value, scen.speed)
+
+		coolant, err := Run(dev, NewCoolantTemperature())
+
+		assertSuccess(t, err)
+		assertEqual(t, coolant.Value, scen.coolant)
+	}
+
+	for _, addr := range []string{
+		"test://racetrack",
+		"test://?boost=2",
+		"test://?speed=fast",
+		"test://?speed=300",
+	} {
+		_, err := NewDevice(addr, false)
+
+		assert(t, err != nil, "Expected invalid mock address to fail: "+addr)
+	}
+}
+
+func TestMockScenarioFuel(t *testing.T) {
+	dev, err := NewMockDevice("", map[string][]string{"fuel": {"50"}})
+
+	assertSuccess(t, err)
+
+	fuel, err := Run(&Device{rawDevice: dev}, NewFuel())
+
+	assertSuccess(t, err)
+	assert(t, fuel.Value > 0.499 && fuel.Value < 0.502, "Expected half full fuel tank")
+}
@@ -0,0 +1,90 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// MQTTClient represents a connected MQTT client that the MQTTPublisher uses
+// to publish messages to a broker.
+//
+// The library does not depend on any MQTT library, so this is usually a thin
+// adapter around the client of your choice, such as Eclipse Paho where the
+// adapter waits for the returned token and returns its error.
+type MQTTClient interface {
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+}
+
+// MQTTPublisher publishes processed commands as readings to an MQTT broker.
+//
+// Each command is published to its own topic, made up of the topic prefix
+// and the key of the command, such as "car/engine_rpm". The payload is the
+// Reading of the command encoded as JSON:
+//
+//	{"key":"engine_rpm","value":2412.5,"unit":"rpm","timestamp":"..."}
+//
+// Publish has the same signature as a typical callback receiving processed
+// commands, so it can be called directly from the loop or callback that polls
+// the device.
+type MQTTPublisher struct {
+	client   MQTTClient
+	prefix   string
+	QoS      byte
+	Retained bool
+	now      func() time.Time
+}
+
+// NewMQTTPublisher creates a new MQTTPublisher that publishes to topics below
+// the given prefix using QoS 0 and non-retained messages.
+func NewMQTTPublisher(client MQTTClient, topicPrefix string) *MQTTPublisher {
+	return &MQTTPublisher{
+		client: client,
+		prefix: strings.TrimSuffix(topicPrefix, "/"),
+		now:    time.Now,
+	}
+}
+
+// Topic returns the topic that the given command is published to.
+func (pub *MQTTPublisher) Topic(cmd OBDCommand) string {
+	if pub.prefix == "" {
+		return cmd.Key()
+	}
+
+	return pub.prefix + "/" + cmd.Key()
+}
+
+// Publish publishes the value of the given processed command to the broker,
+// timestamped with the current time.
+func (pub *MQTTPublisher) Publish(cmd OBDCommand) error {
+	payload, err := json.Marshal(NewReading(cmd, pub.now()))
+
+	if err != nil {
+		return fmt.Errorf("failed to encode reading of %s: %w", cmd.Key(), err)
+	}
+
+	err = pub.client.Publish(pub.Topic(cmd), pub.QoS, pub.Retained, payload)
+
+	if err != nil {
+		return fmt.Errorf("failed to publish reading of %s: %w", cmd.Key(), err)
+	}
+
+	return nil
+}
+
+// PublishMany publishes all the given processed commands, such as the result
+// of RunManyOBDCommands, stopping at the first failure.
+func (pub *MQTTPublisher) PublishMany(commands []OBDCommand) error {
+	for _, cmd := range commands {
+		if err := pub.Publish(cmd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
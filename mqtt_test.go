@@ -0,0 +1,63 @@
+package elmobd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+type publishedMessage struct {
+	topic   string
+	payload string
+}
+
+type fakeMQTTClient struct {
+	messages []publishedMessage
+	err      error
+}
+
+func (client *fakeMQTTClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	if client.err != nil {
+		return client.err
+	}
+
+	client.messages = append(client.messages, publishedMessage{topic, string(payload)})
+
+	return nil
+}
+
+func TestMQTTPublisherPublish(t *testing.T) {
+	client := &fakeMQTTClient{}
+	pub := NewMQTTPublisher(client, "car/")
+
+	pub.now = func() time.Time {
+		return time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC)
+	}
+
+	rpm := NewEngineRPM()
+	rpm.Value = 2412.5
+
+	assertSuccess(t, pub.Publish(rpm))
+
+	assertEqual(t, len(client.messages), 1)
+	assertEqual(t, client.messages[0].topic, "car/engine_rpm")
+	assertEqual(
+		t,
+		client.messages[0].payload,
+		`{"key":"engine_rpm","value":2412.5,"unit":"rpm","timestamp":"2022-09-08T12:00:00Z","schema":1}`,
+	)
+}
+
+func TestMQTTPublisherFailure(t *testing.T) {
+	client := &fakeMQTTClient{err: fmt.Errorf("not connected")}
+	pub := NewMQTTPublisher(client, "")
+
+	err := pub.PublishMany([]OBDCommand{NewVehicleSpeed(), NewEngineRPM()})
+
+	assert(t, err != nil, "publishing without connection fails")
+	assertEqual(t, pub.Topic(NewVehicleSpeed()), "vehicle_speed")
+}
@@ -0,0 +1,234 @@
+package elmobd
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// EnterLowPower puts the device into low power mode (ATLP), where it draws
+// as little current as possible from the battery of the car. The device
+// enters the mode about a second after answering.
+//
+// The device wakes up when it receives any character (see WakeUp), or when
+// the ignition is turned on if the IgnMon input of the chip is wired to the
+// ignition.
+func (dev *Device) EnterLowPower() error {
+	rawRes := dev.runCommand("ATLP")
+
+	if rawRes.Failed() {
+		return rawRes.GetError()
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	return nil
+}
+
+// WakeUp wakes the device from low power mode and waits for it to identify
+// itself. The first command sent after low power mode is swallowed while the
+// device wakes up, so the device is asked to identify itself a few times.
+func (dev *Device) WakeUp() error {
+	var err error
+
+	for i := 0; i < wakeUpAttempts; i++ {
+		if _, err = dev.GetChipVersion(); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to wake up device: %w", err)
+}
+
+// PowerEvent represents the PowerManager putting the device to sleep or
+// waking it up.
+type PowerEvent struct {
+	Asleep bool
+	Time   time.Time
+}
+
+// PowerManager polls the device while the ignition is on and puts the device
+// into low power mode when the car has been shut off, so that a logger left
+// connected does not drain the battery.
+//
+// While the device sleeps it is woken up at the given check interval to see
+// if the ignition is back on, it is put back to sleep if it is not.
+//
+// By default the ignition is checked with GetIgnitionState, which requires
+// the IgnMon input of the chip to be wired to the ignition. Many adapters
+// wire it to the battery instead, then VoltageThreshold can be set to treat
+// the ignition as on while the voltage is at or above the threshold, such as
+// 13.2 V to only poll while the alternator is charging.
+//
+// Use it like this:
+//
+//	pm := elmobd.NewPowerManager(dev, nil)
+//	pm.OffDelay = time.Minute
+//
+//	err := pm.Run(ctx, func() {
+//		// Poll the device
+//	})
+type PowerManager struct {
+	// Interval is the time waited between each round of polling.
+	Interval time.Duration
+	// OffDelay is how long the ignition has to be off before the device is
+	// put to sleep.
+	OffDelay time.Duration
+	// CheckInterval is how often the device is woken up to check the
+	// ignition while it sleeps.
+	CheckInterval time.Duration
+	// VoltageThreshold is the voltage at which the ignition is considered to
+	// be on, GetIgnitionState is used instead when zero.
+	VoltageThreshold float32
+	dev              *Device
+	onChange         func(PowerEvent)
+	now              func() time.Time
+}
+
+// NewPowerManager creates a new PowerManager for the given device. The given
+// callback is called each time the device is put to sleep or woken up, it can
+// be nil.
+func NewPowerManager(dev *Device, onChange func(PowerEvent)) *PowerManager {
+	return &PowerManager{
+		Interval:      time.Second,
+		OffDelay:      30 * time.Second,
+		CheckInterval: 10 * time.Second,
+		dev:           dev,
+		onChange:      onChange,
+		now:           time.Now,
+	}
+}
+
+// IgnitionOn checks whether the ignition is on, see PowerManager.
+func (pm *PowerManager) IgnitionOn() (bool, error) {
+	if pm.VoltageThreshold <= 0 {
+		return pm.dev.GetIgnitionState()
+	}
+
+	voltage, err := pm.dev.GetVoltage()
+
+	if err != nil {
+		return false, err
+	}
+
+	return voltage >= pm.VoltageThreshold, nil
+}
+
+// Run calls the given poll function at the interval while the ignition is on,
+// and handles putting the device to sleep and waking it up. It runs until the
+// given context is done, which is not treated as an error, or until checking
+// the ignition fails.
+//
+// The device is left awake when Run returns.
+func (pm *PowerManager) Run(ctx context.Context, poll func()) error {
+	var offSince time.Time
+
+	for {
+		on, err := pm.IgnitionOn()
+
+		if err != nil {
+			return fmt.Errorf("failed to check ignition: %w", err)
+		}
+
+		if on {
+			offSince = time.Time{}
+
+			poll()
+		} else if offSince.IsZero() {
+			offSince = pm.now()
+		}
+
+		if !on && pm.now().Sub(offSince) >= pm.OffDelay {
+			if err := pm.sleep(ctx); err != nil {
+				return err
+			}
+
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			offSince = time.Time{}
+
+			continue
+		}
+
+		if !waitContext(ctx, pm.Interval) {
+			return nil
+		}
+	}
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// wakeUpAttempts is how many times the device is asked to identify itself
+// when waking it up.
+const wakeUpAttempts = 3
+
+// sleep puts the device into low power mode until the ignition is on again,
+// or the context is done.
+func (pm *PowerManager) sleep(ctx context.Context) error {
+	if err := pm.dev.EnterLowPower(); err != nil {
+		return fmt.Errorf("failed to enter low power mode: %w", err)
+	}
+
+	pm.notify(true)
+
+	for {
+		done := !waitContext(ctx, pm.CheckInterval)
+
+		if err := pm.dev.WakeUp(); err != nil {
+			return err
+		}
+
+		if done {
+			pm.notify(false)
+
+			return nil
+		}
+
+		on, err := pm.IgnitionOn()
+
+		if err != nil {
+			return fmt.Errorf("failed to check ignition: %w", err)
+		}
+
+		if on {
+			pm.notify(false)
+
+			return nil
+		}
+
+		if err := pm.dev.EnterLowPower(); err != nil {
+			return fmt.Errorf("failed to enter low power mode: %w", err)
+		}
+	}
+}
+
+func (pm *PowerManager) notify(asleep bool) {
+	if pm.onChange != nil {
+		pm.onChange(PowerEvent{Asleep: asleep, Time: pm.now()})
+	}
+}
+
+// waitContext waits for the given duration, it returns false if the context
+// is done before that.
+func waitContext(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,117 @@
+package elmobd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// ignitionDevice answers ATIGN with the given states in order, then with ON,
+// and swallows the given amount of commands after ATLP like a real device
+// waking up.
+type ignitionDevice struct {
+	MockDevice
+	ignition []string
+	swallow  int
+	asleep   int
+	commands []string
+}
+
+func (dev *ignitionDevice) RunCommand(command string) RawResult {
+	dev.commands = append(dev.commands, command)
+
+	if dev.asleep > 0 {
+		dev.asleep--
+
+		return &MockResult{input: command, error: io.EOF}
+	}
+
+	if command == "ATLP" {
+		dev.asleep = dev.swallow
+	}
+
+	if command == "ATIGN" && len(dev.ignition) > 0 {
+		state := dev.ignition[0]
+		dev.ignition = dev.ignition[1:]
+
+		return &MockResult{input: command, outputs: []string{state}}
+	}
+
+	return dev.MockDevice.RunCommand(command)
+}
+
+func TestPowerManagerRun(t *testing.T) {
+	raw := &ignitionDevice{ignition: []string{"ON", "OFF", "OFF", "ON"}}
+	dev := &Device{rawDevice: raw}
+
+	var events []PowerEvent
+
+	pm := NewPowerManager(dev, func(event PowerEvent) {
+		events = append(events, event)
+	})
+
+	pm.Interval = time.Millisecond
+	pm.OffDelay = 0
+	pm.CheckInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	polls := 0
+
+	err := pm.Run(ctx, func() {
+		polls++
+
+		if polls == 2 {
+			cancel()
+		}
+	})
+
+	assertSuccess(t, err)
+	assertEqual(t, polls, 2)
+	assertEqual(t, len(events), 2)
+	assertEqual(t, events[0].Asleep, true)
+	assertEqual(t, events[1].Asleep, false)
+	assertEqual(
+		t,
+		fmt.Sprint(raw.commands),
+		"[ATIGN ATIGN ATLP ATI ATIGN ATLP ATI ATIGN ATIGN]",
+	)
+}
+
+func TestPowerManagerVoltageThreshold(t *testing.T) {
+	pm := NewPowerManager(&Device{rawDevice: &MockDevice{}}, nil)
+
+	pm.VoltageThreshold = 13.2
+
+	on, err := pm.IgnitionOn()
+
+	assertSuccess(t, err)
+	assertEqual(t, on, false)
+
+	pm.VoltageThreshold = 12.0
+
+	on, err = pm.IgnitionOn()
+
+	assertSuccess(t, err)
+	assertEqual(t, on, true)
+}
+
+func TestDeviceWakeUp(t *testing.T) {
+	raw := &ignitionDevice{swallow: 1}
+	dev := &Device{rawDevice: raw}
+
+	assertSuccess(t, dev.EnterLowPower())
+	assertSuccess(t, dev.WakeUp())
+	assertEqual(t, fmt.Sprint(raw.commands), "[ATLP ATI ATI]")
+
+	raw.swallow = wakeUpAttempts
+	raw.commands = nil
+
+	assertSuccess(t, dev.EnterLowPower())
+	assert(t, dev.WakeUp() != nil, "Expected waking up to fail")
+}
@@ -0,0 +1,172 @@
+package elmobd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"syscall"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// IsDisconnectError returns true if the given error, as returned when running
+// a command, means that the serial device is gone, such as when the USB
+// dongle has been unplugged.
+func IsDisconnectError(err error) bool {
+	return errors.Is(err, syscall.EIO) ||
+		errors.Is(err, syscall.ENXIO) ||
+		errors.Is(err, syscall.ENODEV) ||
+		errors.Is(err, os.ErrNotExist)
+}
+
+// PresenceEvent represents the serial device appearing or disappearing.
+//
+// When the PresenceWatcher attaches devices automatically, Device is the
+// newly created Device of an appearing serial device, or Err is the error
+// creating it.
+type PresenceEvent struct {
+	Addr    string
+	Present bool
+	Time    time.Time
+	Device  *Device
+	Err     error
+}
+
+// PresenceWatcher watches the path of a serial device and emits events when
+// it appears or disappears, so that loggers survive the dongle being
+// unplugged and plugged in again without restarting the process.
+//
+// When AutoAttach is set, a Device is created when the serial device appears
+// and closed when it disappears. Creating the Device is retried on every
+// check until it succeeds. The Device is created with NewDevice, which needs
+// the serial transport to be imported, otherwise every attempt fails with an
+// unknown transport:
+//
+//	import _ "github.com/rzetterberg/elmobd/transport/serial"
+//
+// Use it like this:
+//
+//	watcher := elmobd.NewPresenceWatcher("/dev/ttyUSB0", time.Second)
+//	watcher.AutoAttach = true
+//
+//	for event := range watcher.Watch(ctx) {
+//		if event.Device != nil {
+//			// Start polling event.Device
+//		}
+//	}
+type PresenceWatcher struct {
+	AutoAttach bool
+	Debug      bool
+	addr       string
+	path       string
+	interval   time.Duration
+	stat       func(path string) error
+	open       func(addr string, debug bool) (*Device, error)
+	now        func() time.Time
+}
+
+// NewPresenceWatcher creates a new PresenceWatcher for the serial device with
+// the given address, either a path or a serial:// URL (see NewDevice, and
+// import github.com/rzetterberg/elmobd/transport/serial for AutoAttach),
+// checking whether the device is present at the given interval.
+func NewPresenceWatcher(addr string, interval time.Duration) *PresenceWatcher {
+	path := addr
+
+	if u, err := url.Parse(addr); err == nil && u.Scheme == "serial" {
+		path = u.Path
+	}
+
+	return &PresenceWatcher{
+		addr:     addr,
+		path:     path,
+		interval: interval,
+		stat: func(path string) error {
+			_, err := os.Stat(path)
+
+			return err
+		},
+		open: NewDevice,
+		now:  time.Now,
+	}
+}
+
+// Watch starts watching the serial device in a new goroutine and returns the
+// channel receiving the events. The first event tells whether the device is
+// present when the watching starts.
+//
+// The watching stops and the channel is closed when the given context is
+// done. A Device attached automatically is not closed then, since it is owned
+// by the receiver of its event.
+func (watcher *PresenceWatcher) Watch(ctx context.Context) <-chan PresenceEvent {
+	events := make(chan PresenceEvent)
+
+	go watcher.watch(ctx, events)
+
+	return events
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+func (watcher *PresenceWatcher) watch(ctx context.Context, events chan<- PresenceEvent) {
+	defer close(events)
+
+	ticker := time.NewTicker(watcher.interval)
+
+	defer ticker.Stop()
+
+	var started, wasPresent bool
+	var attached *Device
+
+	for {
+		present := watcher.stat(watcher.path) == nil
+
+		event := PresenceEvent{
+			Addr:    watcher.addr,
+			Present: present,
+			Time:    watcher.now(),
+		}
+
+		send := !started || present != wasPresent
+
+		if !present && attached != nil {
+			attached.Close()
+			attached = nil
+		}
+
+		if present && watcher.AutoAttach && attached == nil {
+			dev, err := watcher.open(watcher.addr, watcher.Debug)
+
+			if err != nil {
+				event.Err = fmt.Errorf("failed to attach device: %w", err)
+			} else {
+				attached = dev
+				event.Device = dev
+				send = true
+			}
+		}
+
+		if send {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		started = true
+		wasPresent = present
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package elmobd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestIsDisconnectError(t *testing.T) {
+	unplugged := &os.PathError{Op: "read", Path: "/dev/ttyUSB0", Err: syscall.EIO}
+
+	assertEqual(t, IsDisconnectError(unplugged), true)
+	assertEqual(t, IsDisconnectError(fmt.Errorf("wrapped: %w", syscall.ENXIO)), true)
+	assertEqual(t, IsDisconnectError(errors.New("NO DATA")), false)
+	assertEqual(t, IsDisconnectError(nil), false)
+}
+
+func TestPresenceWatcherEvents(t *testing.T) {
+	present := []bool{false, true, true, true, false, true}
+	opens := 0
+	checks := 0
+
+	watcher := NewPresenceWatcher("serial:///dev/ttyUSB0", time.Millisecond)
+	watcher.AutoAttach = true
+	watcher.stat = func(path string) error {
+		assertEqual(t, path, "/dev/ttyUSB0")
+
+		idx := checks
+
+		if idx >= len(present) {
+			idx = len(present) - 1
+		}
+
+		checks++
+
+		if present[idx] {
+			return nil
+		}
+
+		return os.ErrNotExist
+	}
+	watcher.open = func(addr string, debug bool) (*Device, error) {
+		opens++
+
+		if opens == 1 {
+			return nil, errors.New("device busy")
+		}
+
+		return &Device{rawDevice: &MockDevice{}}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := watcher.Watch(ctx)
+
+	expected := []struct {
+		present  bool
+		attached bool
+		failed   bool
+	}{
+		{false, false, false},
+		{true, false, true},
+		{true, true, false},
+		{false, false, false},
+		{true, true, false},
+	}
+
+	for i, exp := range expected {
+		event := <-events
+
+		assertEqual(t, event.Addr, "serial:///dev/ttyUSB0")
+		assert(t, event.Present == exp.present, fmt.Sprintf("Event %d: expected present %v", i, exp.present))
+		assert(t, (event.Device != nil) == exp.attached, fmt.Sprintf("Event %d: expected attached %v", i, exp.attached))
+		assert(t, (event.Err != nil) == exp.failed, fmt.Sprintf("Event %d: expected failure %v", i, exp.failed))
+	}
+
+	cancel()
+
+	for range events {
+	}
+
+	assertEqual(t, opens, 3)
+}
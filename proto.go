@@ -0,0 +1,414 @@
+package elmobd
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// MarshalReadingProto encodes the given reading as the protobuf message
+// Reading defined in readings.proto, which is much smaller than the JSON
+// encoding of the reading, for loggers sending readings over cellular
+// connections. See MarshalReadingsProto for how the values are encoded.
+func MarshalReadingProto(reading Reading) ([]byte, error) {
+	data, err := appendReadingProto(nil, reading)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return appendProtoVarint(data, 8, uint64(schemaOf(reading.Schema))), nil
+}
+
+// UnmarshalReadingProto decodes the given protobuf message Reading, see
+// MarshalReadingProto.
+func UnmarshalReadingProto(data []byte) (Reading, error) {
+	reading := Reading{}
+
+	err := readProtoFields(data, func(field protoField) error {
+		switch field.num {
+		case 1:
+			reading.Key = string(field.data)
+		case 2:
+			reading.Value = math.Float64frombits(field.value)
+		case 3:
+			reading.Value = int(decodeZigZag(field.value))
+		case 4:
+			reading.Unit = string(field.data)
+		case 5:
+			reading.Time = time.UnixMilli(decodeZigZag(field.value)).UTC()
+		case 6:
+			reading.Value = string(field.data)
+		case 7:
+			reading.Value = json.RawMessage(append([]byte{}, field.data...))
+		case 8:
+			reading.Schema = int(field.value)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return Reading{}, fmt.Errorf("failed to decode reading: %w", err)
+	}
+
+	return reading, nil
+}
+
+// MarshalReadingsProto encodes the given readings as the protobuf message
+// ReadingBatch defined in readings.proto, with the current SchemaVersion.
+//
+// The timestamps are encoded in milliseconds. Float values are encoded as
+// doubles, integer values as signed integers, strings as they are and the
+// other values (such as the value of MonitorStatus) as their JSON encoding.
+// When decoded, the integer values are of type int and the values encoded as
+// JSON are of type json.RawMessage.
+func MarshalReadingsProto(readings []Reading) ([]byte, error) {
+	data := appendProtoVarint(nil, 2, SchemaVersion)
+
+	return appendReadingsProto(data, 1, readings)
+}
+
+// UnmarshalReadingsProto decodes the given protobuf message ReadingBatch, see
+// MarshalReadingsProto.
+func UnmarshalReadingsProto(data []byte) ([]Reading, error) {
+	readings := []Reading{}
+	schema := 0
+
+	err := readProtoFields(data, func(field protoField) error {
+		switch field.num {
+		case 1:
+			reading, err := UnmarshalReadingProto(field.data)
+
+			if err != nil {
+				return err
+			}
+
+			readings = append(readings, reading)
+		case 2:
+			schema = int(field.value)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return withSchema(readings, schema), nil
+}
+
+// MarshalSnapshotProto encodes the given snapshot as the protobuf message
+// Snapshot defined in readings.proto, see MarshalReadingsProto for how the
+// readings are encoded.
+func MarshalSnapshotProto(snapshot Snapshot) ([]byte, error) {
+	data := appendProtoVarint(nil, 1, uint64(schemaOf(snapshot.Schema)))
+
+	if !snapshot.Time.IsZero() {
+		data = appendProtoVarint(data, 2, encodeZigZag(snapshot.Time.UnixMilli()))
+	}
+
+	return appendReadingsProto(data, 3, snapshot.Readings)
+}
+
+// UnmarshalSnapshotProto decodes the given protobuf message Snapshot, see
+// MarshalSnapshotProto.
+func UnmarshalSnapshotProto(data []byte) (Snapshot, error) {
+	snapshot := Snapshot{Readings: []Reading{}}
+
+	err := readProtoFields(data, func(field protoField) error {
+		switch field.num {
+		case 1:
+			snapshot.Schema = int(field.value)
+		case 2:
+			snapshot.Time = time.UnixMilli(decodeZigZag(field.value)).UTC()
+		case 3:
+			reading, err := UnmarshalReadingProto(field.data)
+
+			if err != nil {
+				return err
+			}
+
+			snapshot.Readings = append(snapshot.Readings, reading)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	snapshot.Readings = withSchema(snapshot.Readings, snapshot.Schema)
+
+	return snapshot, nil
+}
+
+// MarshalDTCReportProto encodes the given report as the protobuf message
+// DTCReport defined in readings.proto.
+func MarshalDTCReportProto(report DTCReport) ([]byte, error) {
+	data := appendProtoVarint(nil, 4, uint64(schemaOf(report.Schema)))
+
+	if !report.Time.IsZero() {
+		data = appendProtoVarint(data, 1, encodeZigZag(report.Time.UnixMilli()))
+	}
+
+	if report.MilActive {
+		data = appendProtoVarint(data, 2, 1)
+	}
+
+	if len(report.TroubleCodes) > 0 {
+		codes := []byte{}
+
+		for _, code := range report.TroubleCodes {
+			codes = appendUvarint(codes, uint64(code))
+		}
+
+		data = appendProtoBytes(data, 3, codes)
+	}
+
+	return data, nil
+}
+
+// UnmarshalDTCReportProto decodes the given protobuf message DTCReport, see
+// MarshalDTCReportProto.
+func UnmarshalDTCReportProto(data []byte) (DTCReport, error) {
+	report := DTCReport{TroubleCodes: []TroubleCode{}}
+
+	err := readProtoFields(data, func(field protoField) error {
+		switch field.num {
+		case 1:
+			report.Time = time.UnixMilli(decodeZigZag(field.value)).UTC()
+		case 2:
+			report.MilActive = field.value != 0
+		case 4:
+			report.Schema = int(field.value)
+		case 3:
+			if field.wire == protoWireVarint {
+				report.TroubleCodes = append(report.TroubleCodes, TroubleCode(field.value))
+
+				return nil
+			}
+
+			// Repeated scalars are packed, unless the encoder chose not to
+			for codes := field.data; len(codes) > 0; {
+				code, n := binary.Uvarint(codes)
+
+				if n <= 0 {
+					return errors.New("truncated trouble code")
+				}
+
+				report.TroubleCodes = append(report.TroubleCodes, TroubleCode(code))
+				codes = codes[n:]
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return DTCReport{}, fmt.Errorf("failed to decode DTC report: %w", err)
+	}
+
+	return report, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// The wire types of the protobuf encoding.
+const (
+	protoWireVarint = 0
+	protoWire64     = 1
+	protoWireBytes  = 2
+	protoWire32     = 5
+)
+
+// protoField is a field read from a protobuf message, the value holds the
+// varint and fixed size values and the data the length delimited ones.
+type protoField struct {
+	num   int
+	wire  int
+	value uint64
+	data  []byte
+}
+
+// appendReadingProto appends the given reading as a protobuf message
+// Reading to the given data, without its schema version which is given by
+// the message holding it when it is not encoded on its own.
+func appendReadingProto(data []byte, reading Reading) ([]byte, error) {
+	data = appendProtoBytes(data, 1, []byte(reading.Key))
+
+	switch val := reading.Value.(type) {
+	case nil:
+	case float64:
+		data = appendProtoFixed64(data, 2, math.Float64bits(val))
+	case float32:
+		data = appendProtoFixed64(data, 2, math.Float64bits(float64(val)))
+	case int:
+		data = appendProtoVarint(data, 3, encodeZigZag(int64(val)))
+	case int64:
+		data = appendProtoVarint(data, 3, encodeZigZag(val))
+	case uint32:
+		data = appendProtoVarint(data, 3, encodeZigZag(int64(val)))
+	case string:
+		data = appendProtoBytes(data, 6, []byte(val))
+	default:
+		encoded, err := json.Marshal(val)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value of %s: %w", reading.Key, err)
+		}
+
+		data = appendProtoBytes(data, 7, encoded)
+	}
+
+	if reading.Unit != "" {
+		data = appendProtoBytes(data, 4, []byte(reading.Unit))
+	}
+
+	if !reading.Time.IsZero() {
+		data = appendProtoVarint(data, 5, encodeZigZag(reading.Time.UnixMilli()))
+	}
+
+	return data, nil
+}
+
+// appendReadingsProto appends the given readings as the repeated field with
+// the given number to the given data.
+func appendReadingsProto(data []byte, num int, readings []Reading) ([]byte, error) {
+	for _, reading := range readings {
+		message, err := appendReadingProto(nil, reading)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data = appendProtoBytes(data, num, message)
+	}
+
+	return data, nil
+}
+
+// schemaOf returns the given schema version, or the current one for data
+// created without a version.
+func schemaOf(schema int) int {
+	if schema == 0 {
+		return SchemaVersion
+	}
+
+	return schema
+}
+
+// withSchema gives the readings decoded without a schema version the given
+// version of the message holding them.
+func withSchema(readings []Reading, schema int) []Reading {
+	for i := range readings {
+		if readings[i].Schema == 0 {
+			readings[i].Schema = schema
+		}
+	}
+
+	return readings
+}
+
+func appendProtoVarint(data []byte, num int, value uint64) []byte {
+	data = appendUvarint(data, uint64(num)<<3|protoWireVarint)
+
+	return appendUvarint(data, value)
+}
+
+func appendProtoFixed64(data []byte, num int, value uint64) []byte {
+	data = appendUvarint(data, uint64(num)<<3|protoWire64)
+
+	var fixed [8]byte
+
+	binary.LittleEndian.PutUint64(fixed[:], value)
+
+	return append(data, fixed[:]...)
+}
+
+func appendProtoBytes(data []byte, num int, value []byte) []byte {
+	data = appendUvarint(data, uint64(num)<<3|protoWireBytes)
+	data = appendUvarint(data, uint64(len(value)))
+
+	return append(data, value...)
+}
+
+// readProtoFields calls the given function with each field of the given
+// protobuf message, in the order they are encoded.
+func readProtoFields(data []byte, fn func(field protoField) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+
+		if n <= 0 {
+			return errors.New("truncated field tag")
+		}
+
+		data = data[n:]
+		field := protoField{num: int(tag >> 3), wire: int(tag & 0x07)}
+
+		switch field.wire {
+		case protoWireVarint:
+			field.value, n = binary.Uvarint(data)
+
+			if n <= 0 {
+				return fmt.Errorf("truncated field %d", field.num)
+			}
+		case protoWire64:
+			if len(data) < 8 {
+				return fmt.Errorf("truncated field %d", field.num)
+			}
+
+			field.value, n = binary.LittleEndian.Uint64(data), 8
+		case protoWire32:
+			if len(data) < 4 {
+				return fmt.Errorf("truncated field %d", field.num)
+			}
+
+			field.value, n = uint64(binary.LittleEndian.Uint32(data)), 4
+		case protoWireBytes:
+			length, ln := binary.Uvarint(data)
+
+			if ln <= 0 || uint64(len(data)-ln) < length {
+				return fmt.Errorf("truncated field %d", field.num)
+			}
+
+			field.data, n = data[ln:ln+int(length)], ln+int(length)
+		default:
+			return fmt.Errorf("unsupported wire type %d of field %d", field.wire, field.num)
+		}
+
+		data = data[n:]
+
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendUvarint appends the given value as a varint to the given data.
+func appendUvarint(data []byte, value uint64) []byte {
+	var varint [binary.MaxVarintLen64]byte
+
+	return append(data, varint[:binary.PutUvarint(varint[:], value)]...)
+}
+
+func encodeZigZag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+func decodeZigZag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
@@ -0,0 +1,152 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestMarshalReadingProto(t *testing.T) {
+	data, err := MarshalReadingProto(Reading{
+		Key:   "a",
+		Value: 1,
+		Unit:  "u",
+		Time:  time.UnixMilli(1),
+	})
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprintf("% X", data), "0A 01 61 18 02 22 01 75 28 02 40 01")
+
+	reading, err := UnmarshalReadingProto(data)
+
+	assertSuccess(t, err)
+	assertEqual(t, reading.Key, "a")
+	assertEqual(t, reading.Value, 1)
+	assertEqual(t, reading.Unit, "u")
+	assertEqual(t, reading.Time.UnixMilli(), int64(1))
+	assertEqual(t, reading.Schema, SchemaVersion)
+
+	_, err = UnmarshalReadingProto(data[:len(data)-1])
+
+	assert(t, err != nil, "Expected truncated reading to fail")
+}
+
+func TestMarshalReadingsProto(t *testing.T) {
+	at := time.Date(2022, 9, 8, 12, 0, 0, 123000000, time.UTC)
+	rpm := NewEngineRPM()
+	speed := NewVehicleSpeed()
+	temp := NewCoolantTemperature()
+	status := NewMonitorStatus()
+
+	rpm.SetFloat64(2412.5)
+	speed.Value = 80
+	temp.Value = -12
+	status.MilActive = true
+
+	readings := []Reading{
+		NewReading(rpm, at),
+		NewReading(speed, at),
+		NewReading(temp, at),
+		NewReading(status, at),
+		AsReading(NewCommandedSecondaryAirStatus()),
+		{Key: "nothing"},
+	}
+
+	data, err := MarshalReadingsProto(readings)
+
+	assertSuccess(t, err)
+
+	encoded, _ := json.Marshal(readings)
+
+	assert(t, len(data) < len(encoded), "Expected protobuf to be smaller than JSON")
+
+	// Numeric readings are a fraction of their JSON size
+	numeric, _ := MarshalReadingsProto(readings[:3])
+	encoded, _ = json.Marshal(readings[:3])
+
+	assert(t, len(numeric)*2 < len(encoded), "Expected numeric readings to be compact")
+
+	decoded, err := UnmarshalReadingsProto(data)
+
+	assertSuccess(t, err)
+	assertEqual(t, len(decoded), len(readings))
+	assertEqual(t, decoded[0].Value, 2412.5)
+	assertEqual(t, decoded[0].Unit, "rpm")
+	assertEqual(t, decoded[0].Time, at)
+	assertEqual(t, decoded[1].Value, 80)
+	assertEqual(t, decoded[2].Value, -12)
+	assertEqual(t, decoded[4].Value, readings[4].Value)
+	assertEqual(t, decoded[4].Time.IsZero(), true)
+	assertEqual(t, decoded[5].Value, nil)
+
+	// Values with multiple fields are kept as JSON
+	decodedJSON, _ := json.Marshal(decoded[3])
+	readingJSON, _ := json.Marshal(readings[3])
+
+	assertEqual(t, string(decodedJSON), string(readingJSON))
+}
+
+func TestMarshalDTCReportProto(t *testing.T) {
+	report := DTCReport{
+		Time:         time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC),
+		MilActive:    true,
+		TroubleCodes: []TroubleCode{0x0133, 0xC100},
+	}
+
+	data, err := MarshalDTCReportProto(report)
+
+	assertSuccess(t, err)
+
+	decoded, err := UnmarshalDTCReportProto(data)
+
+	assertSuccess(t, err)
+	assertEqual(t, decoded.Time, report.Time)
+	assertEqual(t, decoded.MilActive, true)
+	assertEqual(t, fmt.Sprint(decoded.TroubleCodes), "[P0133 U0100]")
+
+	// Unpacked trouble codes, as other encoders may send them
+	decoded, err = UnmarshalDTCReportProto([]byte{0x18, 0xB3, 0x02})
+
+	assertSuccess(t, err)
+	assertEqual(t, decoded.MilActive, false)
+	assertEqual(t, fmt.Sprint(decoded.TroubleCodes), "[P0133]")
+
+	// Reports created without a version have the current one
+	data, err = MarshalDTCReportProto(DTCReport{})
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprintf("% X", data), "20 01")
+}
+
+func TestMarshalSnapshotProto(t *testing.T) {
+	at := time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC)
+	snapshot := NewSnapshot([]OBDCommand{NewVehicleSpeed(), NewEngineRPM()}, at)
+
+	data, err := MarshalSnapshotProto(snapshot)
+
+	assertSuccess(t, err)
+
+	decoded, err := UnmarshalSnapshotProto(data)
+
+	assertSuccess(t, err)
+	assertEqual(t, decoded.Schema, SchemaVersion)
+	assertEqual(t, decoded.Time, at)
+	assertEqual(t, len(decoded.Readings), 2)
+	assertEqual(t, decoded.Readings[1].Key, "engine_rpm")
+	assertEqual(t, decoded.Readings[1].Schema, SchemaVersion)
+
+	// Readings of batches have the schema of the batch
+	data, err = MarshalReadingsProto(snapshot.Readings)
+
+	assertSuccess(t, err)
+
+	readings, err := UnmarshalReadingsProto(data)
+
+	assertSuccess(t, err)
+	assertEqual(t, readings[0].Schema, SchemaVersion)
+}
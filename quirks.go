@@ -0,0 +1,238 @@
+package elmobd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// The names of the quirks known by the library, see KnownQuirks.
+const (
+	// QuirkNoAdaptiveTiming is set for fake v2.1 chips, which answer "?" to
+	// ATAT. FastMode leaves out ATAT2 for these.
+	QuirkNoAdaptiveTiming = "no_adaptive_timing"
+	// QuirkDropsFirstResponse is set for adapters that do not answer the
+	// first command after being reset. A throwaway command is sent after
+	// each reset for these, see ResetTuner.
+	QuirkDropsFirstResponse = "drops_first_response"
+	// QuirkSlowReset is set for v1.5 clones, many of which answer garbage
+	// when a command is sent right after ATZ. The device waits after each
+	// reset for these, see ResetTuner.
+	QuirkSlowReset = "slow_reset"
+)
+
+// AdapterIdentity represents what is known about the connected adapter when
+// detecting its quirks, see Device.Identify.
+type AdapterIdentity struct {
+	Chip ChipVersion
+	// Description is the answer to AT@1, it is empty if the adapter does not
+	// support it.
+	Description string
+	// FirstResponseDropped tells whether the adapter had to be asked twice to
+	// identify itself.
+	FirstResponseDropped bool
+}
+
+// Quirk represents a known misbehaviour of some ELM327 clones, and how to
+// work around it.
+type Quirk struct {
+	Name        string
+	Description string
+	// Detect tells whether the identified adapter has the quirk.
+	Detect func(id AdapterIdentity) bool
+	// Apply works around the quirk on the device, it can be nil for quirks
+	// that are only checked with Device.HasQuirk.
+	Apply func(dev *Device) error
+}
+
+// ResetTuner is implemented by raw devices whose reset can be adjusted for
+// clones that are not ready right after being reset, such as RealDevice.
+type ResetTuner interface {
+	// SetResetDelay sets the time waited after the device has been reset
+	// before the next command is sent.
+	SetResetDelay(delay time.Duration)
+	// SetResetProbe makes the device send a throwaway command after it has
+	// been reset, for adapters that do not answer the first command.
+	SetResetProbe(probe bool)
+}
+
+// RegisterQuirk adds the given quirk to the ones detected on connect, or
+// replaces the known quirk with the same name.
+func RegisterQuirk(quirk Quirk) {
+	quirkRegistry.mutex.Lock()
+	defer quirkRegistry.mutex.Unlock()
+
+	for i, known := range quirkRegistry.quirks {
+		if known.Name == quirk.Name {
+			quirkRegistry.quirks[i] = quirk
+
+			return
+		}
+	}
+
+	quirkRegistry.quirks = append(quirkRegistry.quirks, quirk)
+}
+
+// KnownQuirks returns the quirks that are detected on connect, in the order
+// they are applied.
+func KnownQuirks() []Quirk {
+	quirkRegistry.mutex.Lock()
+	defer quirkRegistry.mutex.Unlock()
+
+	return append([]Quirk{}, quirkRegistry.quirks...)
+}
+
+// Identify asks the adapter to identify itself (ATI and AT@1), for detecting
+// its quirks.
+//
+// The adapter is asked twice when it does not answer the first time, so
+// FirstResponseDropped is only meaningful when this is the first command
+// sent after the device was reset, as done by NewDevice.
+func (dev *Device) Identify() (AdapterIdentity, error) {
+	var id AdapterIdentity
+
+	chip, err := dev.GetChipVersion()
+
+	if err != nil {
+		chip, err = dev.GetChipVersion()
+
+		if err != nil {
+			return id, fmt.Errorf("failed to identify adapter: %w", err)
+		}
+
+		id.FirstResponseDropped = true
+	}
+
+	id.Chip = chip
+
+	if description, err := dev.GetVersion(); err == nil {
+		id.Description = description
+	}
+
+	return id, nil
+}
+
+// ApplyQuirks identifies the adapter and applies the workarounds of the known
+// quirks it has (see KnownQuirks), returning the names of the quirks found.
+//
+// This is done by NewDevice when connecting, so it only needs to be called
+// when the Device is created some other way.
+func (dev *Device) ApplyQuirks() ([]string, error) {
+	id, err := dev.Identify()
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for _, quirk := range KnownQuirks() {
+		if !quirk.Detect(id) {
+			continue
+		}
+
+		if quirk.Apply != nil {
+			if err := quirk.Apply(dev); err != nil {
+				return names, fmt.Errorf("failed to apply quirk %s: %w", quirk.Name, err)
+			}
+		}
+
+		names = append(names, quirk.Name)
+	}
+
+	dev.quirks.set(names)
+
+	return names, nil
+}
+
+// Quirks returns the names of the quirks applied to the device.
+func (dev *Device) Quirks() []string {
+	return dev.quirks.get()
+}
+
+// HasQuirk checks whether the quirk with the given name was applied to the
+// device.
+func (dev *Device) HasQuirk(name string) bool {
+	for _, applied := range dev.quirks.get() {
+		if applied == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// slowResetDelay is the time waited after a reset for QuirkSlowReset.
+const slowResetDelay = 500 * time.Millisecond
+
+var quirkRegistry = struct {
+	mutex  sync.Mutex
+	quirks []Quirk
+}{
+	quirks: []Quirk{
+		{
+			Name:        QuirkNoAdaptiveTiming,
+			Description: "fake v2.1 chip without adaptive timing (ATAT)",
+			Detect: func(id AdapterIdentity) bool {
+				return id.Chip.LikelyClone && id.Chip.Major == 2 && id.Chip.Minor == 1
+			},
+		},
+		{
+			Name:        QuirkDropsFirstResponse,
+			Description: "does not answer the first command after a reset",
+			Detect: func(id AdapterIdentity) bool {
+				return id.FirstResponseDropped
+			},
+			Apply: func(dev *Device) error {
+				if tuner, ok := unwrapDevice(dev.rawDevice).(ResetTuner); ok {
+					tuner.SetResetProbe(true)
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:        QuirkSlowReset,
+			Description: "needs time after ATZ before answering reliably",
+			Detect: func(id AdapterIdentity) bool {
+				return id.Chip.LikelyClone && id.Chip.Major == 1 && id.Chip.Minor == 5
+			},
+			Apply: func(dev *Device) error {
+				if tuner, ok := unwrapDevice(dev.rawDevice).(ResetTuner); ok {
+					tuner.SetResetDelay(slowResetDelay)
+				}
+
+				return nil
+			},
+		},
+	},
+}
+
+// quirkSet keeps the quirks applied to a Device, it is safe to use from
+// multiple goroutines.
+type quirkSet struct {
+	mutex sync.Mutex
+	names []string
+}
+
+func (set *quirkSet) get() []string {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return append([]string{}, set.names...)
+}
+
+func (set *quirkSet) set(names []string) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	set.names = append([]string{}, names...)
+}
@@ -0,0 +1,156 @@
+package elmobd
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// quirkyDevice identifies itself as the given chip, drops the given amount of
+// commands and records the reset settings it is given.
+type quirkyDevice struct {
+	recordingDevice
+	chip       string
+	drop       int
+	resetDelay time.Duration
+	resetProbe bool
+}
+
+func (dev *quirkyDevice) RunCommand(command string) RawResult {
+	dev.recordingDevice.RunCommand(command)
+
+	if dev.drop > 0 {
+		dev.drop--
+
+		return &MockResult{input: command, error: io.EOF}
+	}
+
+	if command == "ATI" {
+		return &MockResult{input: command, outputs: []string{dev.chip}}
+	}
+
+	return dev.MockDevice.RunCommand(command)
+}
+
+func (dev *quirkyDevice) SetResetDelay(delay time.Duration) {
+	dev.resetDelay = delay
+}
+
+func (dev *quirkyDevice) SetResetProbe(probe bool) {
+	dev.resetProbe = probe
+}
+
+func TestApplyQuirks(t *testing.T) {
+	type scenario struct {
+		chip       string
+		drop       int
+		quirks     string
+		resetDelay time.Duration
+		resetProbe bool
+	}
+
+	scenarios := []scenario{
+		{"ELM327 v1.4b", 0, "[]", 0, false},
+		{"ELM327 v2.1", 0, "[no_adaptive_timing]", 0, false},
+		{"ELM327 v1.5", 0, "[slow_reset]", slowResetDelay, false},
+		{"ELM327 v1.5", 1, "[drops_first_response slow_reset]", slowResetDelay, true},
+	}
+
+	for _, scen := range scenarios {
+		raw := &quirkyDevice{chip: scen.chip, drop: scen.drop}
+		dev := &Device{rawDevice: raw}
+
+		names, err := dev.ApplyQuirks()
+
+		assertSuccess(t, err)
+		assertEqual(t, fmt.Sprint(names), scen.quirks)
+		assertEqual(t, fmt.Sprint(dev.Quirks()), scen.quirks)
+		assertEqual(t, raw.resetDelay, scen.resetDelay)
+		assertEqual(t, raw.resetProbe, scen.resetProbe)
+	}
+
+	raw := &quirkyDevice{chip: "ELM327 v1.4b", drop: 2}
+
+	_, err := (&Device{rawDevice: raw}).ApplyQuirks()
+
+	assert(t, err != nil, "Expected silent adapter to fail identification")
+}
+
+func TestFastModeNoAdaptiveTiming(t *testing.T) {
+	raw := &quirkyDevice{chip: "ELM327 v2.1"}
+	dev := &Device{rawDevice: raw}
+
+	_, err := dev.ApplyQuirks()
+
+	assertSuccess(t, err)
+	assert(t, dev.HasQuirk(QuirkNoAdaptiveTiming), "Expected v2.1 clone quirk")
+
+	raw.commands = nil
+	steps, err := dev.FastMode(0)
+
+	assertSuccess(t, err)
+	assertEqual(t, len(steps), 3)
+	assertEqual(t, fmt.Sprint(raw.commands), "[ATE0 ATL0 ATS0]")
+}
+
+func TestRegisterQuirk(t *testing.T) {
+	defer func(quirks []Quirk) {
+		quirkRegistry.quirks = quirks
+	}(KnownQuirks())
+
+	applied := false
+
+	RegisterQuirk(Quirk{
+		Name: "custom",
+		Detect: func(id AdapterIdentity) bool {
+			return id.Description != ""
+		},
+		Apply: func(dev *Device) error {
+			applied = true
+
+			return nil
+		},
+	})
+
+	// Replacing a known quirk keeps its place
+	RegisterQuirk(Quirk{
+		Name: QuirkSlowReset,
+		Detect: func(id AdapterIdentity) bool {
+			return false
+		},
+	})
+
+	assertEqual(t, len(KnownQuirks()), 4)
+
+	dev := &Device{rawDevice: &MockDevice{}}
+	names, err := dev.ApplyQuirks()
+
+	assertSuccess(t, err)
+	assertEqual(t, fmt.Sprint(names), "[custom]")
+	assertEqual(t, applied, true)
+}
+
+func TestRealDeviceResetProbe(t *testing.T) {
+	conn := newFakeConn("", map[string]string{
+		"ATZ\r\n": "ATZ\r\r\rELM327 v1.5\r\r>",
+		// The answer of the first command after the reset is dropped
+		"ATI\r\n":   "",
+		"010D1\r\n": "010D1\r41 0D 4B\r\r>",
+	})
+	dev := &RealDevice{conn: conn}
+
+	dev.SetResetProbe(true)
+
+	assertSuccess(t, dev.Reset())
+	assertEqual(t, fmt.Sprint(conn.written), fmt.Sprint([]string{"ATZ\r\n", "ATI\r\n"}))
+
+	res := dev.RunCommand("010D1")
+
+	assertSuccess(t, res.GetError())
+	assertEqual(t, fmt.Sprint(res.GetOutputs()), "[41 0D 4B]")
+}
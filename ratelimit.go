@@ -0,0 +1,78 @@
+package elmobd
+
+import (
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// SetRateLimit limits how fast commands are sent to the device, since several
+// cheap clones lock up or answer garbage when commands are sent back-to-back.
+//
+// The given minimum delay is the time waited after a command has been
+// answered before the next command is sent, and the given maximum amount of
+// commands per second limits how often commands are started. Either limit is
+// turned off by setting it to zero, which is the default.
+//
+// The limits apply to all commands run on the device, including AT commands.
+// While a limit is set, commands run from multiple goroutines are sent one
+// after the other.
+func (dev *Device) SetRateLimit(minDelay time.Duration, maxPerSecond float64) {
+	interval := time.Duration(0)
+
+	if maxPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / maxPerSecond)
+	}
+
+	dev.limiter.mutex.Lock()
+	defer dev.limiter.mutex.Unlock()
+
+	dev.limiter.minDelay = minDelay
+	dev.limiter.interval = interval
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// rateLimiter spaces out the commands of a Device, see SetRateLimit.
+type rateLimiter struct {
+	mutex     sync.Mutex
+	minDelay  time.Duration
+	interval  time.Duration
+	lastStart time.Time
+	lastEnd   time.Time
+}
+
+// acquire waits until the next command may be sent, and returns the function
+// to call once the command has been answered. The limiter is held until then,
+// so that commands are not sent while another command is in flight.
+func (lim *rateLimiter) acquire() func() {
+	lim.mutex.Lock()
+
+	if lim.minDelay <= 0 && lim.interval <= 0 {
+		lim.mutex.Unlock()
+
+		return func() {}
+	}
+
+	next := lim.lastEnd.Add(lim.minDelay)
+
+	if start := lim.lastStart.Add(lim.interval); start.After(next) {
+		next = start
+	}
+
+	if delay := time.Until(next); delay > 0 {
+		time.Sleep(delay)
+	}
+
+	lim.lastStart = time.Now()
+
+	return func() {
+		lim.lastEnd = time.Now()
+		lim.mutex.Unlock()
+	}
+}
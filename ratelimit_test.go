@@ -0,0 +1,65 @@
+package elmobd
+
+import (
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestSetRateLimit(t *testing.T) {
+	type scenario struct {
+		minDelay     time.Duration
+		maxPerSecond float64
+	}
+
+	// Both limits space out 3 commands by at least 40 ms in total
+	scenarios := []scenario{
+		{20 * time.Millisecond, 0},
+		{0, 50},
+		{20 * time.Millisecond, 1000},
+	}
+
+	for _, scen := range scenarios {
+		dev := &Device{rawDevice: &MockDevice{}}
+
+		dev.SetRateLimit(scen.minDelay, scen.maxPerSecond)
+
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			_, err := dev.RunOBDCommand(NewVehicleSpeed())
+
+			assertSuccess(t, err)
+		}
+
+		elapsed := time.Since(start)
+
+		assert(t, elapsed >= 40*time.Millisecond, "Expected commands to be spaced out, took "+elapsed.String())
+	}
+}
+
+func TestSetRateLimitAsync(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	dev.SetRateLimit(0, 100)
+
+	start := time.Now()
+	first := dev.RunOBDCommandAsync(NewVehicleSpeed())
+	second := dev.RunOBDCommandAsync(NewEngineRPM())
+
+	assertSuccess(t, (<-first).Err)
+	assertSuccess(t, (<-second).Err)
+
+	elapsed := time.Since(start)
+
+	assert(t, elapsed >= 10*time.Millisecond, "Expected commands to be spaced out, took "+elapsed.String())
+
+	dev.SetRateLimit(0, 0)
+
+	_, err := dev.RunOBDCommand(NewVehicleSpeed())
+
+	assertSuccess(t, err)
+}
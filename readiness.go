@@ -0,0 +1,121 @@
+package elmobd
+
+import (
+	"fmt"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// ReadinessReport represents the emissions readiness of the car, which is
+// what an inspection and maintenance (I/M) program checks through the OBD
+// port, see Device.GetReadinessReport.
+//
+// The distances are in km and the times in minutes. The values of PIDs the
+// car does not support are nil.
+type ReadinessReport struct {
+	// Pass tells whether the car is expected to pass the inspection, the
+	// reasons tell why not.
+	Pass    bool     `json:"pass"`
+	Reasons []string `json:"reasons"`
+	// Ready tells whether all available monitors are complete.
+	Ready              bool          `json:"ready"`
+	IncompleteMonitors []string      `json:"incomplete_monitors"`
+	MilActive          bool          `json:"mil_active"`
+	DtcAmount          byte          `json:"dtc_amount"`
+	TroubleCodes       []TroubleCode `json:"trouble_codes"`
+	Monitors           Monitors      `json:"monitors"`
+	ThisDriveCycle     *Monitors     `json:"this_drive_cycle,omitempty"`
+	DistanceWithMilOn  *uint32       `json:"distance_with_mil_on,omitempty"`
+	TimeWithMilOn      *uint32       `json:"time_with_mil_on,omitempty"`
+	TimeSinceDTCClear  *uint32       `json:"time_since_dtc_clear,omitempty"`
+}
+
+// GetReadinessReport gathers the monitor status, the stored trouble codes,
+// the monitor status of this drive cycle (PID 41) and the distance and time
+// with the MIL on and since the codes were cleared (PIDs 21, 4D and 4E) into
+// one report.
+//
+// The car is expected to pass when the MIL is off and at most the given
+// amount of available monitors are incomplete. In the US, cars from 2001 and
+// later are allowed 1 incomplete monitor and older cars 2, while many other
+// programs require all monitors to be complete.
+//
+// The monitor status and the trouble codes are required, the other PIDs are
+// left out of the report when they can not be read.
+func (dev *Device) GetReadinessReport(allowedIncomplete int) (*ReadinessReport, error) {
+	status, err := Run(dev, NewMonitorStatus())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read monitor status: %w", err)
+	}
+
+	codes, err := dev.GetTroubleCodes()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trouble codes: %w", err)
+	}
+
+	report := &ReadinessReport{
+		Ready:              status.Ready(),
+		IncompleteMonitors: []string{},
+		MilActive:          status.MilActive,
+		DtcAmount:          status.DtcAmount,
+		TroubleCodes:       codes,
+		Monitors:           status.Monitors,
+	}
+
+	for _, test := range status.Incomplete() {
+		report.IncompleteMonitors = append(report.IncompleteMonitors, test.Name)
+	}
+
+	if cycle, err := Run(dev, NewMonitorStatusThisDriveCycle()); err == nil {
+		report.ThisDriveCycle = &cycle.Monitors
+	}
+
+	if dist, err := Run(dev, NewDistWithMILOn()); err == nil {
+		report.DistanceWithMilOn = &dist.Value
+	}
+
+	if minutes, err := Run(dev, NewTimeWithMILOn()); err == nil {
+		report.TimeWithMilOn = &minutes.Value
+	}
+
+	if minutes, err := Run(dev, NewTimeSinceDTCClear()); err == nil {
+		report.TimeSinceDTCClear = &minutes.Value
+	}
+
+	report.assess(allowedIncomplete)
+
+	return report, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// assess decides whether the car is expected to pass the inspection.
+func (report *ReadinessReport) assess(allowedIncomplete int) {
+	report.Reasons = []string{}
+
+	if report.MilActive {
+		report.Reasons = append(
+			report.Reasons,
+			fmt.Sprintf("MIL is on with %d trouble codes", report.DtcAmount),
+		)
+	}
+
+	if len(report.IncompleteMonitors) > allowedIncomplete {
+		report.Reasons = append(
+			report.Reasons,
+			fmt.Sprintf(
+				"%d monitors are incomplete, at most %d allowed",
+				len(report.IncompleteMonitors),
+				allowedIncomplete,
+			),
+		)
+	}
+
+	report.Pass = len(report.Reasons) == 0
+}
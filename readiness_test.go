@@ -0,0 +1,74 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// scriptedDevice answers the commands in outputs with the given outputs, and
+// all other commands like MockDevice.
+type scriptedDevice struct {
+	MockDevice
+	outputs map[string][]string
+}
+
+func (dev *scriptedDevice) RunCommand(command string) RawResult {
+	if outputs, ok := dev.outputs[command]; ok {
+		return &MockResult{input: command, outputs: outputs}
+	}
+
+	return dev.MockDevice.RunCommand(command)
+}
+
+func TestDeviceGetReadinessReport(t *testing.T) {
+	raw := &scriptedDevice{outputs: map[string][]string{
+		// MIL off, spark ignition, catalyst and EVAP available, EVAP incomplete
+		"01011": {"41 01 00 07 05 04"},
+		"03":    {"43 00"},
+		"01411": {"41 41 00 07 05 00"},
+		"014D1": {"41 4D 00 00"},
+		"014E1": {"41 4E 01 2C"},
+	}}
+	dev := &Device{rawDevice: raw}
+
+	report, err := dev.GetReadinessReport(1)
+
+	assertSuccess(t, err)
+	assertEqual(t, report.Pass, true)
+	assertEqual(t, report.Ready, false)
+	assertEqual(t, len(report.IncompleteMonitors), 1)
+	assertEqual(t, report.IncompleteMonitors[0], "evaporative_system")
+	assertEqual(t, len(report.TroubleCodes), 0)
+	assert(t, report.ThisDriveCycle != nil, "Expected monitors of this drive cycle")
+	assertEqual(t, *report.DistanceWithMilOn, uint32(42))
+	assertEqual(t, *report.TimeWithMilOn, uint32(0))
+	assertEqual(t, *report.TimeSinceDTCClear, uint32(300))
+
+	report, err = dev.GetReadinessReport(0)
+
+	assertSuccess(t, err)
+	assertEqual(t, report.Pass, false)
+	assertEqual(t, len(report.Reasons), 1)
+
+	raw.outputs["01011"] = []string{"41 01 81 07 05 00"}
+	raw.outputs["03"] = []string{"43 01 01 33"}
+	delete(raw.outputs, "014E1")
+
+	report, err = dev.GetReadinessReport(1)
+
+	assertSuccess(t, err)
+	assertEqual(t, report.Pass, false)
+	assertEqual(t, report.Ready, true)
+	assertEqual(t, report.TroubleCodes[0], TroubleCode(0x0133))
+	assert(t, report.TimeSinceDTCClear == nil, "Expected unsupported PID to be left out")
+
+	lit, err := json.Marshal(report)
+
+	assertSuccess(t, err)
+	assert(t, json.Valid(lit), "Expected valid JSON")
+	assertEqual(t, report.Reasons[0], "MIL is on with 1 trouble codes")
+}
@@ -0,0 +1,112 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Reading represents the processed value of a command at a given point in
+// time, in a form that is suitable for exporting to other systems.
+//
+// The Value is the value of the command as it is (such as a float64 for
+// EngineRPM) for commands that embed FloatCommand, IntCommand or UIntCommand.
+// Commands with multiple values (such as MonitorStatus) have a struct as
+// Value, and commands without a result have nil. For commands defined outside
+// of this library the Value is the literal representation given by
+// ValueAsLit.
+//
+// The Schema is the version of the data model of the reading, see
+// SchemaVersion.
+type Reading struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Unit   string      `json:"unit,omitempty"`
+	Time   time.Time   `json:"timestamp"`
+	Schema int         `json:"schema"`
+}
+
+// NewReading creates a new Reading from the given processed command, taken at
+// the given time.
+func NewReading(cmd OBDCommand, at time.Time) Reading {
+	var value interface{}
+
+	if valCmd, ok := cmd.(valueCommand); ok {
+		value = valCmd.value()
+	} else {
+		value = cmd.ValueAsLit()
+	}
+
+	return Reading{
+		Key:    cmd.Key(),
+		Value:  value,
+		Unit:   GetCommandUnit(cmd),
+		Time:   at,
+		Schema: SchemaVersion,
+	}
+}
+
+// MarshalJSON encodes the reading as a JSON object, leaving out the timestamp
+// when it is the zero time. Readings without a schema are encoded with the
+// current SchemaVersion.
+func (reading Reading) MarshalJSON() ([]byte, error) {
+	// The alias type doesn't have the MarshalJSON method, which avoids endless
+	// recursion when encoding the fields.
+	type fields Reading
+
+	reading.Schema = schemaOf(reading.Schema)
+
+	if reading.Time.IsZero() {
+		return json.Marshal(struct {
+			fields
+			Time *time.Time `json:"timestamp,omitempty"`
+		}{fields: fields(reading)})
+	}
+
+	return json.Marshal(fields(reading))
+}
+
+// AsReading creates a Reading from the given processed command without a
+// timestamp.
+func AsReading(cmd OBDCommand) Reading {
+	return NewReading(cmd, time.Time{})
+}
+
+// MarshalCommandJSON encodes the value of the given processed command as JSON,
+// in the same way for all commands:
+//
+//	{"key":"engine_rpm","value":2412.5,"unit":"rpm","schema":1}
+func MarshalCommandJSON(cmd OBDCommand) ([]byte, error) {
+	return json.Marshal(AsReading(cmd))
+}
+
+// Float64 returns the value of the reading as a float64, the second return
+// value is false if the value is not a number.
+func (reading Reading) Float64() (float64, bool) {
+	switch val := reading.Value.(type) {
+	case float32:
+		return float64(val), true
+	case float64:
+		return val, true
+	case int:
+		return float64(val), true
+	case uint32:
+		return float64(val), true
+	default:
+		return 0, false
+	}
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// valueCommand is implemented by all commands defined in this library, either
+// through embedding FloatCommand, IntCommand, UIntCommand and
+// ResultLessCommand or directly.
+type valueCommand interface {
+	value() interface{}
+}
@@ -0,0 +1,59 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestMarshalCommandJSON(t *testing.T) {
+	rpm := NewEngineRPM()
+	rpm.Value = 2412.5
+
+	status := NewMonitorStatus()
+	status.MilActive = true
+	status.DtcAmount = 3
+
+	type scenario struct {
+		command OBDCommand
+		json    string
+	}
+
+	scenarios := []scenario{
+		{rpm, `{"key":"engine_rpm","value":2412.5,"unit":"rpm","schema":1}`},
+		{NewOBDStandards(), `{"key":"obd_standards","value":0,"schema":1}`},
+		{status, `{"key":"monitor_status","value":{"mil_active":true,"dtc_amount":3,"monitors":{"compression_ignition":false,"tests":null}},"schema":1}`},
+		{NewClearTroubleCodes(), `{"key":"clear_trouble_codes","value":null,"schema":1}`},
+	}
+
+	for _, scen := range scenarios {
+		encoded, err := MarshalCommandJSON(scen.command)
+
+		assertSuccess(t, err)
+		assertEqual(t, string(encoded), scen.json)
+	}
+}
+
+func TestReadingJSONTimestamp(t *testing.T) {
+	at := time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC)
+	encoded, err := json.Marshal(NewReading(NewVehicleSpeed(), at))
+
+	assertSuccess(t, err)
+	assertEqual(
+		t,
+		string(encoded),
+		`{"key":"vehicle_speed","value":0,"unit":"km/h","timestamp":"2022-09-08T12:00:00Z","schema":1}`,
+	)
+}
+
+func TestMonitorStatusValueAsLitIsJSON(t *testing.T) {
+	status := NewMonitorStatus()
+	decoded := map[string]interface{}{}
+
+	assertSuccess(t, json.Unmarshal([]byte(status.ValueAsLit()), &decoded))
+	assertEqual(t, decoded["mil_active"], false)
+}
@@ -4,14 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"net"
-	"net/url"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/tarm/serial"
 )
 
 /*==============================================================================
@@ -45,6 +40,16 @@ func (res *RealResult) GetOutputs() []string {
 	return res.outputs
 }
 
+// GetWriteTime returns how long it took to write the command
+func (res *RealResult) GetWriteTime() time.Duration {
+	return res.writeTime
+}
+
+// GetReadTime returns how long it took to read the outputs
+func (res *RealResult) GetReadTime() time.Duration {
+	return res.readTime
+}
+
 // FormatOverview formats a result as an overview of what command was run and
 // how long it took.
 func (res *RealResult) FormatOverview() string {
@@ -65,6 +70,20 @@ func (res *RealResult) FormatOverview() string {
 	)
 }
 
+// Conn is the connection to an ELM327 device that a RealDevice talks over,
+// such as a serial port or a TCP connection. Besides reading, writing and
+// closing, Flush discards the data not yet read or written, which is done
+// before resetting the device. Connections without such buffers, such as
+// network connections, return nil.
+//
+// Reads are expected to time out rather than block forever when the device
+// does not answer. Serial ports do so themselves, connections that also
+// implement SetReadDeadline(time.Time) error are given a deadline before each
+// read, see NewRealDevice.
+//
+// A transport registered with RegisterTransport opens its Conn in the
+// function given to NewRealDevice, and returns the RealDevice from its
+// OpenFunc.
 type Conn interface {
 	io.ReadWriteCloser
 	Flush() error
@@ -72,59 +91,50 @@ type Conn interface {
 
 // RealDevice represent the low level serial connection.
 type RealDevice struct {
-	mutex   sync.Mutex
-	state   deviceState
-	input   string
-	outputs []string
-	conn    Conn
+	mutex    sync.Mutex
+	state    DeviceState
+	input    string
+	outputs  []string
+	conn     Conn
+	open     func() (Conn, error)
+	openAt   func(baud int) (Conn, error)
+	baud     int
+	baseBaud int
+	echoOff  bool
+	// resetDelay and resetProbe work around clones that are not ready right
+	// after a reset, see ResetTuner
+	resetDelay time.Duration
+	resetProbe bool
+	lenient    bool
+	// readTimeout is how long a read waits for the device on connections
+	// that time out reads through deadlines, serial ports time out reads
+	// themselves
+	readTimeout time.Duration
+	readBuffer  []byte
 }
 
-// NewSerialDevice creates a new low-level ELM327 device manager by connecting to
-// the device at given path.
+// NewRealDevice creates a new low-level ELM327 device manager on the
+// connection opened by the given function, which is called again when the
+// device is reopened (see Reopen). The transports use it to create their
+// devices, see RegisterTransport.
+//
+// On connections that time out reads through deadlines, such as network
+// connections, a read waits for the device for the given timeout. Zero waits
+// forever, serial ports time out reads themselves.
 //
 // After a connection has been established the device is reset, and a minimum of
 // 800 ms blocking wait will occur. This makes sure the device does not have
 // any custom settings that could make this library handle the device
 // incorrectly.
-func NewSerialDevice(addr *url.URL) (*RealDevice, error) {
-	config := &serial.Config{
-		Name:        addr.Path,
-		Baud:        38400,
-		ReadTimeout: time.Second * 5,
-		Size:        8,
-		Parity:      serial.ParityNone,
-		StopBits:    serial.Stop1,
-	}
-
-	q := addr.Query()
-	baudStr := q.Get("baudrate")
-	timeoutStr := q.Get("timeout")
-
-	if baudStr != "" {
-		if baud, err := strconv.Atoi(baudStr); err == nil {
-			config.Baud = baud
-		}
-	}
-
-	if timeoutStr != "" {
-		if to, err := time.ParseDuration(timeoutStr); err == nil {
-			config.ReadTimeout = to
-		}
-	}
-
-	port, err := serial.OpenPort(config)
-
-	if err != nil {
-		return nil, err
-	}
-
+func NewRealDevice(open func() (Conn, error), readTimeout time.Duration) (*RealDevice, error) {
 	dev := &RealDevice{
-		state: deviceReady,
-		mutex: sync.Mutex{},
-		conn:  port,
+		state:       DeviceReady,
+		mutex:       sync.Mutex{},
+		open:        open,
+		readTimeout: readTimeout,
 	}
 
-	err = dev.Reset()
+	err := dev.connect()
 
 	if err != nil {
 		return nil, err
@@ -133,39 +143,24 @@ func NewSerialDevice(addr *url.URL) (*RealDevice, error) {
 	return dev, nil
 }
 
-type netConn struct {
-	net.Conn
-}
-
-func (t *netConn) Flush() error {
-	return nil
-}
-
-func NewNetDevice(u *url.URL) (*RealDevice, error) {
-	var network = u.Scheme
-	var address string
-
-	switch network {
-	case "tcp", "tcp4", "tcp6":
-		address = u.Host
-
-	case "unix":
-		address = u.Opaque
+// NewBaudRealDevice works like NewRealDevice, for connections with a baud
+// rate such as serial ports. The given function opens the connection at the
+// given baud rate, which makes it possible to switch to a faster rate with
+// SetBaudRate.
+func NewBaudRealDevice(openAt func(baud int) (Conn, error), baud int) (*RealDevice, error) {
+	dev := &RealDevice{
+		state:    DeviceReady,
+		mutex:    sync.Mutex{},
+		openAt:   openAt,
+		baud:     baud,
+		baseBaud: baud,
 	}
 
-	conn, err := net.Dial(network, address)
-
-	if err != nil {
-		return nil, err
+	dev.open = func() (Conn, error) {
+		return openAt(baud)
 	}
 
-	dev := &RealDevice{
-		state: deviceReady,
-		mutex: sync.Mutex{},
-		conn:  &netConn{conn},
-	}
-
-	err = dev.Reset()
+	err := dev.connect()
 
 	if err != nil {
 		return nil, err
@@ -174,6 +169,12 @@ func NewNetDevice(u *url.URL) (*RealDevice, error) {
 	return dev, nil
 }
 
+// deadlineConn is implemented by connections that time out reads through
+// deadlines, such as network connections.
+type deadlineConn interface {
+	SetReadDeadline(t time.Time) error
+}
+
 // Reset restarts the device, resets all the settings to factory defaults and
 // makes sure it actually is a ELM327 device we are talking to.
 //
@@ -182,7 +183,7 @@ func (dev *RealDevice) Reset() error {
 	var err error
 
 	dev.mutex.Lock()
-	dev.state = deviceBusy
+	dev.state = DeviceBusy
 
 	err = dev.conn.Flush()
 
@@ -196,8 +197,23 @@ func (dev *RealDevice) Reset() error {
 		goto out
 	}
 
+	// The device goes back to its default baud rate, the echo of ATZ is lost
+	// while the connection follows
+	if dev.baud != dev.baseBaud {
+		err = dev.switchBaud(dev.baseBaud)
+
+		if err != nil {
+			goto out
+		}
+
+		dev.echoOff = true
+	}
+
 	err = dev.read()
 
+	// ATZ turns the echo back on
+	dev.echoOff = false
+
 	if err != nil {
 		goto out
 	}
@@ -216,9 +232,10 @@ func (dev *RealDevice) Reset() error {
 out:
 	if err != nil {
 		dev.conn.Flush()
-		dev.state = deviceError
+		dev.state = DeviceError
 	} else {
-		dev.state = deviceReady
+		dev.settleAfterReset()
+		dev.state = DeviceReady
 	}
 
 	dev.mutex.Unlock()
@@ -230,9 +247,9 @@ out:
 // waiting for the output. There are no restrictions on what commands you can
 // run with this function, so be careful.
 //
-// WARNING: Do not turn off echoing, because the underlying write function
-// relies on echo being on so that it can compare the input command and the
-// echo from the device.
+// Turning off echoing (ATE0) is supported, the device keeps track of whether
+// the echo of the command should be compared to the command sent. Use Reset
+// rather than running ATZ, so that the baud rate is followed as well.
 //
 // For more information about AT/OBD commands, see:
 // https://en.wikipedia.org/wiki/Hayes_command_set
@@ -253,7 +270,7 @@ func (dev *RealDevice) RunCommand(command string) RawResult {
 	startTotal = time.Now()
 
 	dev.mutex.Lock()
-	dev.state = deviceBusy
+	dev.state = DeviceBusy
 
 	startWrite = time.Now()
 
@@ -274,12 +291,14 @@ func (dev *RealDevice) RunCommand(command string) RawResult {
 	if err != nil {
 		goto out
 	}
+
+	dev.trackEcho(command)
 out:
 	if err != nil {
 		dev.conn.Flush()
-		dev.state = deviceError
+		dev.state = DeviceError
 	} else {
-		dev.state = deviceReady
+		dev.state = DeviceReady
 	}
 
 	dev.mutex.Unlock()
@@ -291,17 +310,181 @@ out:
 	return &result
 }
 
+// Interrupt stops the command the device is currently processing, by sending
+// a single character to it and waiting for the prompt. Any output of the
+// interrupted command is discarded.
+func (dev *RealDevice) Interrupt() error {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	dev.input = ""
+
+	_, err := dev.conn.Write([]byte("\r"))
+
+	if err != nil {
+		return &TransportError{"write", err}
+	}
+
+	_, err = dev.readRaw()
+
+	return err
+}
+
+// WarmStart restarts the device like Reset does, but without the power on
+// LED test, and keeps the baud rate of the connection.
+func (dev *RealDevice) WarmStart() error {
+	res := dev.RunCommand("ATWS")
+
+	return res.GetError()
+}
+
+// Reopen closes the connection to the device and connects to it again, then
+// resets the device. Used to recover a device that has stopped responding
+// while keeping the connection open.
+func (dev *RealDevice) Reopen() error {
+	dev.mutex.Lock()
+
+	dev.conn.Close()
+
+	var conn Conn
+	var err error
+
+	if dev.openAt != nil {
+		conn, err = dev.openAt(dev.baud)
+	} else {
+		conn, err = dev.open()
+	}
+
+	if err == nil {
+		dev.conn = conn
+	}
+
+	dev.mutex.Unlock()
+
+	if err != nil {
+		return &TransportError{"open", err}
+	}
+
+	return dev.Reset()
+}
+
+// SetBaudRate switches the connection to the device to the given baud rate,
+// such as 115200, by asking the device to switch with ATBRD and reopening the
+// serial port at the new rate. Only serial devices support it.
+//
+// The device goes back to its default baud rate when it is reset, and so
+// does the connection (see Reset). When the switch fails the device and the
+// connection stay at the current baud rate.
+func (dev *RealDevice) SetBaudRate(baud int) error {
+	if dev.openAt == nil {
+		return fmt.Errorf("baud rate can only be changed on serial devices")
+	}
+
+	if baud <= 0 {
+		return fmt.Errorf("unsupported baud rate: %d", baud)
+	}
+
+	divisor := (baudRateClock + baud/2) / baud
+
+	if divisor < 8 || divisor > 0xFF {
+		return fmt.Errorf("unsupported baud rate: %d", baud)
+	}
+
+	// Give the host as much time as possible to reopen the port before the
+	// device gives up on the new baud rate
+	res := dev.RunCommand("ATBRT00")
+
+	if res.Failed() {
+		return res.GetError()
+	}
+
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	old := dev.baud
+
+	command := fmt.Sprintf("ATBRD%02X", divisor)
+
+	if _, err := dev.write(command); err != nil {
+		return err
+	}
+
+	// The device answers OK without a prompt when it is about to switch
+	buffer, err := dev.readUntil(func(buf []byte) bool {
+		return bytes.Contains(buf, []byte("OK")) || bytes.HasSuffix(buf, []byte(">"))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if bytes.Contains(buffer, []byte("?")) {
+		return &UnknownCommandError{Input: command}
+	}
+
+	if !bytes.Contains(buffer, []byte("OK")) {
+		return fmt.Errorf("device does not support baud rate %d: %q", baud, buffer)
+	}
+
+	if err := dev.switchBaud(baud); err != nil {
+		return err
+	}
+
+	err = dev.confirmBaud()
+
+	if err != nil {
+		// The device goes back to the old baud rate when the new rate is not
+		// confirmed in time
+		dev.switchBaud(old)
+
+		return fmt.Errorf("failed to switch to baud rate %d: %w", baud, err)
+	}
+
+	return nil
+}
+
+// SetResetDelay sets the time waited after the device has been reset before
+// the next command is sent, see ResetTuner.
+func (dev *RealDevice) SetResetDelay(delay time.Duration) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	dev.resetDelay = delay
+}
+
+// SetResetProbe makes the device send a throwaway command after it has been
+// reset, see ResetTuner.
+func (dev *RealDevice) SetResetProbe(probe bool) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	dev.resetProbe = probe
+}
+
+// Close closes the connection to the device.
+func (dev *RealDevice) Close() error {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	return dev.conn.Close()
+}
+
 /*==============================================================================
  * Internal
  */
 
-type deviceState int
+// connect opens the connection to the device and resets the device.
+func (dev *RealDevice) connect() error {
+	conn, err := dev.open()
 
-const (
-	deviceReady deviceState = iota
-	deviceBusy
-	deviceError
-)
+	if err != nil {
+		return &TransportError{"open", err}
+	}
+
+	dev.conn = conn
+
+	return dev.Reset()
+}
 
 func (dev *RealDevice) write(input string) (int, error) {
 	dev.input = ""
@@ -310,55 +493,189 @@ func (dev *RealDevice) write(input string) (int, error) {
 		[]byte(input + "\r\n"),
 	)
 
-	if err == nil {
-		dev.input = input
+	if err != nil {
+		return n, &TransportError{"write", err}
 	}
 
-	return n, err
+	dev.input = input
+
+	return n, nil
+}
+
+// settleAfterReset waits for the device after it has been reset and sends
+// the throwaway command, if set, whose answer is discarded.
+func (dev *RealDevice) settleAfterReset() {
+	if dev.resetDelay > 0 {
+		time.Sleep(dev.resetDelay)
+	}
+
+	if !dev.resetProbe {
+		return
+	}
+
+	if _, err := dev.write("ATI"); err == nil {
+		dev.readRaw()
+	}
+
+	dev.conn.Flush()
 }
 
 func (dev *RealDevice) read() error {
-	var buffer bytes.Buffer
+	buffer, err := dev.readRaw()
 
-	ticker := time.NewTicker(10 * time.Millisecond)
+	if err != nil {
+		dev.outputs = []string{}
+		return err
+	}
 
-	for range ticker.C {
-		tmp := make([]byte, 128)
-		n, err := dev.conn.Read(tmp)
+	return dev.processResult(buffer)
+}
 
-		if err != nil {
-			dev.outputs = []string{}
-			return err
+// readRaw reads from the device until the prompt is received, and returns
+// what was read before the prompt. In lenient mode NULs sent after the prompt
+// are ignored.
+func (dev *RealDevice) readRaw() ([]byte, error) {
+	buffer, err := dev.readUntil(func(buf []byte) bool {
+		if dev.lenient {
+			buf = bytes.TrimRight(buf, "\x00")
 		}
 
-		buffer.Write(tmp[:n])
+		return len(buf) > 0 && buf[len(buf)-1] == byte('>')
+	})
+
+	if err != nil {
+		return buffer, err
+	}
 
-		if tmp[n-1] == byte('>') {
-			buffer.Truncate(buffer.Len() - 1)
-			ticker.Stop()
+	return buffer[:bytes.LastIndexByte(buffer, '>')], nil
+}
 
-			break
+// readUntil reads from the device until the given function returns true for
+// what has been read so far. Each read blocks until the device sends
+// something, or until the read timeout of the connection passes.
+//
+// The read buffer is reused, so the returned bytes are only valid until the
+// next read.
+func (dev *RealDevice) readUntil(done func(buf []byte) bool) ([]byte, error) {
+	if dev.readBuffer == nil {
+		dev.readBuffer = make([]byte, 0, readBufferSize)
+	}
+
+	buffer := dev.readBuffer[:0]
+	deadlines, hasDeadlines := dev.conn.(deadlineConn)
+
+	for {
+		if len(buffer) == cap(buffer) {
+			buffer = append(buffer, make([]byte, readBufferSize)...)[:len(buffer)]
+		}
+
+		if hasDeadlines && dev.readTimeout > 0 {
+			if err := deadlines.SetReadDeadline(time.Now().Add(dev.readTimeout)); err != nil {
+				return buffer, &TransportError{"read", err}
+			}
+		}
+
+		n, err := dev.conn.Read(buffer[len(buffer):cap(buffer)])
+		buffer = buffer[:len(buffer)+n]
+
+		// Keep the grown buffer for the next read
+		dev.readBuffer = buffer
+
+		if n > 0 && done(buffer) {
+			return buffer, nil
+		}
+
+		if err != nil {
+			return buffer, &TransportError{"read", err}
 		}
 	}
+}
 
-	return dev.processResult(buffer)
+// readBufferSize is the initial size of the read buffer, and how much it
+// grows by when a response does not fit.
+const readBufferSize = 256
+
+// baudRateClock is the clock divided by the divisor given to ATBRD.
+const baudRateClock = 4000000
+
+// switchBaud reopens the serial port at the given baud rate.
+func (dev *RealDevice) switchBaud(baud int) error {
+	dev.conn.Close()
+
+	conn, err := dev.openAt(baud)
+
+	if err != nil {
+		return &TransportError{"open", err}
+	}
+
+	dev.conn = conn
+	dev.baud = baud
+
+	return nil
 }
 
-func (dev *RealDevice) processResult(result bytes.Buffer) error {
+// confirmBaud waits for the device to identify itself at the new baud rate
+// and confirms the rate by answering with a carriage return.
+func (dev *RealDevice) confirmBaud() error {
+	_, err := dev.readUntil(func(buf []byte) bool {
+		return bytes.Contains(buf, []byte("ELM327")) && buf[len(buf)-1] == '\r'
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := dev.conn.Write([]byte("\r")); err != nil {
+		return &TransportError{"write", err}
+	}
+
+	buffer, err := dev.readRaw()
+
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(buffer, []byte("OK")) {
+		return fmt.Errorf("device did not confirm the baud rate: %q", buffer)
+	}
+
+	return nil
+}
+
+// trackEcho keeps track of whether the device echoes the commands, after
+// the given command has been run.
+func (dev *RealDevice) trackEcho(command string) {
+	switch strings.ToUpper(strings.ReplaceAll(command, " ", "")) {
+	case "ATE0":
+		dev.echoOff = true
+	case "ATE1", "ATZ", "ATWS", "ATD":
+		dev.echoOff = false
+	}
+}
+
+func (dev *RealDevice) processResult(result []byte) error {
+	output := string(result)
+
+	if dev.lenient {
+		output = normalizeOutput(output)
+	}
+
 	parts := strings.Split(
-		string(result.Bytes()),
+		output,
 		"\r",
 	)
 
-	if parts[0] != dev.input {
-		return fmt.Errorf(
-			"Write echo mismatch: %q not suffix of %q",
-			dev.input,
-			parts[0],
-		)
-	}
+	if !dev.echoOff {
+		if !echoMatches(parts[0], dev.input, dev.lenient) {
+			return fmt.Errorf(
+				"Write echo mismatch: %q not suffix of %q",
+				dev.input,
+				parts[0],
+			)
+		}
 
-	parts = parts[1:]
+		parts = parts[1:]
+	}
 
 	var trimmedParts []string
 
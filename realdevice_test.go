@@ -0,0 +1,218 @@
+package elmobd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// fakeConn answers what is written to it with the given responses, as the
+// ELM327 device would over the serial port.
+type fakeConn struct {
+	responses map[string]string
+	pending   bytes.Buffer
+	written   []string
+	closed    bool
+}
+
+func newFakeConn(initial string, responses map[string]string) *fakeConn {
+	conn := &fakeConn{responses: responses}
+	conn.pending.WriteString(initial)
+
+	return conn
+}
+
+func (conn *fakeConn) Write(p []byte) (int, error) {
+	conn.written = append(conn.written, string(p))
+	conn.pending.WriteString(conn.responses[string(p)])
+
+	return len(p), nil
+}
+
+func (conn *fakeConn) Read(p []byte) (int, error) {
+	if conn.pending.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	return conn.pending.Read(p)
+}
+
+func (conn *fakeConn) Close() error {
+	conn.closed = true
+
+	return nil
+}
+
+func (conn *fakeConn) Flush() error {
+	return nil
+}
+
+func TestRealDeviceEchoOff(t *testing.T) {
+	conn := newFakeConn("", map[string]string{
+		"ATE0\r\n":  "ATE0\rOK\r\r>",
+		"010D1\r\n": "41 0D 4B\r\r>",
+		"ATE1\r\n":  "OK\r\r>",
+	})
+	dev := &RealDevice{conn: conn}
+
+	res := dev.RunCommand("ATE0")
+
+	assertSuccess(t, res.GetError())
+
+	res = dev.RunCommand("010D1")
+
+	assertSuccess(t, res.GetError())
+	assertEqual(t, fmt.Sprint(res.GetOutputs()), "[41 0D 4B]")
+
+	res = dev.RunCommand("ATE1")
+
+	assertSuccess(t, res.GetError())
+
+	conn.responses["010D1\r\n"] = "010D1\r41 0D 4B\r\r>"
+
+	res = dev.RunCommand("010D1")
+
+	assertSuccess(t, res.GetError())
+	assertEqual(t, fmt.Sprint(res.GetOutputs()), "[41 0D 4B]")
+}
+
+func TestRealDeviceSetBaudRate(t *testing.T) {
+	slow := newFakeConn("", map[string]string{
+		"ATBRT00\r\n": "ATBRT00\rOK\r\r>",
+		"ATBRD23\r\n": "ATBRD23\rOK\r",
+	})
+	fast := newFakeConn("ELM327 v1.5\r", map[string]string{
+		"\r":        "OK\r\r>",
+		"010D1\r\n": "010D1\r41 0D 4B\r\r>",
+		"ATZ\r\n":   "",
+	})
+	reset := newFakeConn("\r\rELM327 v1.5\r\r>", nil)
+
+	var bauds []int
+
+	dev := &RealDevice{
+		conn:     slow,
+		baud:     38400,
+		baseBaud: 38400,
+		openAt: func(baud int) (Conn, error) {
+			bauds = append(bauds, baud)
+
+			if baud == 115200 {
+				return fast, nil
+			}
+
+			return reset, nil
+		},
+	}
+
+	assertSuccess(t, dev.SetBaudRate(115200))
+	assertEqual(t, dev.baud, 115200)
+	assertEqual(t, slow.closed, true)
+
+	res := dev.RunCommand("010D1")
+
+	assertSuccess(t, res.GetError())
+	assertEqual(t, fmt.Sprint(res.GetOutputs()), "[41 0D 4B]")
+
+	// Resetting the device brings the connection back to the default rate
+	assertSuccess(t, dev.Reset())
+	assertEqual(t, dev.baud, 38400)
+	assertEqual(t, fmt.Sprint(bauds), "[115200 38400]")
+
+	assert(t, dev.SetBaudRate(10) != nil, "Expected unsupported baud rate to fail")
+	assert(
+		t,
+		(&RealDevice{}).SetBaudRate(115200) != nil,
+		"Expected device without serial port to fail",
+	)
+}
+
+func TestRealDeviceSetBaudRateUnsupported(t *testing.T) {
+	conn := newFakeConn("", map[string]string{
+		"ATBRT00\r\n": "ATBRT00\rOK\r\r>",
+		"ATBRD23\r\n": "ATBRD23\r?\r\r>",
+	})
+	dev := &RealDevice{
+		conn:     conn,
+		baud:     38400,
+		baseBaud: 38400,
+		openAt: func(baud int) (Conn, error) {
+			return nil, fmt.Errorf("port should not be reopened")
+		},
+	}
+
+	err := dev.SetBaudRate(115200)
+
+	assert(t, errors.Is(err, ErrUnknownCommand), "Expected unsupported ATBRD to fail")
+	assertEqual(t, dev.baud, 38400)
+	assertEqual(t, conn.closed, false)
+}
+
+func TestRealDeviceReadLargeResponse(t *testing.T) {
+	var response strings.Builder
+	var expected []string
+
+	response.WriteString("0902\r")
+
+	for i := 0; i < 60; i++ {
+		line := fmt.Sprintf("%X: 49 02 01 31 44 34", i%16)
+
+		expected = append(expected, line)
+		response.WriteString(line + "\r")
+	}
+
+	response.WriteString("\r>")
+
+	conn := newFakeConn("", map[string]string{
+		"0902\r\n":  response.String(),
+		"010D1\r\n": "010D1\r41 0D 4B\r\r>",
+	})
+	dev := &RealDevice{conn: conn}
+
+	res := dev.RunCommand("0902")
+
+	assertSuccess(t, res.GetError())
+	assertEqual(t, fmt.Sprint(res.GetOutputs()), fmt.Sprint(expected))
+
+	// The grown buffer is reused for the next response
+	res = dev.RunCommand("010D1")
+
+	assertSuccess(t, res.GetError())
+	assertEqual(t, fmt.Sprint(res.GetOutputs()), "[41 0D 4B]")
+	assert(t, cap(dev.readBuffer) > readBufferSize, "Expected read buffer to be kept")
+}
+
+// pipeConn is one end of an in-memory network connection.
+type pipeConn struct {
+	net.Conn
+}
+
+func (conn *pipeConn) Flush() error {
+	return nil
+}
+
+func TestRealDeviceReadTimeout(t *testing.T) {
+	client, server := net.Pipe()
+
+	defer server.Close()
+
+	go func() {
+		// Read the command, but never answer it
+		server.Read(make([]byte, 16))
+	}()
+
+	dev := &RealDevice{conn: &pipeConn{client}, readTimeout: 20 * time.Millisecond}
+	res := dev.RunCommand("010D1")
+
+	assert(t, res.Failed(), "Expected read to time out")
+	assert(t, IsTimeoutError(res.GetError()), "Expected a timeout error")
+}
@@ -0,0 +1,155 @@
+package elmobd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// DIDRange represents a range of data identifiers (DIDs) of service 22, from
+// First to Last including both.
+type DIDRange struct {
+	First uint16 `json:"first"`
+	Last  uint16 `json:"last"`
+}
+
+// ScanEntry represents a PID that answered when scanning the car, see
+// Device.Scan.
+type ScanEntry struct {
+	// Service is the service of the PID, such as 0x01.
+	Service byte `json:"service"`
+	// PID is the PID, or the DID for service 22.
+	PID uint16 `json:"pid"`
+	// Name is the key of the command reading the PID, if there is one.
+	Name string `json:"name,omitempty"`
+	// Width is the amount of bytes of the payload.
+	Width int `json:"width"`
+	// Payload is the payload of the answer as hex, such as "0C10".
+	Payload string `json:"payload"`
+}
+
+// ScanReport represents the PIDs the car answers, see Device.Scan.
+type ScanReport struct {
+	Schema int       `json:"schema"`
+	Time   time.Time `json:"timestamp"`
+	// Probed is the amount of PIDs that were probed.
+	Probed int `json:"probed"`
+	// Entries are the PIDs that answered, by service and PID.
+	Entries []ScanEntry `json:"pids"`
+}
+
+// Scan probes every PID of service 01, of freeze frame 0 of service 02 and
+// of service 09, and the DIDs of service 22 in the given ranges, recording the
+// ones the car answers along with the width of their payload. Unlike
+// CheckSupportedCommands it does not rely on what the car reports as
+// supported, which makes it useful for reverse-engineering undocumented cars.
+//
+// PIDs answered with NO DATA, a negative response or an answer that can not
+// be parsed are left out. The scan is stopped when the device fails or can not
+// connect to the car. As it runs a command for each PID it takes a while, such
+// as a minute at 80 ms per command.
+//
+// The report is meant to be stored as JSON, such as:
+//
+//	{"schema":1,"timestamp":"...","probed":768,"pids":[
+//	  {"service":1,"pid":12,"name":"engine_rpm","width":2,"payload":"0300"}
+//	]}
+func (dev *Device) Scan(didRanges ...DIDRange) (*ScanReport, error) {
+	report := &ScanReport{
+		Schema:  SchemaVersion,
+		Time:    time.Now(),
+		Entries: []ScanEntry{},
+	}
+
+	for _, service := range []byte{SERVICE_01_ID, SERVICE_02_ID, SERVICE_09_ID} {
+		for pid := 0; pid <= 0xFF; pid++ {
+			if err := dev.scanPID(report, service, uint16(pid)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, didRange := range didRanges {
+		for did := int(didRange.First); did <= int(didRange.Last); did++ {
+			if err := dev.scanPID(report, SERVICE_22_ID, uint16(did)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// scanPID probes the given PID of the given service, adding it to the given
+// report if the car answers it.
+func (dev *Device) scanPID(report *ScanReport, service byte, pid uint16) error {
+	// The answer echoes the request, which is the PID (and the frame for
+	// service 02, and the DID for service 22)
+	echo := []byte{byte(pid)}
+
+	switch service {
+	case SERVICE_02_ID:
+		echo = []byte{byte(pid), 0x00}
+	case SERVICE_22_ID:
+		echo = []byte{byte(pid >> 8), byte(pid)}
+	}
+
+	command := fmt.Sprintf("%02X%X", service, echo)
+	rawRes := dev.runCommand(command)
+
+	report.Probed++
+
+	if rawRes.Failed() {
+		return fmt.Errorf("failed to probe %s: %w", command, rawRes.GetError())
+	}
+
+	if dev.outputDebug {
+		fmt.Println(rawRes.FormatOverview())
+	}
+
+	result, err := parseOBDResponse(nil, rawRes.GetOutputs())
+
+	if errors.Is(err, ErrUnableToConnect) {
+		return fmt.Errorf("failed to probe %s: %w", command, err)
+	}
+
+	if err != nil || result == nil {
+		return nil
+	}
+
+	value := result.value
+
+	if len(value) < len(echo)+1 || value[0] != service+0x40 || string(value[1:len(echo)+1]) != string(echo) {
+		return nil
+	}
+
+	entry := ScanEntry{
+		Service: service,
+		PID:     pid,
+		Width:   len(value) - len(echo) - 1,
+		Payload: fmt.Sprintf("%X", value[len(echo)+1:]),
+	}
+
+	switch service {
+	case SERVICE_01_ID, SERVICE_02_ID:
+		entry.Name = pidName(SERVICE_01_ID, OBDParameterID(pid))
+	case SERVICE_09_ID:
+		entry.Name = pidName(SERVICE_09_ID, OBDParameterID(pid))
+	}
+
+	if entry.Name == "unknown" {
+		entry.Name = ""
+	}
+
+	report.Entries = append(report.Entries, entry)
+
+	return nil
+}
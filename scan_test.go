@@ -0,0 +1,75 @@
+package elmobd
+
+import (
+	"errors"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestScan(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	raw.setResponse("22F190", "62 F1 90 57 30 4C")
+	raw.setResponse("22F191", "7F 22 31")
+
+	report, err := dev.Scan(DIDRange{0xF18F, 0xF191})
+
+	assertSuccess(t, err)
+	assertEqual(t, report.Schema, SchemaVersion)
+	assertEqual(t, report.Probed, 3*256+3)
+
+	entries := map[[2]uint16]ScanEntry{}
+
+	for _, entry := range report.Entries {
+		entries[[2]uint16{uint16(entry.Service), entry.PID}] = entry
+	}
+
+	rpm := entries[[2]uint16{0x01, 0x0C}]
+
+	assertEqual(t, rpm.Name, "engine_rpm")
+	assertEqual(t, rpm.Width, 2)
+	assertEqual(t, rpm.Payload, "0300")
+
+	frameRPM := entries[[2]uint16{0x02, 0x0C}]
+
+	assertEqual(t, frameRPM.Name, "engine_rpm")
+	assertEqual(t, frameRPM.Payload, "0300")
+
+	_, ok := entries[[2]uint16{0x02, 0x10}]
+
+	assertEqual(t, ok, false)
+
+	info := entries[[2]uint16{0x09, 0x00}]
+
+	assertEqual(t, info.Width, 4)
+	assertEqual(t, info.Payload, "55400000")
+
+	did := entries[[2]uint16{0x22, 0xF190}]
+
+	assertEqual(t, did.Name, "")
+	assertEqual(t, did.Width, 3)
+	assertEqual(t, did.Payload, "57304C")
+
+	_, ok = entries[[2]uint16{0x22, 0xF191}]
+
+	assertEqual(t, ok, false)
+
+	_, ok = entries[[2]uint16{0x22, 0xF18F}]
+
+	assertEqual(t, ok, false)
+}
+
+func TestScanUnableToConnect(t *testing.T) {
+	raw := &MockDevice{}
+	dev := &Device{rawDevice: raw}
+
+	raw.setResponse("0100", "UNABLE TO CONNECT")
+
+	_, err := dev.Scan()
+
+	assert(t, errors.Is(err, ErrUnableToConnect), "Expected ErrUnableToConnect")
+}
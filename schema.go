@@ -0,0 +1,69 @@
+package elmobd
+
+import (
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// SchemaVersion is the version of the data model shared by all exporters,
+// which is made up of Reading, Snapshot and DTCReport. It is included in
+// everything exported, as the schema field of the JSON encoding (used by
+// MQTTPublisher, WebhookSink and Server), the schema column of CSVLogger (see
+// CSVSchema) and the schema field of the protobuf messages (see
+// readings.proto).
+//
+// Within a version fields are only added, never renamed, removed or changed
+// in meaning, so consumers should ignore the fields they do not know. The
+// version is increased when a change would break existing consumers.
+const SchemaVersion = 1
+
+// Snapshot represents the readings of several commands taken together, such
+// as the sensors read in one round of polling.
+type Snapshot struct {
+	Schema   int       `json:"schema"`
+	Time     time.Time `json:"timestamp"`
+	Readings []Reading `json:"readings"`
+}
+
+// NewSnapshot creates a new Snapshot of the given processed commands, taken
+// at the given time.
+func NewSnapshot(commands []OBDCommand, at time.Time) Snapshot {
+	readings := make([]Reading, 0, len(commands))
+
+	for _, cmd := range commands {
+		readings = append(readings, NewReading(cmd, at))
+	}
+
+	return Snapshot{
+		Schema:   SchemaVersion,
+		Time:     at,
+		Readings: readings,
+	}
+}
+
+// DTCReport represents the trouble code status of the car at a given point
+// in time, such as for sending it from a logger along with the readings.
+type DTCReport struct {
+	Schema       int           `json:"schema"`
+	Time         time.Time     `json:"timestamp"`
+	MilActive    bool          `json:"mil_active"`
+	TroubleCodes []TroubleCode `json:"trouble_codes"`
+}
+
+// NewDTCReport creates a new DTCReport of the given MIL status and trouble
+// codes, taken at the given time.
+func NewDTCReport(milActive bool, codes []TroubleCode, at time.Time) DTCReport {
+	if codes == nil {
+		codes = []TroubleCode{}
+	}
+
+	return DTCReport{
+		Schema:       SchemaVersion,
+		Time:         at,
+		MilActive:    milActive,
+		TroubleCodes: codes,
+	}
+}
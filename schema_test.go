@@ -0,0 +1,57 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestSnapshotJSON(t *testing.T) {
+	at := time.Date(2022, 9, 8, 12, 0, 0, 0, time.UTC)
+	speed := NewVehicleSpeed()
+	speed.Value = 80
+
+	encoded, err := json.Marshal(NewSnapshot([]OBDCommand{speed}, at))
+
+	assertSuccess(t, err)
+	assertEqual(
+		t,
+		string(encoded),
+		`{"schema":1,"timestamp":"2022-09-08T12:00:00Z","readings":[{"key":"vehicle_speed","value":80,"unit":"km/h","timestamp":"2022-09-08T12:00:00Z","schema":1}]}`,
+	)
+
+	encoded, err = json.Marshal(NewDTCReport(true, nil, at))
+
+	assertSuccess(t, err)
+	assertEqual(
+		t,
+		string(encoded),
+		`{"schema":1,"timestamp":"2022-09-08T12:00:00Z","mil_active":true,"trouble_codes":[]}`,
+	)
+}
+
+func TestCSVLoggerSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elmobd")
+	assertSuccess(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log.csv")
+
+	logger, err := NewCSVLogger(path, CSVKey, CSVSchema)
+	assertSuccess(t, err)
+
+	assertSuccess(t, logger.Log(NewVehicleSpeed()))
+	assertSuccess(t, logger.Close())
+
+	content, err := ioutil.ReadFile(path)
+	assertSuccess(t, err)
+
+	assertEqual(t, string(content), "key,schema\nvehicle_speed,1\n")
+}
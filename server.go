@@ -0,0 +1,201 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Server is an http.Handler exposing the values of a Device as JSON, so that
+// programs not written in Go (such as browser dashboards or Home Assistant
+// REST sensors) can read them:
+//
+//   - GET /sensors gives the readings of all the sensors of the server
+//   - GET /sensors/{key} gives the reading of the command with the given key
+//   - GET /dtc gives the MIL status and the amount of trouble codes
+//   - GET /vehicle gives information about the ELM327 device and the car
+//
+// Each request runs the commands on the device, so the Device is shared by
+// all requests.
+//
+// Use it with the http package, such as:
+//
+//	http.ListenAndServe(":8080", elmobd.NewServer(dev, nil))
+type Server struct {
+	dev  *Device
+	keys []string
+	mux  *http.ServeMux
+	now  func() time.Time
+}
+
+// NewServer creates a new Server reading values from the given device. The
+// given keys are the commands included in /sensors, when empty all defined
+// commands are included (see GetCommandKeys).
+func NewServer(dev *Device, keys []string) *Server {
+	if len(keys) == 0 {
+		keys = GetCommandKeys()
+	}
+
+	srv := &Server{
+		dev:  dev,
+		keys: keys,
+		mux:  http.NewServeMux(),
+		now:  time.Now,
+	}
+
+	srv.mux.HandleFunc("/sensors", srv.handleSensors)
+	srv.mux.HandleFunc("/sensors/", srv.handleSensor)
+	srv.mux.HandleFunc("/dtc", srv.handleDTC)
+	srv.mux.HandleFunc("/vehicle", srv.handleVehicle)
+
+	return srv
+}
+
+// ServeHTTP handles the request using the endpoint for the path of the
+// request.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "only GET is allowed")
+		return
+	}
+
+	srv.mux.ServeHTTP(w, r)
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// sensorsResponse is a Snapshot of the sensors, along with the errors of the
+// sensors that could not be read.
+type sensorsResponse struct {
+	Schema   int               `json:"schema"`
+	Time     time.Time         `json:"timestamp"`
+	Readings []Reading         `json:"readings"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+type dtcResponse struct {
+	Schema    int  `json:"schema"`
+	MilActive bool `json:"mil_active"`
+	DtcAmount byte `json:"dtc_amount"`
+}
+
+type vehicleResponse struct {
+	Version          string  `json:"version"`
+	Voltage          float32 `json:"voltage"`
+	OBDStandards     uint32  `json:"obd_standards"`
+	OBDStandardsName string  `json:"obd_standards_name"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (srv *Server) handleSensors(w http.ResponseWriter, r *http.Request) {
+	resp := sensorsResponse{
+		Schema:   SchemaVersion,
+		Time:     srv.now(),
+		Readings: []Reading{},
+	}
+
+	for _, key := range srv.keys {
+		cmd, ok := NewCommandByKey(key)
+
+		if !ok {
+			continue
+		}
+
+		_, err := srv.dev.RunOBDCommand(cmd)
+
+		if err != nil {
+			if resp.Errors == nil {
+				resp.Errors = map[string]string{}
+			}
+
+			resp.Errors[key] = err.Error()
+
+			continue
+		}
+
+		resp.Readings = append(resp.Readings, NewReading(cmd, srv.now()))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (srv *Server) handleSensor(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/sensors/")
+	cmd, ok := NewCommandByKey(key)
+
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown sensor: "+key)
+		return
+	}
+
+	_, err := srv.dev.RunOBDCommand(cmd)
+
+	if err != nil {
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewReading(cmd, srv.now()))
+}
+
+func (srv *Server) handleDTC(w http.ResponseWriter, r *http.Request) {
+	status, err := Run(srv.dev, NewMonitorStatus())
+
+	if err != nil {
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtcResponse{SchemaVersion, status.MilActive, status.DtcAmount})
+}
+
+func (srv *Server) handleVehicle(w http.ResponseWriter, r *http.Request) {
+	version, err := srv.dev.GetVersion()
+
+	if err != nil {
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	voltage, err := srv.dev.GetVoltage()
+
+	if err != nil {
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	standards, err := Run(srv.dev, NewOBDStandards())
+
+	if err != nil {
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, vehicleResponse{
+		version,
+		voltage,
+		standards.Value,
+		standards.Standard().String(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{msg})
+}
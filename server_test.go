@@ -0,0 +1,70 @@
+package elmobd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func serverGet(t *testing.T, srv *Server, path string, body interface{}) int {
+	rec := httptest.NewRecorder()
+
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	assertEqual(t, rec.Header().Get("Content-Type"), "application/json")
+	assertSuccess(t, json.Unmarshal(rec.Body.Bytes(), body))
+
+	return rec.Code
+}
+
+func TestServerSensors(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	srv := NewServer(dev, []string{"engine_rpm", "vehicle_speed", "fuel_pressure"})
+
+	var sensors struct {
+		Schema   int
+		Readings []map[string]interface{}
+		Errors   map[string]string
+	}
+
+	assertEqual(t, serverGet(t, srv, "/sensors", &sensors), http.StatusOK)
+	assertEqual(t, sensors.Schema, SchemaVersion)
+	assertEqual(t, len(sensors.Readings), 2)
+	assertEqual(t, sensors.Readings[1]["value"], 75.0)
+	assertEqual(t, len(sensors.Errors), 1)
+
+	var reading map[string]interface{}
+
+	assertEqual(t, serverGet(t, srv, "/sensors/engine_rpm", &reading), http.StatusOK)
+	assertEqual(t, reading["value"], 192.0)
+	assertEqual(t, reading["unit"], "rpm")
+	assertEqual(t, reading["schema"], 1.0)
+
+	var failure map[string]string
+
+	assertEqual(t, serverGet(t, srv, "/sensors/nope", &failure), http.StatusNotFound)
+	assertEqual(t, serverGet(t, srv, "/sensors/fuel_pressure", &failure), http.StatusBadGateway)
+}
+
+func TestServerDTCAndVehicle(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+	srv := NewServer(dev, nil)
+
+	var dtc map[string]interface{}
+
+	assertEqual(t, serverGet(t, srv, "/dtc", &dtc), http.StatusOK)
+	assertEqual(t, dtc["mil_active"], true)
+	assertEqual(t, dtc["dtc_amount"], 127.0)
+
+	var vehicle map[string]interface{}
+
+	assertEqual(t, serverGet(t, srv, "/vehicle", &vehicle), http.StatusOK)
+	assertEqual(t, vehicle["obd_standards"], 6.0)
+	assertEqual(t, vehicle["obd_standards_name"], "EOBD (Europe)")
+	assertEqual(t, vehicle["version"], "OBDII by elm329@gmail.com")
+}
@@ -0,0 +1,6 @@
+// Package sqlitetest runs the tests of the TripLogger against a real SQLite
+// database. It is a separate module so that elmobd itself does not depend on
+// a SQLite driver, run the tests from this directory with:
+//
+//	go test ./...
+package sqlitetest
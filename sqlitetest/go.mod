@@ -0,0 +1,10 @@
+module github.com/rzetterberg/elmobd/sqlitetest
+
+go 1.18
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.16
+	github.com/rzetterberg/elmobd v0.0.0
+)
+
+replace github.com/rzetterberg/elmobd => ../
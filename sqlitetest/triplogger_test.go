@@ -0,0 +1,162 @@
+package sqlitetest
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rzetterberg/elmobd"
+)
+
+/*==============================================================================
+ * Utilities
+ */
+
+func assertSuccess(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertEqual(t *testing.T, a interface{}, b interface{}) {
+	t.Helper()
+
+	if a != b {
+		t.Fatalf("Expected %v to equal %v", a, b)
+	}
+}
+
+// openTripLogger opens the SQLite database at the given path and creates a
+// TripLogger on it.
+func openTripLogger(t *testing.T, path string) *elmobd.TripLogger {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", path)
+
+	assertSuccess(t, err)
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	logger, err := elmobd.NewTripLogger(db)
+
+	assertSuccess(t, err)
+
+	return logger
+}
+
+func newRPM(rpm float64) *elmobd.EngineRPM {
+	cmd := elmobd.NewEngineRPM()
+
+	cmd.SetFloat64(rpm)
+
+	return cmd
+}
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestTripLoggerRoundTrip(t *testing.T) {
+	logger := openTripLogger(t, filepath.Join(t.TempDir(), "trips.db"))
+
+	speed := elmobd.NewVehicleSpeed()
+	speed.Value = 75
+
+	fuelType := elmobd.NewFuelType()
+	fuelType.Value = elmobd.FuelDiesel
+
+	assertSuccess(t, logger.Log(newRPM(0)))
+	assertSuccess(t, logger.Log(newRPM(850)))
+	assertSuccess(t, logger.LogMany([]elmobd.OBDCommand{speed, fuelType, newRPM(2400)}))
+	assertSuccess(t, logger.Log(newRPM(0)))
+
+	trips, err := logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(trips), 1)
+
+	readings, err := logger.TripReadings(trips[0].ID, "")
+
+	assertSuccess(t, err)
+	assertEqual(t, len(readings), 4)
+
+	expected := []elmobd.Reading{
+		{Key: "engine_rpm", Value: 850.0, Unit: "rpm"},
+		{Key: "vehicle_speed", Value: 75.0, Unit: "km/h"},
+		{Key: "fuel_type", Value: "diesel", Unit: ""},
+		{Key: "engine_rpm", Value: 2400.0, Unit: "rpm"},
+	}
+
+	for i, reading := range readings {
+		assertEqual(t, reading.Key, expected[i].Key)
+		assertEqual(t, reading.Value, expected[i].Value)
+		assertEqual(t, reading.Unit, expected[i].Unit)
+	}
+
+	assertEqual(t, trips[0].StartedAt.Equal(readings[0].Time), true)
+	assertEqual(t, trips[0].EndedAt.Before(readings[3].Time), false)
+
+	readings, err = logger.TripReadings(trips[0].ID, "engine_rpm")
+
+	assertSuccess(t, err)
+	assertEqual(t, len(readings), 2)
+	assertEqual(t, readings[1].Value, 2400.0)
+}
+
+func TestTripLoggerExplicitTrips(t *testing.T) {
+	logger := openTripLogger(t, filepath.Join(t.TempDir(), "trips.db"))
+
+	assertSuccess(t, logger.StartTrip())
+	assertSuccess(t, logger.StartTrip())
+	assertSuccess(t, logger.EndTrip())
+	assertSuccess(t, logger.EndTrip())
+
+	trips, err := logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(trips), 2)
+	assertEqual(t, trips[0].EndedAt.IsZero(), false)
+	assertEqual(t, trips[1].EndedAt.IsZero(), false)
+}
+
+func TestTripLoggerEndsPreviousTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trips.db")
+	logger := openTripLogger(t, path)
+
+	assertSuccess(t, logger.Log(newRPM(850)))
+	assertSuccess(t, logger.Log(newRPM(900)))
+
+	trips, err := logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, trips[0].EndedAt.IsZero(), true)
+
+	readings, err := logger.TripReadings(trips[0].ID, "")
+
+	assertSuccess(t, err)
+
+	// The logger stopped during the trip, which ends at its last reading
+	logger = openTripLogger(t, path)
+
+	trips, err = logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(trips), 1)
+	assertEqual(t, trips[0].EndedAt.Equal(readings[1].Time), true)
+
+	// A trip without readings ends when it started
+	assertSuccess(t, logger.StartTrip())
+
+	logger = openTripLogger(t, path)
+
+	trips, err = logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(trips), 2)
+	assertEqual(t, trips[1].EndedAt.Equal(trips[1].StartedAt), true)
+}
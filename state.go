@@ -0,0 +1,136 @@
+package elmobd
+
+import (
+	"sync"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// DeviceState represents whether the device is ready to run a command, is
+// running a command or failed the last command.
+type DeviceState int
+
+const (
+	// DeviceReady means the device is idle and the last command succeeded.
+	DeviceReady DeviceState = iota
+	// DeviceBusy means one or more commands are in flight.
+	DeviceBusy
+	// DeviceError means the last command failed, such as the device not
+	// answering. The state goes back to DeviceReady once a command succeeds.
+	DeviceError
+)
+
+// String returns the name of the state.
+func (state DeviceState) String() string {
+	switch state {
+	case DeviceReady:
+		return "ready"
+	case DeviceBusy:
+		return "busy"
+	case DeviceError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// StateEvent represents the device changing from one state to another.
+type StateEvent struct {
+	From DeviceState
+	To   DeviceState
+}
+
+// State returns the current state of the device, such as for displaying the
+// connection status in a UI.
+func (dev *Device) State() DeviceState {
+	return dev.state.get()
+}
+
+// OnStateChange registers a callback that is called every time the state of
+// the device changes, such as for graying out controls while a command is in
+// flight. The callbacks are called from the goroutine running the command.
+func (dev *Device) OnStateChange(callback func(StateEvent)) {
+	dev.state.subscribe(callback)
+}
+
+// State returns the current state of the low level connection.
+func (dev *RealDevice) State() DeviceState {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	return dev.state
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// stateTracker keeps the state of a Device, it is safe to use from multiple
+// goroutines. The state is busy as long as any command is in flight.
+type stateTracker struct {
+	mutex     sync.Mutex
+	state     DeviceState
+	inFlight  int
+	callbacks []func(StateEvent)
+}
+
+func (tracker *stateTracker) get() DeviceState {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	return tracker.state
+}
+
+func (tracker *stateTracker) subscribe(callback func(StateEvent)) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.callbacks = append(tracker.callbacks, callback)
+}
+
+// begin marks a command as in flight, which makes the state busy.
+func (tracker *stateTracker) begin() {
+	tracker.mutex.Lock()
+	tracker.inFlight++
+
+	tracker.set(DeviceBusy)
+}
+
+// end marks a command as finished. Once no other command is in flight the
+// state becomes ready, or error if the command failed.
+func (tracker *stateTracker) end(failed bool) {
+	tracker.mutex.Lock()
+	tracker.inFlight--
+
+	if tracker.inFlight > 0 {
+		tracker.mutex.Unlock()
+
+		return
+	}
+
+	if failed {
+		tracker.set(DeviceError)
+	} else {
+		tracker.set(DeviceReady)
+	}
+}
+
+// set changes the state and notifies the callbacks, if the state changed. The
+// mutex must be held, it is released before notifying the callbacks.
+func (tracker *stateTracker) set(state DeviceState) {
+	event := StateEvent{From: tracker.state, To: state}
+	tracker.state = state
+	callbacks := tracker.callbacks
+
+	tracker.mutex.Unlock()
+
+	if event.From == event.To {
+		return
+	}
+
+	for _, callback := range callbacks {
+		callback(event)
+	}
+}
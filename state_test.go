@@ -0,0 +1,72 @@
+package elmobd
+
+import (
+	"errors"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// failingDevice is a RawDevice where every command fails.
+type failingDevice struct{}
+
+func (dev *failingDevice) RunCommand(command string) RawResult {
+	return &MockResult{input: command, error: errors.New("device gone")}
+}
+
+func TestDeviceStateEvents(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	var events []StateEvent
+
+	dev.OnStateChange(func(event StateEvent) {
+		assertEqual(t, dev.State(), event.To)
+
+		events = append(events, event)
+	})
+
+	assertEqual(t, dev.State(), DeviceReady)
+
+	_, err := dev.RunOBDCommand(NewEngineRPM())
+
+	assertSuccess(t, err)
+	assertEqual(t, len(events), 2)
+	assertEqual(t, events[0], StateEvent{DeviceReady, DeviceBusy})
+	assertEqual(t, events[1], StateEvent{DeviceBusy, DeviceReady})
+
+	dev.rawDevice = &failingDevice{}
+
+	_, err = dev.RunOBDCommand(NewEngineRPM())
+
+	assert(t, err != nil, "Expected command to fail")
+	assertEqual(t, dev.State(), DeviceError)
+	assertEqual(t, events[3], StateEvent{DeviceBusy, DeviceError})
+	assertEqual(t, DeviceError.String(), "error")
+}
+
+func TestDeviceStateConcurrentCommands(t *testing.T) {
+	var tracker stateTracker
+
+	tracker.begin()
+	tracker.begin()
+
+	assertEqual(t, tracker.get(), DeviceBusy)
+
+	// A command failing while another is in flight keeps the state busy
+	tracker.end(true)
+
+	assertEqual(t, tracker.get(), DeviceBusy)
+
+	tracker.end(false)
+
+	assertEqual(t, tracker.get(), DeviceReady)
+
+	tracker.begin()
+	tracker.begin()
+	tracker.end(false)
+	tracker.end(true)
+
+	assertEqual(t, tracker.get(), DeviceError)
+}
@@ -0,0 +1,136 @@
+package elmobd
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Stats represents a snapshot of the counters a Device keeps about the
+// commands it has run, see Device.Stats.
+//
+// Byte counts are based on the command sent and the outputs received, which
+// means the echo, line endings and prompt of the ELM327 device are not
+// included.
+//
+// The latency percentiles are calculated from the most recent commands only
+// (see statsLatencyWindow), so they reflect the current health of the link
+// rather than the whole lifetime of the Device.
+//
+// Retries counts the commands run again after the watchdog recovered the
+// device (see Device.EnableWatchdog), a retried command is counted once in
+// CommandsRun.
+type Stats struct {
+	CommandsRun    uint64
+	CommandsFailed uint64
+	NoDataCount    uint64
+	Retries        uint64
+	BytesWritten   uint64
+	BytesRead      uint64
+	AverageLatency time.Duration
+	LatencyP50     time.Duration
+	LatencyP95     time.Duration
+	LatencyP99     time.Duration
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// statsLatencyWindow is the amount of latencies kept for calculating
+// percentiles.
+const statsLatencyWindow = 256
+
+// statsCollector keeps the counters of a Device, it is safe to use from
+// multiple goroutines.
+type statsCollector struct {
+	mutex        sync.Mutex
+	stats        Stats
+	totalLatency time.Duration
+	latencies    []time.Duration
+	next         int
+}
+
+// record updates the counters with the result of running the given raw
+// command, that took the given amount of time.
+func (col *statsCollector) record(command string, res RawResult, latency time.Duration) {
+	col.mutex.Lock()
+	defer col.mutex.Unlock()
+
+	col.stats.CommandsRun++
+	col.stats.BytesWritten += uint64(len(command))
+
+	if res.Failed() {
+		col.stats.CommandsFailed++
+	}
+
+	for _, out := range res.GetOutputs() {
+		col.stats.BytesRead += uint64(len(out))
+
+		if strings.HasPrefix(out, "NO DATA") {
+			col.stats.NoDataCount++
+		}
+	}
+
+	col.totalLatency += latency
+
+	if len(col.latencies) < statsLatencyWindow {
+		col.latencies = append(col.latencies, latency)
+	} else {
+		col.latencies[col.next] = latency
+		col.next = (col.next + 1) % statsLatencyWindow
+	}
+}
+
+// recordRetry counts a command run again.
+func (col *statsCollector) recordRetry() {
+	col.mutex.Lock()
+	defer col.mutex.Unlock()
+
+	col.stats.Retries++
+}
+
+// snapshot creates a copy of the current counters with the averages and
+// percentiles calculated.
+func (col *statsCollector) snapshot() Stats {
+	col.mutex.Lock()
+	defer col.mutex.Unlock()
+
+	result := col.stats
+
+	if result.CommandsRun == 0 {
+		return result
+	}
+
+	result.AverageLatency = col.totalLatency / time.Duration(result.CommandsRun)
+
+	sorted := make([]time.Duration, len(col.latencies))
+	copy(sorted, col.latencies)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	result.LatencyP50 = latencyPercentile(sorted, 50)
+	result.LatencyP95 = latencyPercentile(sorted, 95)
+	result.LatencyP99 = latencyPercentile(sorted, 99)
+
+	return result
+}
+
+// latencyPercentile picks the given percentile from the sorted latencies using
+// the nearest-rank method.
+func latencyPercentile(sorted []time.Duration, percentile int) time.Duration {
+	rank := (percentile*len(sorted) + 99) / 100
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
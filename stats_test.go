@@ -0,0 +1,72 @@
+package elmobd
+
+import (
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+type noDataDevice struct {
+}
+
+func (dev *noDataDevice) RunCommand(command string) RawResult {
+	return &MockResult{
+		input:   command,
+		outputs: []string{"NO DATA"},
+	}
+}
+
+func TestStatsCounters(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	_, err := dev.RunOBDCommand(NewEngineRPM())
+	assertSuccess(t, err)
+
+	_, err = dev.RunOBDCommand(NewVehicleSpeed())
+	assertSuccess(t, err)
+
+	stats := dev.Stats()
+
+	assertEqual(t, stats.CommandsRun, uint64(2))
+	assertEqual(t, stats.CommandsFailed, uint64(0))
+	assertEqual(t, stats.NoDataCount, uint64(0))
+	assertEqual(t, stats.BytesWritten, uint64(len("010C1")+len("010D1")))
+	assertEqual(t, stats.BytesRead, uint64(len("41 0C 03 00")+len("41 0D 4B")))
+
+	dev.rawDevice = &noDataDevice{}
+
+	_, err = dev.RunOBDCommand(NewEngineRPM())
+	assert(t, err != nil, "NO DATA response fails")
+
+	stats = dev.Stats()
+
+	assertEqual(t, stats.CommandsRun, uint64(3))
+	assertEqual(t, stats.NoDataCount, uint64(1))
+}
+
+func TestStatsLatencyPercentiles(t *testing.T) {
+	col := statsCollector{}
+	res := &MockResult{}
+
+	for i := 1; i <= 100; i++ {
+		col.record("", res, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := col.snapshot()
+
+	assertEqual(t, stats.AverageLatency, 50500*time.Microsecond)
+	assertEqual(t, stats.LatencyP50, 50*time.Millisecond)
+	assertEqual(t, stats.LatencyP95, 95*time.Millisecond)
+	assertEqual(t, stats.LatencyP99, 99*time.Millisecond)
+
+	for i := 0; i < statsLatencyWindow; i++ {
+		col.record("", res, time.Second)
+	}
+
+	stats = col.snapshot()
+
+	assertEqual(t, stats.LatencyP50, time.Second)
+}
@@ -0,0 +1,206 @@
+package elmobd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// SupportedPIDs retrieves all PIDs of service 01 that are supported in
+// ascending order, leaving out the PIDs that check the support of the next
+// part.
+func (sc *SupportedCommands) SupportedPIDs() []OBDParameterID {
+	var pids []OBDParameterID
+
+	for _, part := range sc.parts {
+		pids = append(pids, partPIDs(part)...)
+	}
+
+	return pids
+}
+
+// Report renders the lookup table as a human-readable report, with the raw
+// bitmap of each part followed by the supported PIDs and the key of the
+// command reading each PID, such as:
+//
+//	Part 1 (PIDs 0x01-0x20): 0C 10 00 00
+//	  0x05 coolant_temperature
+//	  0x06 short_term_fuel_trim_bank1
+//	  0x0C engine_rpm
+//
+// PIDs without a defined command are shown as unknown.
+func (sc *SupportedCommands) Report() string {
+	var out strings.Builder
+
+	for _, part := range sc.parts {
+		first := int(part.ParameterID()) + 1
+
+		fmt.Fprintf(
+			&out,
+			"Part %d (PIDs 0x%02X-0x%02X): %s\n",
+			part.Index(),
+			first,
+			first+PartRange-1,
+			formatPartBitmap(part),
+		)
+
+		for _, pid := range partPIDs(part) {
+			fmt.Fprintf(&out, "  0x%02X %s\n", pid, pidName(SERVICE_01_ID, pid))
+		}
+	}
+
+	if sc.vehicleInfo != nil {
+		fmt.Fprintf(&out, "Vehicle info (service 09): %s\n", formatPartBitmap(sc.vehicleInfo))
+
+		for _, pid := range partPIDs(sc.vehicleInfo) {
+			fmt.Fprintf(&out, "  0x%02X %s\n", pid, pidName(SERVICE_09_ID, pid))
+		}
+	}
+
+	return out.String()
+}
+
+// SupportedCommandsDiff represents the difference between what two lookup
+// tables support, such as for two cars or two ECUs of the same car, see
+// SupportedCommands.Diff.
+type SupportedCommandsDiff struct {
+	// OnlyFirst are the PIDs only supported by the first table
+	OnlyFirst []OBDParameterID
+	// OnlySecond are the PIDs only supported by the second table
+	OnlySecond []OBDParameterID
+	// Both are the PIDs supported by both tables
+	Both []OBDParameterID
+}
+
+// Diff compares which PIDs of service 01 the lookup table supports with the
+// other lookup table.
+func (sc *SupportedCommands) Diff(other *SupportedCommands) SupportedCommandsDiff {
+	diff := SupportedCommandsDiff{}
+	second := map[OBDParameterID]bool{}
+
+	for _, pid := range other.SupportedPIDs() {
+		second[pid] = true
+	}
+
+	for _, pid := range sc.SupportedPIDs() {
+		if second[pid] {
+			diff.Both = append(diff.Both, pid)
+			delete(second, pid)
+		} else {
+			diff.OnlyFirst = append(diff.OnlyFirst, pid)
+		}
+	}
+
+	for pid := range second {
+		diff.OnlySecond = append(diff.OnlySecond, pid)
+	}
+
+	sort.Slice(diff.OnlySecond, func(i, j int) bool {
+		return diff.OnlySecond[i] < diff.OnlySecond[j]
+	})
+
+	return diff
+}
+
+// Equal checks if both lookup tables support the same PIDs.
+func (diff SupportedCommandsDiff) Equal() bool {
+	return len(diff.OnlyFirst) == 0 && len(diff.OnlySecond) == 0
+}
+
+// String renders the difference in the style of a unified diff, the PIDs
+// only supported by the first table are prefixed with "-" and the PIDs only
+// supported by the second table with "+". The PIDs supported by both are left
+// out.
+func (diff SupportedCommandsDiff) String() string {
+	var out strings.Builder
+
+	for _, pid := range diff.OnlyFirst {
+		fmt.Fprintf(&out, "- 0x%02X %s\n", pid, pidName(SERVICE_01_ID, pid))
+	}
+
+	for _, pid := range diff.OnlySecond {
+		fmt.Fprintf(&out, "+ 0x%02X %s\n", pid, pidName(SERVICE_01_ID, pid))
+	}
+
+	return out.String()
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// pidKey identifies a PID within its service.
+type pidKey struct {
+	mode byte
+	pid  OBDParameterID
+}
+
+var (
+	pidNamesOnce sync.Once
+	pidNames     map[pidKey]string
+)
+
+// pidName retrieves the key of the command reading the given PID, when there
+// are several commands reading the PID their keys are joined with "/".
+func pidName(mode byte, pid OBDParameterID) string {
+	pidNamesOnce.Do(func() {
+		pidNames = map[pidKey]string{}
+
+		for _, key := range GetCommandKeys() {
+			cmd, _ := NewCommandByKey(key)
+			id := pidKey{cmd.ModeID(), cmd.ParameterID()}
+
+			if name, ok := pidNames[id]; ok {
+				pidNames[id] = name + "/" + key
+			} else {
+				pidNames[id] = key
+			}
+		}
+
+		pidNames[pidKey{SERVICE_09_ID, VehicleInfoVIN}] = "vin"
+		pidNames[pidKey{SERVICE_09_ID, VehicleInfoCalibrationID}] = "calibration_id"
+		pidNames[pidKey{SERVICE_09_ID, VehicleInfoCVN}] = "cvn"
+		pidNames[pidKey{SERVICE_09_ID, VehicleInfoPerformanceTracking}] = "performance_tracking"
+		pidNames[pidKey{SERVICE_09_ID, VehicleInfoECUName}] = "ecu_name"
+		pidNames[pidKey{SERVICE_09_ID, VehicleInfoPerformanceTrackingCompression}] = "performance_tracking_compression"
+	})
+
+	if name, ok := pidNames[pidKey{mode, pid}]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// partPIDs retrieves the supported PIDs of the given part, leaving out the
+// PID that checks the support of the next part.
+func partPIDs(part *PartSupported) []OBDParameterID {
+	var pids []OBDParameterID
+
+	first := int(part.ParameterID()) + 1
+
+	for pid := first; pid < first+PartRange-1; pid++ {
+		if part.SupportsPID(OBDParameterID(pid)) {
+			pids = append(pids, OBDParameterID(pid))
+		}
+	}
+
+	return pids
+}
+
+// formatPartBitmap formats the value of the part as 4 hex bytes, as received
+// from the car.
+func formatPartBitmap(part *PartSupported) string {
+	return fmt.Sprintf(
+		"%02X %02X %02X %02X",
+		byte(part.Value>>24),
+		byte(part.Value>>16),
+		byte(part.Value>>8),
+		byte(part.Value),
+	)
+}
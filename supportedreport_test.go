@@ -0,0 +1,56 @@
+package elmobd
+
+import (
+	"fmt"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestSupportedCommandsReport(t *testing.T) {
+	sc, err := NewSupportedCommands([]uint32{0x0C100001, 0x00000000})
+
+	assertSuccess(t, err)
+
+	vehicleInfo := NewVehicleInfoSupported()
+	vehicleInfo.SetRawValue(0x40000000)
+	sc.SetVehicleInfoPart(vehicleInfo)
+
+	expected := "Part 1 (PIDs 0x01-0x20): 0C 10 00 01\n" +
+		"  0x05 coolant_temperature\n" +
+		"  0x06 short_term_fuel_trim_bank1\n" +
+		"  0x0C engine_rpm\n" +
+		"Part 2 (PIDs 0x21-0x40): 00 00 00 00\n" +
+		"Vehicle info (service 09): 40 00 00 00\n" +
+		"  0x02 vin\n"
+
+	assertEqual(t, sc.Report(), expected)
+}
+
+func TestSupportedCommandsDiff(t *testing.T) {
+	first, err := NewSupportedCommands([]uint32{0x0C100000})
+
+	assertSuccess(t, err)
+
+	second, err := NewSupportedCommands([]uint32{0x04180001, 0x00008000})
+
+	assertSuccess(t, err)
+
+	diff := first.Diff(second)
+
+	assertEqual(t, diff.Equal(), false)
+	assertEqual(t, fmt.Sprint(diff.OnlyFirst), "[5]")
+	assertEqual(t, fmt.Sprint(diff.OnlySecond), "[13 49]")
+	assertEqual(t, fmt.Sprint(diff.Both), "[6 12]")
+	assertEqual(
+		t,
+		diff.String(),
+		"- 0x05 coolant_temperature\n"+
+			"+ 0x0D vehicle_speed\n"+
+			"+ 0x31 dist_since_dtc_clean\n",
+	)
+
+	assertEqual(t, first.Diff(first).Equal(), true)
+}
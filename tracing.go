@@ -0,0 +1,83 @@
+package elmobd
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Attribute keys set on the spans created by the Device.
+const (
+	AttrCommandKey    = "obd.command.key"
+	AttrCommandMode   = "obd.command.mode"
+	AttrCommandPID    = "obd.command.pid"
+	AttrResultStatus  = "obd.result.status"
+	AttrWriteDuration = "obd.write.duration"
+	AttrReadDuration  = "obd.read.duration"
+)
+
+// Span represents a single traced operation, which is ended by calling End.
+//
+// The values given to SetAttribute are either strings or time.Durations.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	SetError(err error)
+	End()
+}
+
+// Tracer represents something that can create spans, such as a thin adapter
+// around an OpenTelemetry trace.Tracer. It is injected into the Device using
+// SetTracer.
+//
+// The library does not depend on any tracing library itself, which means that
+// programs that don't use tracing don't pull in any extra dependencies.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) Span
+}
+
+// TimedResult is implemented by RawResults that know how long the write and
+// the read of the command took, such as RealResult and MockResult.
+type TimedResult interface {
+	GetWriteTime() time.Duration
+	GetReadTime() time.Duration
+}
+
+// SetTracer sets the tracer used to emit one span per OBD command run on the
+// device. Setting it to nil turns off tracing, which is the default.
+//
+// The tracer should be set before the device is used by multiple goroutines.
+func (dev *Device) SetTracer(tracer Tracer) {
+	dev.tracer = tracer
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+const runOBDCommandSpanName = "elmobd.RunOBDCommand"
+
+// endCommandSpan decorates the span with the details of the run command and
+// ends it.
+func endCommandSpan(span Span, cmd OBDCommand, rawRes RawResult, err error) {
+	span.SetAttribute(AttrCommandKey, cmd.Key())
+	span.SetAttribute(AttrCommandMode, fmt.Sprintf("%02X", cmd.ModeID()))
+	span.SetAttribute(AttrCommandPID, fmt.Sprintf("%02X", cmd.ParameterID()))
+
+	if timed, ok := rawRes.(TimedResult); ok {
+		span.SetAttribute(AttrWriteDuration, timed.GetWriteTime())
+		span.SetAttribute(AttrReadDuration, timed.GetReadTime())
+	}
+
+	if err != nil {
+		span.SetAttribute(AttrResultStatus, "error")
+		span.SetError(err)
+	} else {
+		span.SetAttribute(AttrResultStatus, "ok")
+	}
+
+	span.End()
+}
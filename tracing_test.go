@@ -0,0 +1,86 @@
+package elmobd
+
+import (
+	"context"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (span *recordedSpan) SetAttribute(key string, value interface{}) {
+	span.attributes[key] = value
+}
+
+func (span *recordedSpan) SetError(err error) {
+	span.err = err
+}
+
+func (span *recordedSpan) End() {
+	span.ended = true
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (tracer *recordingTracer) StartSpan(ctx context.Context, name string) Span {
+	span := &recordedSpan{name: name, attributes: map[string]interface{}{}}
+
+	tracer.spans = append(tracer.spans, span)
+
+	return span
+}
+
+func TestTracerSpanPerCommand(t *testing.T) {
+	tracer := &recordingTracer{}
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	dev.SetTracer(tracer)
+
+	_, err := dev.RunOBDCommand(NewEngineRPM())
+	assertSuccess(t, err)
+
+	_, err = dev.RunOBDCommand(NewFuelPressure())
+	assert(t, err != nil, "unsupported mock command fails")
+
+	assertEqual(t, len(tracer.spans), 2)
+
+	span := tracer.spans[0]
+
+	assertEqual(t, span.name, runOBDCommandSpanName)
+	assertEqual(t, span.ended, true)
+	assertEqual(t, span.attributes[AttrCommandKey], "engine_rpm")
+	assertEqual(t, span.attributes[AttrCommandPID], "0C")
+	assertEqual(t, span.attributes[AttrResultStatus], "ok")
+	assert(t, span.err == nil, "successful span has no error")
+
+	span = tracer.spans[1]
+
+	assertEqual(t, span.attributes[AttrResultStatus], "error")
+	assert(t, span.err != nil, "failed span has error")
+}
+
+func TestRunOBDCommandContextCanceled(t *testing.T) {
+	tracer := &recordingTracer{}
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	dev.SetTracer(tracer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := dev.RunOBDCommandContext(ctx, NewEngineRPM())
+
+	assertEqual(t, err, context.Canceled)
+	assertEqual(t, len(tracer.spans), 0)
+	assertEqual(t, dev.Stats().CommandsRun, uint64(0))
+}
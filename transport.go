@@ -0,0 +1,92 @@
+package elmobd
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// DefaultReadTimeout is how long a read waits for the device, unless the
+// address of the device gives another timeout.
+const DefaultReadTimeout = 5 * time.Second
+
+// OpenFunc opens the raw device at the given address, see RegisterTransport.
+type OpenFunc func(addr *url.URL) (RawDevice, error)
+
+// RegisterTransport makes NewDevice open the addresses with the given scheme
+// using the given function, replacing the transport already registered for
+// the scheme, if any.
+//
+// Only the mock device (test://) is built in. The other transports live in
+// the subpackages of transport, and register themselves when imported, so
+// that programs only pull in the dependencies of the transports they use:
+//
+//	import (
+//		"github.com/rzetterberg/elmobd"
+//		_ "github.com/rzetterberg/elmobd/transport/net"
+//		_ "github.com/rzetterberg/elmobd/transport/serial"
+//	)
+func RegisterTransport(scheme string, open OpenFunc) {
+	transports.mutex.Lock()
+	defer transports.mutex.Unlock()
+
+	transports.schemes[scheme] = open
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+var transports = struct {
+	mutex   sync.Mutex
+	schemes map[string]OpenFunc
+}{
+	schemes: map[string]OpenFunc{
+		"test": func(addr *url.URL) (RawDevice, error) {
+			dev, err := NewMockDevice(addr.Host, addr.Query())
+
+			if err != nil {
+				return nil, err
+			}
+
+			return dev, nil
+		},
+	},
+}
+
+// transportPackages tells which package registers the transports of the
+// schemes known by the library, for telling what to import.
+var transportPackages = map[string]string{
+	"serial": "transport/serial",
+	"tcp":    "transport/net",
+	"tcp4":   "transport/net",
+	"tcp6":   "transport/net",
+	"unix":   "transport/net",
+}
+
+// openTransport opens the raw device at the given address with the transport
+// registered for its scheme.
+func openTransport(addr *url.URL) (RawDevice, error) {
+	transports.mutex.Lock()
+	open, ok := transports.schemes[addr.Scheme]
+	transports.mutex.Unlock()
+
+	if ok {
+		return open(addr)
+	}
+
+	if pkg, known := transportPackages[addr.Scheme]; known {
+		return nil, fmt.Errorf(
+			"no transport registered for device scheme %q, import github.com/rzetterberg/elmobd/%s",
+			addr.Scheme,
+			pkg,
+		)
+	}
+
+	return nil, fmt.Errorf("unknown device scheme: %q", addr.Scheme)
+}
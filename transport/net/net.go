@@ -0,0 +1,82 @@
+// Package net is the transport for ELM327 devices reached over the network,
+// such as WiFi adapters, or over a unix socket.
+//
+// Importing it makes elmobd.NewDevice open tcp://, tcp4://, tcp6:// and
+// unix: addresses:
+//
+//	import _ "github.com/rzetterberg/elmobd/transport/net"
+//
+//	dev, err := elmobd.NewDevice("tcp://192.168.0.10:35000", false)
+package net
+
+import (
+	stdnet "net"
+	"net/url"
+	"time"
+
+	"github.com/rzetterberg/elmobd"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Open creates a new low-level ELM327 device manager by connecting to the
+// device at the given network address, such as a WiFi adapter at
+// tcp://192.168.0.10:35000, or a unix socket at unix:/tmp/elm327.sock.
+//
+// A read waits 5 s for the device by default, another timeout can be given
+// in the address, such as tcp://192.168.0.10:35000?timeout=2s.
+func Open(addr *url.URL) (*elmobd.RealDevice, error) {
+	network := addr.Scheme
+	address := addr.Host
+
+	if network == "unix" {
+		address = addr.Opaque
+	}
+
+	open := func() (elmobd.Conn, error) {
+		c, err := stdnet.Dial(network, address)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &conn{c}, nil
+	}
+
+	timeout := elmobd.DefaultReadTimeout
+
+	if to, err := time.ParseDuration(addr.Query().Get("timeout")); err == nil {
+		timeout = to
+	}
+
+	return elmobd.NewRealDevice(open, timeout)
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+func init() {
+	for _, scheme := range []string{"tcp", "tcp4", "tcp6", "unix"} {
+		elmobd.RegisterTransport(scheme, func(addr *url.URL) (elmobd.RawDevice, error) {
+			dev, err := Open(addr)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return dev, nil
+		})
+	}
+}
+
+// conn is a network connection, which has nothing to flush.
+type conn struct {
+	stdnet.Conn
+}
+
+func (c *conn) Flush() error {
+	return nil
+}
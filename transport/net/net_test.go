@@ -0,0 +1,98 @@
+package net
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	stdnet "net"
+	"net/url"
+	"testing"
+
+	"github.com/rzetterberg/elmobd"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// serveELM327 answers the commands sent to the given listener like an
+// ELM327 device with echo on would.
+func serveELM327(listener stdnet.Listener, answers map[string]string) {
+	conn, err := listener.Accept()
+
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		line, err := reader.ReadString('\n')
+
+		if err != nil {
+			return
+		}
+
+		command := line[:len(line)-2]
+
+		fmt.Fprintf(conn, "%s\r%s\r\r>", command, answers[command])
+	}
+}
+
+func TestNewDevice(t *testing.T) {
+	listener, err := stdnet.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	go serveELM327(listener, map[string]string{
+		"ATZ":   "ELM327 v1.4b",
+		"ATI":   "ELM327 v1.4b",
+		"AT@1":  "OBDII to RS232 Interpreter",
+		"ATSP0": "OK",
+		"010D1": "41 0D 4B",
+	})
+
+	dev, err := elmobd.NewDevice(fmt.Sprintf("tcp://%s?timeout=1s", listener.Addr()), false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer dev.Close()
+
+	speed, err := elmobd.Run(dev, elmobd.NewVehicleSpeed())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if speed.Value != 75 {
+		t.Errorf("Expected speed 75, got %d", speed.Value)
+	}
+}
+
+func TestOpenRefused(t *testing.T) {
+	listener, err := stdnet.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, _ := url.Parse(fmt.Sprintf("tcp://%s", listener.Addr()))
+
+	listener.Close()
+
+	_, err = Open(addr)
+
+	var transportErr *elmobd.TransportError
+
+	if !errors.As(err, &transportErr) || transportErr.Op != "open" {
+		t.Errorf("Expected a transport error opening the device, got %v", err)
+	}
+}
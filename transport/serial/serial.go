@@ -0,0 +1,75 @@
+// Package serial is the transport for ELM327 devices connected to a serial
+// port, such as USB and Bluetooth adapters.
+//
+// Importing it makes elmobd.NewDevice open serial:// addresses, and the
+// paths of existing files:
+//
+//	import _ "github.com/rzetterberg/elmobd/transport/serial"
+//
+//	dev, err := elmobd.NewDevice("serial:///dev/ttyUSB0?baudrate=38400", false)
+package serial
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rzetterberg/elmobd"
+	tarm "github.com/tarm/serial"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Open creates a new low-level ELM327 device manager by connecting to the
+// device at the path of the given address.
+//
+// The baud rate defaults to 38400 and a read waits 5 s for the device, which
+// can be changed with the baudrate and timeout parameters of the address,
+// such as serial:///dev/ttyUSB0?baudrate=115200&timeout=2s.
+func Open(addr *url.URL) (*elmobd.RealDevice, error) {
+	config := &tarm.Config{
+		Name:        addr.Path,
+		Baud:        38400,
+		ReadTimeout: elmobd.DefaultReadTimeout,
+		Size:        8,
+		Parity:      tarm.ParityNone,
+		StopBits:    tarm.Stop1,
+	}
+
+	q := addr.Query()
+
+	if baud, err := strconv.Atoi(q.Get("baudrate")); err == nil {
+		config.Baud = baud
+	}
+
+	if to, err := time.ParseDuration(q.Get("timeout")); err == nil {
+		config.ReadTimeout = to
+	}
+
+	openAt := func(baud int) (elmobd.Conn, error) {
+		atBaud := *config
+		atBaud.Baud = baud
+
+		return tarm.OpenPort(&atBaud)
+	}
+
+	return elmobd.NewBaudRealDevice(openAt, config.Baud)
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+func init() {
+	elmobd.RegisterTransport("serial", func(addr *url.URL) (elmobd.RawDevice, error) {
+		dev, err := Open(addr)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return dev, nil
+	})
+}
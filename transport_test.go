@@ -0,0 +1,66 @@
+package elmobd
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestRegisterTransport(t *testing.T) {
+	_, err := NewDevice("serial:///dev/does-not-exist", false)
+
+	assert(
+		t,
+		err != nil && strings.Contains(err.Error(), "transport/serial"),
+		"Expected unregistered transport to tell what to import",
+	)
+
+	_, err = NewDevice("carrier-pigeon://", false)
+
+	assert(t, err != nil, "Expected unknown scheme to fail")
+
+	defer func() {
+		transports.mutex.Lock()
+		delete(transports.schemes, "scripted")
+		transports.mutex.Unlock()
+	}()
+
+	var opened *url.URL
+
+	RegisterTransport("scripted", func(addr *url.URL) (RawDevice, error) {
+		opened = addr
+
+		return &MockDevice{}, nil
+	})
+
+	dev, err := NewDevice("scripted://adapter", false)
+
+	assertSuccess(t, err)
+	assertEqual(t, opened.Host, "adapter")
+	assertEqual(t, dev.HasQuirk(QuirkSlowReset), true)
+}
+
+func TestNewDeviceUnidentifiedAdapter(t *testing.T) {
+	defer func() {
+		transports.mutex.Lock()
+		delete(transports.schemes, "anonymous")
+		transports.mutex.Unlock()
+	}()
+
+	RegisterTransport("anonymous", func(addr *url.URL) (RawDevice, error) {
+		raw := &MockDevice{}
+
+		raw.SetATResponse("ATI", "OBDLink MX")
+
+		return raw, nil
+	})
+
+	dev, err := NewDevice("anonymous://adapter", false)
+
+	assertSuccess(t, err)
+	assertEqual(t, len(dev.Quirks()), 0)
+}
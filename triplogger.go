@@ -0,0 +1,338 @@
+package elmobd
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// Trip represents a period between the engine starting and stopping, as
+// recorded by the TripLogger. EndedAt is the zero time for the trip in
+// progress.
+type Trip struct {
+	ID        int64
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+// TripLogger persists readings and trip boundaries into a SQLite database,
+// for offline analysis of the logged data.
+//
+// The library does not depend on a SQLite driver, instead you open the
+// database with the driver of your choice (such as github.com/mattn/go-sqlite3)
+// and give the *sql.DB to NewTripLogger.
+//
+// Trips are detected from the engine RPM readings logged: a trip starts at the
+// first reading of a running engine and ends at the first reading of a
+// stopped engine. Trips can also be started and ended explicitly. Readings
+// logged outside of a trip are stored without a trip.
+//
+// A TripLogger is safe to use from multiple goroutines.
+type TripLogger struct {
+	mutex  sync.Mutex
+	db     *sql.DB
+	tripID int64
+	now    func() time.Time
+}
+
+// NewTripLogger creates a new TripLogger using the given database, creating
+// the tables "trips" and "readings" if they don't exist.
+//
+// A trip that was in progress when the previous logger stopped is ended at
+// the time of its last reading.
+func NewTripLogger(db *sql.DB) (*TripLogger, error) {
+	for _, stmt := range tripLoggerSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("failed to create trip log tables: %w", err)
+		}
+	}
+
+	_, err := db.Exec(`
+		UPDATE trips SET ended_at = COALESCE(
+			(SELECT MAX(recorded_at) FROM readings WHERE trip_id = trips.id),
+			started_at
+		)
+		WHERE ended_at IS NULL`,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to end previous trips: %w", err)
+	}
+
+	return &TripLogger{db: db, now: time.Now}, nil
+}
+
+// Log stores the value of the given processed command, timestamped with the
+// current time. Logging EngineRPM starts or ends the current trip.
+func (logger *TripLogger) Log(cmd OBDCommand) error {
+	return logger.LogMany([]OBDCommand{cmd})
+}
+
+// LogMany stores the values of all the given processed commands, such as the
+// result of RunManyOBDCommands, using the same timestamp for all readings.
+func (logger *TripLogger) LogMany(commands []OBDCommand) error {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	now := logger.now()
+
+	for _, cmd := range commands {
+		if rpm, ok := cmd.(*EngineRPM); ok {
+			var err error
+
+			switch tripTransition(logger.tripID != 0, rpm.Value) {
+			case tripStart:
+				err = logger.startTrip(now)
+			case tripEnd:
+				err = logger.endTrip(now)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err := logger.insertReading(NewReading(cmd, now))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartTrip ends the current trip (if any) and starts a new trip.
+func (logger *TripLogger) StartTrip() error {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	now := logger.now()
+
+	if logger.tripID != 0 {
+		if err := logger.endTrip(now); err != nil {
+			return err
+		}
+	}
+
+	return logger.startTrip(now)
+}
+
+// EndTrip ends the current trip, it does nothing when there's no trip in
+// progress.
+func (logger *TripLogger) EndTrip() error {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.tripID == 0 {
+		return nil
+	}
+
+	return logger.endTrip(logger.now())
+}
+
+// Trips retrieves all recorded trips, oldest first.
+func (logger *TripLogger) Trips() ([]Trip, error) {
+	rows, err := logger.db.Query(
+		"SELECT id, started_at, ended_at FROM trips ORDER BY started_at",
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trips: %w", err)
+	}
+
+	defer rows.Close()
+
+	var trips []Trip
+
+	for rows.Next() {
+		var trip Trip
+		var started int64
+		var ended sql.NullInt64
+
+		if err := rows.Scan(&trip.ID, &started, &ended); err != nil {
+			return nil, fmt.Errorf("failed to read trip: %w", err)
+		}
+
+		trip.StartedAt = time.Unix(0, started)
+
+		if ended.Valid {
+			trip.EndedAt = time.Unix(0, ended.Int64)
+		}
+
+		trips = append(trips, trip)
+	}
+
+	return trips, rows.Err()
+}
+
+// TripReadings retrieves the readings of the given trip, oldest first. When
+// key is not empty only the readings of the command with that key are
+// retrieved.
+//
+// The values of the readings are float64 for numeric values and string for
+// all other values.
+func (logger *TripLogger) TripReadings(tripID int64, key string) ([]Reading, error) {
+	query := "SELECT key, value, value_lit, unit, recorded_at FROM readings WHERE trip_id = ?"
+	args := []interface{}{tripID}
+
+	if key != "" {
+		query += " AND key = ?"
+		args = append(args, key)
+	}
+
+	rows, err := logger.db.Query(query+" ORDER BY recorded_at, id", args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query readings: %w", err)
+	}
+
+	defer rows.Close()
+
+	var readings []Reading
+
+	for rows.Next() {
+		var reading Reading
+		var value sql.NullFloat64
+		var lit string
+		var recorded int64
+
+		err := rows.Scan(&reading.Key, &value, &lit, &reading.Unit, &recorded)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reading: %w", err)
+		}
+
+		if value.Valid {
+			reading.Value = value.Float64
+		} else {
+			reading.Value = lit
+		}
+
+		reading.Time = time.Unix(0, recorded)
+
+		readings = append(readings, reading)
+	}
+
+	return readings, rows.Err()
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// tripLoggerSchema creates the tables used by the TripLogger, times are
+// stored as nanoseconds since the Unix epoch.
+var tripLoggerSchema = []string{
+	`CREATE TABLE IF NOT EXISTS trips (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_at INTEGER NOT NULL,
+		ended_at INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS readings (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		trip_id INTEGER REFERENCES trips(id),
+		key TEXT NOT NULL,
+		value REAL,
+		value_lit TEXT NOT NULL,
+		unit TEXT NOT NULL,
+		recorded_at INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS readings_trip_key ON readings (trip_id, key)`,
+}
+
+type tripChange int
+
+const (
+	tripUnchanged tripChange = iota
+	tripStart
+	tripEnd
+)
+
+// tripTransition decides how the trip changes when an engine RPM reading is
+// logged.
+func tripTransition(inTrip bool, rpm float32) tripChange {
+	running := rpm > 0
+
+	if running && !inTrip {
+		return tripStart
+	}
+
+	if !running && inTrip {
+		return tripEnd
+	}
+
+	return tripUnchanged
+}
+
+func (logger *TripLogger) startTrip(at time.Time) error {
+	res, err := logger.db.Exec(
+		"INSERT INTO trips (started_at) VALUES (?)",
+		at.UnixNano(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to start trip: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return fmt.Errorf("failed to start trip: %w", err)
+	}
+
+	logger.tripID = id
+
+	return nil
+}
+
+func (logger *TripLogger) endTrip(at time.Time) error {
+	_, err := logger.db.Exec(
+		"UPDATE trips SET ended_at = ? WHERE id = ?",
+		at.UnixNano(),
+		logger.tripID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to end trip: %w", err)
+	}
+
+	logger.tripID = 0
+
+	return nil
+}
+
+func (logger *TripLogger) insertReading(reading Reading) error {
+	var tripID sql.NullInt64
+	var value sql.NullFloat64
+
+	if logger.tripID != 0 {
+		tripID = sql.NullInt64{Int64: logger.tripID, Valid: true}
+	}
+
+	if val, ok := reading.Float64(); ok {
+		value = sql.NullFloat64{Float64: val, Valid: true}
+	}
+
+	_, err := logger.db.Exec(
+		`INSERT INTO readings (trip_id, key, value, value_lit, unit, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		tripID,
+		reading.Key,
+		value,
+		fmt.Sprint(reading.Value),
+		reading.Unit,
+		reading.Time.UnixNano(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to store reading of %s: %w", reading.Key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,322 @@
+package elmobd
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Test database
+ */
+
+// tripDB is a minimal database/sql driver, that runs the statements of the
+// TripLogger against rows kept in memory. Reopening the same tripDB keeps the
+// rows, like reopening a SQLite file.
+type tripDB struct {
+	trips    [][]driver.Value
+	readings [][]driver.Value
+}
+
+func (db *tripDB) open() *sql.DB {
+	return sql.OpenDB(db)
+}
+
+func (db *tripDB) Connect(ctx context.Context) (driver.Conn, error) {
+	return &tripConn{db}, nil
+}
+
+func (db *tripDB) Open(name string) (driver.Conn, error) {
+	return &tripConn{db}, nil
+}
+
+func (db *tripDB) Driver() driver.Driver {
+	return db
+}
+
+type tripConn struct {
+	db *tripDB
+}
+
+func (conn *tripConn) Prepare(query string) (driver.Stmt, error) {
+	return &tripStmt{conn.db, strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (conn *tripConn) Close() error {
+	return nil
+}
+
+func (conn *tripConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions not supported")
+}
+
+type tripStmt struct {
+	db    *tripDB
+	query string
+}
+
+func (stmt *tripStmt) Close() error {
+	return nil
+}
+
+func (stmt *tripStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *tripStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := stmt.db
+
+	switch {
+	case strings.HasPrefix(stmt.query, "CREATE"):
+		return driver.RowsAffected(0), nil
+	case stmt.query == "INSERT INTO trips (started_at) VALUES (?)":
+		id := int64(len(db.trips) + 1)
+
+		db.trips = append(db.trips, []driver.Value{id, args[0], nil})
+
+		return tripResult(id), nil
+	case stmt.query == "UPDATE trips SET ended_at = ? WHERE id = ?":
+		db.trips[args[1].(int64)-1][2] = args[0]
+
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(stmt.query, "UPDATE trips SET ended_at = COALESCE("):
+		for _, trip := range db.trips {
+			if trip[2] != nil {
+				continue
+			}
+
+			trip[2] = trip[1]
+
+			for _, reading := range db.readings {
+				if reading[1] == trip[0] && reading[6].(int64) > trip[2].(int64) {
+					trip[2] = reading[6]
+				}
+			}
+		}
+
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(stmt.query, "INSERT INTO readings"):
+		id := int64(len(db.readings) + 1)
+
+		db.readings = append(db.readings, append([]driver.Value{id}, args...))
+
+		return tripResult(id), nil
+	}
+
+	return nil, fmt.Errorf("unexpected statement: %s", stmt.query)
+}
+
+func (stmt *tripStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := stmt.db
+
+	switch {
+	case stmt.query == "SELECT id, started_at, ended_at FROM trips ORDER BY started_at":
+		rows := append([][]driver.Value{}, db.trips...)
+
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i][1].(int64) < rows[j][1].(int64)
+		})
+
+		return &tripRows{rows: rows}, nil
+	case strings.HasPrefix(stmt.query, "SELECT key, value, value_lit, unit, recorded_at FROM readings WHERE trip_id = ?"):
+		rows := [][]driver.Value{}
+
+		for _, reading := range db.readings {
+			if reading[1] != args[0] || (len(args) > 1 && reading[2] != args[1]) {
+				continue
+			}
+
+			rows = append(rows, reading[2:])
+		}
+
+		// The readings are inserted in order of their id
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i][4].(int64) < rows[j][4].(int64)
+		})
+
+		return &tripRows{rows: rows}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected query: %s", stmt.query)
+}
+
+type tripResult int64
+
+func (res tripResult) LastInsertId() (int64, error) {
+	return int64(res), nil
+}
+
+func (res tripResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+type tripRows struct {
+	rows [][]driver.Value
+}
+
+func (rows *tripRows) Columns() []string {
+	if len(rows.rows) == 0 {
+		return nil
+	}
+
+	return make([]string, len(rows.rows[0]))
+}
+
+func (rows *tripRows) Close() error {
+	return nil
+}
+
+func (rows *tripRows) Next(dest []driver.Value) error {
+	if len(rows.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, rows.rows[0])
+	rows.rows = rows.rows[1:]
+
+	return nil
+}
+
+// newTestTripLogger creates a TripLogger on the given database, with a clock
+// advancing a second on each reading.
+func newTestTripLogger(t *testing.T, db *tripDB, start time.Time) *TripLogger {
+	logger, err := NewTripLogger(db.open())
+
+	assertSuccess(t, err)
+
+	at := start.Add(-time.Second)
+
+	logger.now = func() time.Time {
+		at = at.Add(time.Second)
+
+		return at
+	}
+
+	return logger
+}
+
+func newTestRPM(rpm float64) *EngineRPM {
+	cmd := NewEngineRPM()
+
+	cmd.SetFloat64(rpm)
+
+	return cmd
+}
+
+/*==============================================================================
+ * Tests
+ */
+
+func TestTripTransition(t *testing.T) {
+	type scenario struct {
+		inTrip bool
+		rpm    float32
+		change tripChange
+	}
+
+	scenarios := []scenario{
+		{false, 0, tripUnchanged},
+		{false, 850, tripStart},
+		{true, 2400, tripUnchanged},
+		{true, 0, tripEnd},
+	}
+
+	for _, scen := range scenarios {
+		assertEqual(t, tripTransition(scen.inTrip, scen.rpm), scen.change)
+	}
+}
+
+func TestTripLoggerRoundTrip(t *testing.T) {
+	start := time.Unix(1662638400, 0)
+	logger := newTestTripLogger(t, &tripDB{}, start)
+
+	speed := NewVehicleSpeed()
+	speed.Value = 75
+
+	fuelType := NewFuelType()
+	fuelType.Value = FuelDiesel
+
+	assertSuccess(t, logger.Log(newTestRPM(0)))
+	assertSuccess(t, logger.Log(newTestRPM(850)))
+	assertSuccess(t, logger.LogMany([]OBDCommand{speed, fuelType, newTestRPM(2400)}))
+	assertSuccess(t, logger.Log(newTestRPM(0)))
+
+	trips, err := logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(trips), 1)
+	assertEqual(t, trips[0].StartedAt.Equal(start.Add(time.Second)), true)
+	assertEqual(t, trips[0].EndedAt.Equal(start.Add(3*time.Second)), true)
+
+	readings, err := logger.TripReadings(trips[0].ID, "")
+
+	assertSuccess(t, err)
+	assertEqual(t, len(readings), 4)
+
+	expected := []Reading{
+		{Key: "engine_rpm", Value: 850.0, Unit: "rpm", Time: start.Add(time.Second)},
+		{Key: "vehicle_speed", Value: 75.0, Unit: "km/h", Time: start.Add(2 * time.Second)},
+		{Key: "fuel_type", Value: "diesel", Unit: "", Time: start.Add(2 * time.Second)},
+		{Key: "engine_rpm", Value: 2400.0, Unit: "rpm", Time: start.Add(2 * time.Second)},
+	}
+
+	for i, reading := range readings {
+		assertEqual(t, reading.Key, expected[i].Key)
+		assertEqual(t, reading.Value, expected[i].Value)
+		assertEqual(t, reading.Unit, expected[i].Unit)
+		assertEqual(t, reading.Time.Equal(expected[i].Time), true)
+	}
+
+	readings, err = logger.TripReadings(trips[0].ID, "engine_rpm")
+
+	assertSuccess(t, err)
+	assertEqual(t, len(readings), 2)
+	assertEqual(t, readings[1].Value, 2400.0)
+}
+
+func TestTripLoggerExplicitTrips(t *testing.T) {
+	start := time.Unix(1662638400, 0)
+	logger := newTestTripLogger(t, &tripDB{}, start)
+
+	assertSuccess(t, logger.StartTrip())
+	assertSuccess(t, logger.StartTrip())
+	assertSuccess(t, logger.EndTrip())
+	assertSuccess(t, logger.EndTrip())
+
+	trips, err := logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(trips), 2)
+	assertEqual(t, trips[0].EndedAt.Equal(start.Add(time.Second)), true)
+	assertEqual(t, trips[1].StartedAt.Equal(start.Add(time.Second)), true)
+	assertEqual(t, trips[1].EndedAt.Equal(start.Add(2*time.Second)), true)
+}
+
+func TestTripLoggerEndsPreviousTrip(t *testing.T) {
+	start := time.Unix(1662638400, 0)
+	db := &tripDB{}
+	logger := newTestTripLogger(t, db, start)
+
+	assertSuccess(t, logger.Log(newTestRPM(850)))
+	assertSuccess(t, logger.Log(newTestRPM(900)))
+
+	trips, err := logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, trips[0].EndedAt.IsZero(), true)
+
+	// The logger stopped during the trip
+	logger = newTestTripLogger(t, db, start.Add(time.Hour))
+
+	trips, err = logger.Trips()
+
+	assertSuccess(t, err)
+	assertEqual(t, len(trips), 1)
+	assertEqual(t, trips[0].EndedAt.Equal(start.Add(time.Second)), true)
+}
@@ -0,0 +1,45 @@
+package elmobd
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// ErrDeviceBusy is returned by TryRunOBDCommand when another command is in
+// flight on the device.
+var ErrDeviceBusy = errors.New("device is busy")
+
+// TryRunOBDCommand works like RunOBDCommand, but returns ErrDeviceBusy right
+// away if another command is in flight instead of waiting for it, such as
+// for refreshing a UI without queueing behind a slow diagnostic read.
+//
+// Commands queued with RunOBDCommandAsync are only in flight once they are
+// being run. The rate limit of the device (see SetRateLimit) is still waited
+// for.
+func (dev *Device) TryRunOBDCommand(cmd OBDCommand) (OBDCommand, error) {
+	if !atomic.CompareAndSwapInt32(&dev.inFlight, 0, 1) {
+		return cmd, ErrDeviceBusy
+	}
+
+	defer atomic.AddInt32(&dev.inFlight, -1)
+
+	return dev.RunOBDCommand(cmd)
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// enterFlight marks a command as in flight on the device, and returns the
+// function to call once it has been answered.
+func (dev *Device) enterFlight() func() {
+	atomic.AddInt32(&dev.inFlight, 1)
+
+	return func() {
+		atomic.AddInt32(&dev.inFlight, -1)
+	}
+}
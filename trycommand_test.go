@@ -0,0 +1,61 @@
+package elmobd
+
+import (
+	"errors"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// blockingDevice signals when a command is started and waits to be released
+// before answering it.
+type blockingDevice struct {
+	MockDevice
+	started chan struct{}
+	release chan struct{}
+}
+
+func (dev *blockingDevice) RunCommand(command string) RawResult {
+	dev.started <- struct{}{}
+	<-dev.release
+
+	return dev.MockDevice.RunCommand(command)
+}
+
+func TestTryRunOBDCommand(t *testing.T) {
+	raw := &blockingDevice{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	dev := &Device{rawDevice: raw}
+	done := make(chan error)
+
+	go func() {
+		_, err := dev.RunOBDCommand(NewMonitorStatus())
+
+		done <- err
+	}()
+
+	<-raw.started
+
+	_, err := dev.TryRunOBDCommand(NewVehicleSpeed())
+
+	assert(t, errors.Is(err, ErrDeviceBusy), "Expected device to be busy")
+
+	raw.release <- struct{}{}
+
+	assertSuccess(t, <-done)
+
+	go func() {
+		<-raw.started
+		raw.release <- struct{}{}
+	}()
+
+	cmd, err := dev.TryRunOBDCommand(NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, cmd.(*VehicleSpeed).Value, uint32(75))
+	assertEqual(t, dev.inFlight, int32(0))
+}
@@ -0,0 +1,266 @@
+package elmobd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// IsTimeoutError returns true if the given error, as returned when running a
+// command, means that the device did not answer in time.
+func IsTimeoutError(err error) bool {
+	var netErr net.Error
+
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// The serial port reports a read timeout as the end of the file
+	return errors.Is(err, io.EOF) || errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// RecoverableDevice is implemented by raw devices that can be brought back
+// when they have stopped responding, such as RealDevice.
+type RecoverableDevice interface {
+	RawDevice
+	Interrupt() error
+	WarmStart() error
+	Reset() error
+	Reopen() error
+}
+
+// WatchdogEvent represents an escalation step the watchdog took to recover
+// the device, see Device.EnableWatchdog.
+type WatchdogEvent struct {
+	Timeouts  int
+	Step      string
+	Recovered bool
+	Err       error
+}
+
+// EnableWatchdog makes the device recover automatically when it stops
+// responding, which some ELM327 clones do while keeping the port open.
+//
+// After the given amount of consecutive timeouts the watchdog escalates
+// through the following steps, until the device identifies itself again:
+//
+//   - interrupt: sends a character to stop the current command
+//   - warm start: sends ATWS
+//   - reset: sends ATZ
+//   - reopen: closes the port and opens it again
+//
+// The command that triggered the recovery is run again once the device has
+// recovered, so the caller gets its result as if nothing happened. The given
+// callback, if any, is called with each step taken.
+//
+// The settings applied with FastMode after enabling the watchdog are applied
+// again when the device was restarted by the recovery, which is reported to
+// the callback as the step "restore settings". The baud rate is not, the
+// device goes back to its default baud rate when it is restarted.
+//
+// Devices that can not be recovered (such as the mock device) are left as
+// they are, and an error is returned.
+func (dev *Device) EnableWatchdog(maxTimeouts int, callback func(WatchdogEvent)) error {
+	if _, ok := dev.rawDevice.(*watchdogDevice); ok {
+		return fmt.Errorf("watchdog already enabled")
+	}
+
+	recoverable, ok := dev.rawDevice.(RecoverableDevice)
+
+	if !ok {
+		return fmt.Errorf("device does not support recovery: %T", dev.rawDevice)
+	}
+
+	if maxTimeouts < 1 {
+		maxTimeouts = 1
+	}
+
+	dev.rawDevice = &watchdogDevice{
+		device:      recoverable,
+		maxTimeouts: maxTimeouts,
+		callback:    callback,
+		onRetry:     dev.stats.recordRetry,
+	}
+
+	return nil
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// watchdogStep is a step of the escalation sequence.
+type watchdogStep struct {
+	name string
+	run  func(RecoverableDevice) error
+}
+
+var watchdogSteps = []watchdogStep{
+	{"interrupt", RecoverableDevice.Interrupt},
+	{"warm start", RecoverableDevice.WarmStart},
+	{"reset", RecoverableDevice.Reset},
+	{"reopen", RecoverableDevice.Reopen},
+}
+
+// watchdogDevice wraps a RecoverableDevice, counting consecutive timeouts.
+type watchdogDevice struct {
+	mutex       sync.Mutex
+	device      RecoverableDevice
+	maxTimeouts int
+	timeouts    int
+	callback    func(WatchdogEvent)
+	onRetry     func()
+	settings    []string
+}
+
+// unwrapDevice returns the device wrapped by the watchdog, if the given
+// device is a watchdog, so that the interfaces implemented by the wrapped
+// device can be checked.
+func unwrapDevice(raw RawDevice) RawDevice {
+	if wd, ok := raw.(*watchdogDevice); ok {
+		return wd.device
+	}
+
+	return raw
+}
+
+func (wd *watchdogDevice) RunCommand(command string) RawResult {
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+
+	res := wd.device.RunCommand(command)
+
+	if !res.Failed() || !IsTimeoutError(res.GetError()) {
+		wd.timeouts = 0
+
+		return res
+	}
+
+	wd.timeouts++
+
+	if wd.timeouts < wd.maxTimeouts {
+		return res
+	}
+
+	if !wd.recover() {
+		return res
+	}
+
+	wd.timeouts = 0
+
+	if wd.onRetry != nil {
+		wd.onRetry()
+	}
+
+	return wd.device.RunCommand(command)
+}
+
+func (wd *watchdogDevice) Close() error {
+	if closer, ok := wd.device.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// keepSetting remembers the given setting the device accepted, to apply it
+// again after the device has been restarted.
+func (wd *watchdogDevice) keepSetting(command string) {
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+
+	for _, setting := range wd.settings {
+		if setting == command {
+			return
+		}
+	}
+
+	wd.settings = append(wd.settings, command)
+}
+
+// recover runs the escalation steps until the device identifies itself, it
+// returns whether the device recovered.
+func (wd *watchdogDevice) recover() bool {
+	for i, step := range watchdogSteps {
+		err := step.run(wd.device)
+
+		if err == nil {
+			err = wd.probe()
+		}
+
+		wd.notify(step.name, err == nil, err)
+
+		if err != nil {
+			continue
+		}
+
+		// Only the interrupt keeps the settings of the device
+		if i > 0 && len(wd.settings) > 0 {
+			wd.notify("restore settings", true, wd.restoreSettings())
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// restoreSettings applies the kept settings again, returning the error of the
+// first one the device did not accept.
+func (wd *watchdogDevice) restoreSettings() error {
+	var firstErr error
+
+	for _, setting := range wd.settings {
+		res := wd.device.RunCommand(setting)
+		err := res.GetError()
+
+		if err == nil && !hasOutput(res.GetOutputs(), "OK") {
+			err = fmt.Errorf("device did not accept %s: %q", setting, res.GetOutputs())
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// notify calls the callback, if any, with the given step.
+func (wd *watchdogDevice) notify(step string, recovered bool, err error) {
+	if wd.callback == nil {
+		return
+	}
+
+	wd.callback(WatchdogEvent{
+		Timeouts:  wd.timeouts,
+		Step:      step,
+		Recovered: recovered,
+		Err:       err,
+	})
+}
+
+// probe checks that the device responds by asking it to identify itself.
+func (wd *watchdogDevice) probe() error {
+	res := wd.device.RunCommand("ATI")
+
+	if res.Failed() {
+		return res.GetError()
+	}
+
+	for _, out := range res.GetOutputs() {
+		if strings.HasPrefix(out, "ELM327") {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("device did not identify itself as ELM327: %v", res.GetOutputs())
+}
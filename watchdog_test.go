@@ -0,0 +1,159 @@
+package elmobd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// hungDevice is a RecoverableDevice that times out until one of its
+// recovery steps is run that has been marked as working.
+type hungDevice struct {
+	MockDevice
+	hung     bool
+	fixedBy  string
+	steps    []string
+	commands []string
+	lenient  bool
+	baud     int
+}
+
+func (dev *hungDevice) RunCommand(command string) RawResult {
+	dev.commands = append(dev.commands, command)
+
+	if dev.hung {
+		return &MockResult{input: command, error: io.EOF}
+	}
+
+	if command == "ATI" {
+		return &MockResult{input: command, outputs: []string{"ELM327 v1.5"}}
+	}
+
+	return dev.MockDevice.RunCommand(command)
+}
+
+func (dev *hungDevice) step(name string) error {
+	dev.steps = append(dev.steps, name)
+
+	if name == dev.fixedBy {
+		dev.hung = false
+	}
+
+	return nil
+}
+
+func (dev *hungDevice) SetLenient(lenient bool) { dev.lenient = lenient }
+
+func (dev *hungDevice) SetBaudRate(baud int) error {
+	dev.baud = baud
+
+	return nil
+}
+
+func (dev *hungDevice) Interrupt() error { return dev.step("interrupt") }
+func (dev *hungDevice) WarmStart() error { return dev.step("warm start") }
+func (dev *hungDevice) Reset() error     { return dev.step("reset") }
+func (dev *hungDevice) Reopen() error    { return dev.step("reopen") }
+
+func TestWatchdogEscalation(t *testing.T) {
+	raw := &hungDevice{hung: true, fixedBy: "reset"}
+	dev := &Device{rawDevice: raw}
+
+	var events []WatchdogEvent
+
+	assertSuccess(t, dev.EnableWatchdog(2, func(event WatchdogEvent) {
+		events = append(events, event)
+	}))
+
+	_, err := dev.RunOBDCommand(NewVehicleSpeed())
+
+	assert(t, IsTimeoutError(err), "Expected first command to time out")
+	assertEqual(t, len(raw.steps), 0)
+
+	speed, err := Run(dev, NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, speed.Value, uint32(75))
+	assertEqual(t, len(events), 3)
+	assertEqual(t, events[0].Step, "interrupt")
+	assertEqual(t, events[0].Recovered, false)
+	assertEqual(t, events[2].Step, "reset")
+	assertEqual(t, events[2].Recovered, true)
+	assertEqual(t, events[2].Timeouts, 2)
+	assertEqual(t, len(raw.steps), 3)
+	assertEqual(t, dev.Stats().Retries, uint64(1))
+	assertEqual(t, dev.Stats().CommandsRun, uint64(2))
+}
+
+func TestWatchdogGivesUp(t *testing.T) {
+	raw := &hungDevice{hung: true}
+	dev := &Device{rawDevice: raw}
+
+	assertSuccess(t, dev.EnableWatchdog(1, nil))
+
+	_, err := dev.RunOBDCommand(NewVehicleSpeed())
+
+	assert(t, errors.Is(err, io.EOF), "Expected the timeout to be returned")
+	assertEqual(t, len(raw.steps), 4)
+	assertEqual(t, raw.steps[3], "reopen")
+}
+
+func TestWatchdogRequiresRecoverableDevice(t *testing.T) {
+	dev := &Device{rawDevice: &MockDevice{}}
+
+	assert(t, dev.EnableWatchdog(3, nil) != nil, "Expected mock device to be rejected")
+
+	recoverable := &Device{rawDevice: &hungDevice{}}
+
+	assertSuccess(t, recoverable.EnableWatchdog(3, nil))
+	assert(t, recoverable.EnableWatchdog(3, nil) != nil, "Expected enabling twice to fail")
+}
+
+func TestWatchdogKeepsDeviceSettings(t *testing.T) {
+	raw := &hungDevice{fixedBy: "reset"}
+	dev := &Device{rawDevice: raw}
+
+	assertSuccess(t, dev.EnableWatchdog(1, nil))
+	assertSuccess(t, dev.SetLenient(true))
+	assertEqual(t, raw.lenient, true)
+
+	_, err := dev.FastMode(115200)
+
+	assertSuccess(t, err)
+	assertEqual(t, raw.baud, 115200)
+}
+
+func TestWatchdogRestoresSettings(t *testing.T) {
+	raw := &hungDevice{fixedBy: "reset"}
+	dev := &Device{rawDevice: raw}
+
+	var events []WatchdogEvent
+
+	assertSuccess(t, dev.EnableWatchdog(1, func(event WatchdogEvent) {
+		events = append(events, event)
+	}))
+
+	_, err := dev.FastMode(0)
+
+	assertSuccess(t, err)
+
+	raw.hung = true
+	raw.commands = nil
+
+	_, err = Run(dev, NewVehicleSpeed())
+
+	assertSuccess(t, err)
+	assertEqual(t, len(events), 4)
+	assertEqual(t, events[3].Step, "restore settings")
+	assertSuccess(t, events[3].Err)
+	assertEqual(
+		t,
+		fmt.Sprint(raw.commands[len(raw.commands)-5:]),
+		"[ATE0 ATL0 ATS0 ATAT2 010D1]",
+	)
+}
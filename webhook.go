@@ -0,0 +1,320 @@
+package elmobd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*==============================================================================
+ * External
+ */
+
+// ErrWebhookStopped is returned by WebhookSink.Publish once Run is stopping.
+var ErrWebhookStopped = errors.New("webhook sink is stopped")
+
+// WebhookError is given to OnDrop when a batch could not be delivered because
+// the endpoint answered with an error status.
+type WebhookError struct {
+	StatusCode int
+	Status     string
+}
+
+func (err *WebhookError) Error() string {
+	return fmt.Sprintf("webhook answered %s", err.Status)
+}
+
+// WebhookSink delivers processed commands as readings to an HTTP endpoint,
+// such as the ingestion API of your own cloud. The readings are sent in
+// batches, as a POST request with a JSON array of readings as body:
+//
+//	[{"key":"engine_rpm","value":2412.5,"unit":"rpm","timestamp":"..."}]
+//
+// A batch is sent when it has BatchSize readings or FlushInterval has passed,
+// a FlushInterval that is not positive is taken as the default of 5 s.
+// Batches failing because of the network, a 5xx status or a 429 status are
+// retried MaxRetries times, waiting RetryDelay before the first retry and
+// twice as long before each following one. Batches that can not be delivered
+// are given to OnDrop.
+//
+// The readings are queued while a batch is delivered. When the queue is full
+// Publish blocks until there is room again, which slows down the loop polling
+// the device instead of using ever more memory while the endpoint is down.
+//
+// Use it like this:
+//
+//	sink := elmobd.NewWebhookSink("https://example.com/readings", 1000)
+//
+//	go sink.Run(ctx)
+//
+//	err := sink.Publish(cmd)
+//
+// It is safe to use from multiple goroutines.
+type WebhookSink struct {
+	// URL is the endpoint the batches are posted to.
+	URL string
+	// BatchSize is the maximum amount of readings sent in a batch.
+	BatchSize int
+	// FlushInterval is how long a reading waits for a batch to fill up.
+	FlushInterval time.Duration
+	// MaxRetries is how many times a failed batch is sent again.
+	MaxRetries int
+	// RetryDelay is how long to wait before the first retry.
+	RetryDelay time.Duration
+	// Client is the HTTP client sending the batches.
+	Client *http.Client
+	// OnDrop, if set, is called with the batches that could not be
+	// delivered.
+	OnDrop    func(readings []Reading, err error)
+	queue     chan Reading
+	mutex     sync.Mutex
+	stopping  bool
+	stop      chan struct{}
+	publishes sync.WaitGroup
+	now       func() time.Time
+	sleep     func(ctx context.Context, d time.Duration)
+}
+
+// NewWebhookSink creates a new WebhookSink posting to the given URL, queueing
+// up to the given amount of readings, with batches of 50 readings flushed at
+// least every 5 s and 3 retries starting 1 s apart.
+func NewWebhookSink(url string, queueSize int) *WebhookSink {
+	return &WebhookSink{
+		URL:           url,
+		BatchSize:     50,
+		FlushInterval: webhookFlushInterval,
+		MaxRetries:    3,
+		RetryDelay:    time.Second,
+		Client:        &http.Client{Timeout: 10 * time.Second},
+		queue:         make(chan Reading, queueSize),
+		stop:          make(chan struct{}),
+		now:           time.Now,
+		sleep:         sleepContext,
+	}
+}
+
+// Publish queues the value of the given processed command to be delivered,
+// timestamped with the current time. It blocks while the queue is full, see
+// WebhookSink.
+func (sink *WebhookSink) Publish(cmd OBDCommand) error {
+	return sink.PublishReading(NewReading(cmd, sink.now()))
+}
+
+// PublishMany queues all the given processed commands, such as the result of
+// RunManyOBDCommands, using the same timestamp for all readings.
+func (sink *WebhookSink) PublishMany(commands []OBDCommand) error {
+	now := sink.now()
+
+	for _, cmd := range commands {
+		if err := sink.PublishReading(NewReading(cmd, now)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PublishReading queues the given reading to be delivered. It blocks while
+// the queue is full, see WebhookSink.
+func (sink *WebhookSink) PublishReading(reading Reading) error {
+	sink.mutex.Lock()
+
+	if sink.stopping {
+		sink.mutex.Unlock()
+
+		return ErrWebhookStopped
+	}
+
+	// Run waits for the readings being queued before draining the queue
+	sink.publishes.Add(1)
+	sink.mutex.Unlock()
+
+	defer sink.publishes.Done()
+
+	select {
+	case sink.queue <- reading:
+		return nil
+	case <-sink.stop:
+		return ErrWebhookStopped
+	}
+}
+
+// Run delivers the queued readings until the given context is done, which is
+// not treated as an error. The readings still queued by then are delivered
+// once more without retrying, before Run returns.
+//
+// Run must only be called once, Publish returns ErrWebhookStopped once the
+// context is done.
+func (sink *WebhookSink) Run(ctx context.Context) error {
+	interval := sink.FlushInterval
+
+	if interval <= 0 {
+		interval = webhookFlushInterval
+	}
+
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	batch := []Reading{}
+
+	for {
+		flush := false
+
+		select {
+		case <-ctx.Done():
+			sink.stopPublishing()
+			sink.drain(batch)
+
+			return nil
+		case reading := <-sink.queue:
+			batch = append(batch, reading)
+			flush = len(batch) >= sink.BatchSize
+		case <-ticker.C:
+			flush = len(batch) > 0
+		}
+
+		if flush {
+			batch = sink.deliver(ctx, batch)
+		}
+	}
+}
+
+/*==============================================================================
+ * Internal
+ */
+
+// webhookFlushInterval is the default FlushInterval of a WebhookSink.
+const webhookFlushInterval = 5 * time.Second
+
+// stopPublishing makes Publish fail with ErrWebhookStopped, and waits for the
+// readings being queued, so that the queue can be drained.
+func (sink *WebhookSink) stopPublishing() {
+	sink.mutex.Lock()
+	sink.stopping = true
+	sink.mutex.Unlock()
+
+	close(sink.stop)
+
+	sink.publishes.Wait()
+}
+
+// deliver posts the given batch, retrying as described in WebhookSink. The
+// batch is returned when the given context is done before it was delivered,
+// so that Run delivers it while draining the queue, and an empty batch
+// otherwise.
+func (sink *WebhookSink) deliver(ctx context.Context, batch []Reading) []Reading {
+	delay := sink.RetryDelay
+	err := sink.post(ctx, batch)
+
+	for retry := 0; retry < sink.MaxRetries && webhookRetryable(err) && ctx.Err() == nil; retry++ {
+		sink.sleep(ctx, delay)
+		delay *= 2
+
+		err = sink.post(ctx, batch)
+	}
+
+	if err != nil && ctx.Err() != nil {
+		return batch
+	}
+
+	if err != nil && sink.OnDrop != nil {
+		sink.OnDrop(batch, err)
+	}
+
+	return []Reading{}
+}
+
+// drain delivers the given batch and the readings left in the queue once,
+// after Run has been stopped.
+func (sink *WebhookSink) drain(batch []Reading) {
+	for drained := false; !drained; {
+		select {
+		case reading := <-sink.queue:
+			batch = append(batch, reading)
+		default:
+			drained = true
+		}
+	}
+
+	for len(batch) > 0 {
+		size := sink.BatchSize
+
+		if size <= 0 || size > len(batch) {
+			size = len(batch)
+		}
+
+		err := sink.post(context.Background(), batch[:size])
+
+		if err != nil && sink.OnDrop != nil {
+			sink.OnDrop(batch[:size], err)
+		}
+
+		batch = batch[size:]
+	}
+}
+
+// post sends the given batch once.
+func (sink *WebhookSink) post(ctx context.Context, batch []Reading) error {
+	body, err := json.Marshal(batch)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := sink.Client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return &WebhookError{res.StatusCode, res.Status}
+	}
+
+	return nil
+}
+
+// webhookRetryable checks if a batch failing with the given error is worth
+// sending again, which is not the case when the endpoint rejects it.
+func webhookRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var webhookErr *WebhookError
+
+	if errors.As(err, &webhookErr) {
+		return webhookErr.StatusCode >= 500 || webhookErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// sleepContext waits for the given duration, or until the given context is
+// done.
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,208 @@
+package elmobd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+/*==============================================================================
+ * Tests
+ */
+
+// webhookEndpoint records the batches posted to it, answering with the given
+// statuses in turn and with 200 OK once they are used up.
+type webhookEndpoint struct {
+	mutex    sync.Mutex
+	statuses []int
+	batches  [][]Reading
+	posts    int
+}
+
+func (endpoint *webhookEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint.mutex.Lock()
+	defer endpoint.mutex.Unlock()
+
+	endpoint.posts++
+
+	if len(endpoint.statuses) > 0 {
+		status := endpoint.statuses[0]
+		endpoint.statuses = endpoint.statuses[1:]
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+
+			return
+		}
+	}
+
+	var batch []Reading
+
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	endpoint.batches = append(endpoint.batches, batch)
+}
+
+func newTestWebhookSink(url string, queueSize int) *WebhookSink {
+	sink := NewWebhookSink(url, queueSize)
+
+	sink.sleep = func(ctx context.Context, d time.Duration) {}
+
+	return sink
+}
+
+func TestWebhookSinkBatches(t *testing.T) {
+	endpoint := &webhookEndpoint{}
+	server := httptest.NewServer(endpoint)
+
+	defer server.Close()
+
+	sink := newTestWebhookSink(server.URL, 10)
+	sink.BatchSize = 2
+	sink.FlushInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		sink.Run(ctx)
+		close(stopped)
+	}()
+
+	assertSuccess(t, sink.PublishMany([]OBDCommand{NewVehicleSpeed(), NewEngineRPM()}))
+	assertSuccess(t, sink.Publish(NewCoolantTemperature()))
+
+	// The last reading is delivered when stopping
+	cancel()
+	<-stopped
+
+	assertEqual(t, len(endpoint.batches), 2)
+	assertEqual(t, len(endpoint.batches[0]), 2)
+	assertEqual(t, endpoint.batches[0][1].Key, "engine_rpm")
+	assertEqual(t, endpoint.batches[1][0].Key, "coolant_temperature")
+	assertEqual(t, sink.Publish(NewVehicleSpeed()), ErrWebhookStopped)
+}
+
+func TestWebhookSinkRetries(t *testing.T) {
+	type scenario struct {
+		statuses []int
+		posts    int
+		dropped  int
+	}
+
+	scenarios := []scenario{
+		{[]int{http.StatusServiceUnavailable, http.StatusTooManyRequests}, 3, 0},
+		{[]int{500, 500, 500, 500}, 4, 1},
+		{[]int{http.StatusBadRequest}, 1, 1},
+	}
+
+	for _, scen := range scenarios {
+		endpoint := &webhookEndpoint{statuses: scen.statuses}
+		server := httptest.NewServer(endpoint)
+
+		sink := newTestWebhookSink(server.URL, 10)
+		dropped := 0
+
+		sink.OnDrop = func(readings []Reading, err error) {
+			var webhookErr *WebhookError
+
+			assert(t, errors.As(err, &webhookErr), "Expected webhook error")
+
+			dropped += len(readings)
+		}
+
+		batch := sink.deliver(context.Background(), []Reading{AsReading(NewVehicleSpeed())})
+
+		assertEqual(t, len(batch), 0)
+		assertEqual(t, endpoint.posts, scen.posts)
+		assertEqual(t, dropped, scen.dropped)
+
+		server.Close()
+	}
+}
+
+func TestWebhookSinkBackpressure(t *testing.T) {
+	sink := newTestWebhookSink("http://127.0.0.1:0", 1)
+
+	assertSuccess(t, sink.Publish(NewVehicleSpeed()))
+
+	published := make(chan error)
+
+	go func() {
+		published <- sink.Publish(NewEngineRPM())
+	}()
+
+	select {
+	case <-published:
+		t.Fatal("Expected publishing to a full queue to block")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	<-sink.queue
+
+	assertSuccess(t, <-published)
+}
+
+func TestWebhookSinkStopping(t *testing.T) {
+	endpoint := &webhookEndpoint{}
+	server := httptest.NewServer(endpoint)
+
+	defer server.Close()
+
+	sink := newTestWebhookSink(server.URL, 1)
+	sink.FlushInterval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		sink.Run(ctx)
+		close(stopped)
+	}()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+
+	published := 0
+
+	// Every reading queued while stopping is either delivered or refused
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := sink.Publish(NewVehicleSpeed())
+
+			if err == nil {
+				mutex.Lock()
+				published++
+				mutex.Unlock()
+			} else {
+				assertEqual(t, err, ErrWebhookStopped)
+			}
+		}()
+	}
+
+	cancel()
+	wg.Wait()
+	<-stopped
+
+	delivered := 0
+
+	for _, batch := range endpoint.batches {
+		delivered += len(batch)
+	}
+
+	assertEqual(t, delivered, published)
+	assertEqual(t, sink.Publish(NewVehicleSpeed()), ErrWebhookStopped)
+}